@@ -11,6 +11,7 @@ import (
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc"
@@ -36,6 +37,12 @@ func main() {
 	fmt.Printf("Build version: %s\nBuild date: %s\nBuild commit: %s\n", buildVersion, buildDate, buildCommit)
 
 	logger.Newlogger()
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate-data" {
+		os.Exit(runMigrateData(os.Args[2:]))
+	}
 	log.Info().Msg("Start program")
 	cnfg, err := config.NewConfig()
 	if err != nil {
@@ -44,10 +51,14 @@ func main() {
 	strg := storage.NewStorage(cnfg)
 	log.Debug().Msg("storage init")
 	deletingWorker := worker.NewWorker()
-	hndlr := handler.NewHandler(cnfg, strg, deletingWorker)
+	clickRecorder := worker.NewClickRecorder(cnfg.ClicksQueueSize)
+	hndlr := handler.NewHandler(cnfg, strg, deletingWorker, clickRecorder)
 	router := router.NewRouter(hndlr)
 	log.Debug().Msg("handler init")
 	deletingWorker.Run(strg, cnfg.DeletingBufferSize, cnfg.DeletingBufferTimeout)
+	expiringReaper := worker.NewReaper()
+	expiringReaper.Run(strg, cnfg.ExpireInterval, time.Duration(cnfg.RetentionDays)*24*time.Hour)
+	clickRecorder.Run(strg, cnfg.ClicksBufferSize, cnfg.ClicksBufferTimeout)
 	srv := http.Server{
 		Addr:    cnfg.ServerAddress,
 		Handler: router,
@@ -69,7 +80,7 @@ func main() {
 			}
 		}
 	}()
-	gRPCconf := pb.NewShortURLsServer(cnfg, strg, deletingWorker)
+	gRPCconf := pb.NewShortURLsServer(cnfg, strg, deletingWorker, clickRecorder)
 	gRPCaddr, _, _ := strings.Cut(cnfg.ServerAddress, ":")
 	listen, err := net.Listen("tcp", gRPCaddr+":3200")
 	if err != nil {
@@ -90,6 +101,8 @@ func main() {
 	<-sigChan
 	log.Info().Msgf("OS cmd received stop signal")
 	deletingWorker.Stop()
+	expiringReaper.Stop()
+	clickRecorder.Stop()
 	strg.CloseDB()
 	if err := srv.Shutdown(context.Background()); err != nil {
 		log.Error().Msgf("HTTP server Shutdown: %s", err)
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"shortURL/internal/config"
+	"shortURL/internal/storage/migrations"
+)
+
+const migrateUsage = "usage: shortener migrate up|down|status [flags]"
+
+// runMigrate функция выполняет команду migrate и возвращает код завершения программы.
+// Параметры подключения к базе данных задаются так же, как для запуска сервиса.
+func runMigrate(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+	command := args[0]
+	// флаги после названия команды разбираются config.NewConfig
+	os.Args = append([]string{os.Args[0]}, args[1:]...)
+	cnfg, err := config.NewConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "config error:", err)
+		return 1
+	}
+	if cnfg.DatabaseDSN == "" {
+		fmt.Fprintln(os.Stderr, "database DSN is not set: use DATABASE_DSN or -d flag")
+		return 1
+	}
+	db, err := sql.Open("pgx", cnfg.DatabaseDSN)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "open database error:", err)
+		return 1
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	switch command {
+	case "up":
+		applied, err := migrations.Up(ctx, db)
+		for _, m := range applied {
+			fmt.Printf("applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Println("no migrations to apply")
+		}
+	case "down":
+		m, err := migrations.Down(ctx, db)
+		if errors.Is(err, migrations.ErrNoMigrations) {
+			fmt.Println("no migrations to revert")
+			return 0
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Printf("reverted %04d_%s\n", m.Version, m.Name)
+	case "status":
+		states, err := migrations.Status(ctx, db)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		for _, s := range states {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			fmt.Printf("%04d_%-32s %s\n", s.Version, s.Name, applied)
+		}
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+	return 0
+}
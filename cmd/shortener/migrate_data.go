@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"shortURL/internal/config"
+	"shortURL/internal/storage"
+	"shortURL/internal/transfer"
+)
+
+const migrateDataUsage = `usage: shortener migrate-data -from SOURCE -to TARGET [flags]
+storages: file:PATH, postgres://DSN`
+
+// runMigrateData функция выполняет команду migrate-data и возвращает код завершения программы.
+// Команда переносит все записи коротких ссылок, включая владельца и отметку об удалении, между хранилищами.
+func runMigrateData(args []string) int {
+	flags := flag.NewFlagSet("migrate-data", flag.ContinueOnError)
+	from := flags.String("from", "", "Исходное хранилище")
+	to := flags.String("to", "", "Целевое хранилище")
+	dryRun := flags.Bool("dry-run", false, "Оценить перенос без записи в целевое хранилище")
+	progress := flags.String("progress", "", "Файл прогресса для продолжения прерванного переноса")
+	batch := flags.Int("batch", transfer.DefaultBatchSize, "Количество записей, переносимых за одно обращение к хранилищу")
+	sample := flags.Int("sample", transfer.DefaultSampleSize, "Количество случайных записей для сверки")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, migrateDataUsage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *from == "" || *to == "" {
+		flags.Usage()
+		return 2
+	}
+	if *from == *to {
+		fmt.Fprintln(os.Stderr, "source and target are the same storage")
+		return 2
+	}
+
+	src, err := openRecordStorage(*from, true)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "open source:", err)
+		return 1
+	}
+	defer src.CloseDB()
+	dst, err := openRecordStorage(*to, false)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "open target:", err)
+		return 1
+	}
+	defer dst.CloseDB()
+
+	report, err := transfer.Run(context.Background(), src, dst, transfer.Options{
+		Source:       *from,
+		Target:       *to,
+		BatchSize:    *batch,
+		SampleSize:   *sample,
+		DryRun:       *dryRun,
+		ProgressPath: *progress,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	report.Print(os.Stdout)
+	if !report.OK() {
+		return 1
+	}
+	return 0
+}
+
+// openRecordStorage функция открывает хранилище по его описанию: file:PATH или строка подключения PostgreSQL.
+// Исходный файл хранилища должен существовать, целевой создается при необходимости.
+// Хранилище в памяти отклоняется: как источник оно всегда пусто, а как цель теряется при завершении команды.
+func openRecordStorage(spec string, source bool) (storage.RecordStorager, error) {
+	cfg := &config.Config{KeyGenerator: config.KeyRandom, KeyLength: 8, FileSync: config.SyncAlways}
+	switch {
+	case spec == "memory":
+		return nil, errors.New("memory storage is not persistent and cannot be migrated")
+	case strings.HasPrefix(spec, "file:"):
+		cfg.FileStoragePath = strings.TrimPrefix(spec, "file:")
+		if source {
+			if _, err := os.Stat(cfg.FileStoragePath); err != nil {
+				return nil, err
+			}
+		}
+		cfg.FileSyncInterval = time.Second
+		return storage.NewFileStorager(cfg, storage.NewKeyGenerator(cfg)), nil
+	case strings.HasPrefix(spec, "postgres://"), strings.HasPrefix(spec, "postgresql://"):
+		cfg.DatabaseDSN = spec
+		return storage.NewSQLStorager(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown storage %q: %s", spec, migrateDataUsage)
+	}
+}
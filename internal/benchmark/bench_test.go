@@ -41,9 +41,11 @@ func BenchmarkRouter(b *testing.B) {
 	}
 	storage := storage.NewStorage(cnfg)
 	deletingWorker := worker.NewWorker()
-	handlers := handler.NewHandler(cnfg, storage, deletingWorker)
+	clickRecorder := worker.NewClickRecorder(cnfg.ClicksQueueSize)
+	handlers := handler.NewHandler(cnfg, storage, deletingWorker, clickRecorder)
 	router := NewRouter(handlers)
 	deletingWorker.Run(storage, cnfg.DeletingBufferSize, cnfg.DeletingBufferTimeout)
+	clickRecorder.Run(storage, cnfg.ClicksBufferSize, cnfg.ClicksBufferTimeout)
 	listener, err := net.Listen("tcp", cnfg.ServerAddress)
 	if err != nil {
 		log.Fatal().Err(err).Msg("net.Listen error")
@@ -0,0 +1,82 @@
+package bencmark
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"shortURL/internal/config"
+	"shortURL/internal/storage"
+)
+
+// record повторяет формат записи файлового хранилища.
+type record struct {
+	UserID string `json:"ID"`
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+}
+
+// openPerWrite воспроизводит прежнюю запись в файл хранилища: открытие, запись одной строки и закрытие на каждый запрос.
+type openPerWrite struct {
+	path string
+	sync.Mutex
+}
+
+func (w *openPerWrite) write(rec record) error {
+	w.Lock()
+	defer w.Unlock()
+	file, err := os.OpenFile(w.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0777)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return json.NewEncoder(file).Encode(&rec)
+}
+
+func BenchmarkFileStorageWrite(b *testing.B) {
+	zerolog.SetGlobalLevel(zerolog.FatalLevel)
+
+	b.Run("open per write", func(b *testing.B) {
+		w := openPerWrite{path: filepath.Join(b.TempDir(), "storage.json")}
+		var n int64
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				i := strconv.FormatInt(atomic.AddInt64(&n, 1), 10)
+				if err := w.write(record{UserID: "user1", Key: i, Value: "https://go.dev/" + i}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	})
+
+	for _, policy := range []string{config.SyncNever, config.SyncInterval, config.SyncAlways} {
+		b.Run("group commit "+policy, func(b *testing.B) {
+			cfg := &config.Config{
+				BaseURL:          "http://127.0.0.1:8080",
+				FileStoragePath:  filepath.Join(b.TempDir(), "storage.json"),
+				KeyGenerator:     config.KeyCounter,
+				FileSync:         policy,
+				FileSyncInterval: time.Second,
+			}
+			strg := storage.NewFileStorager(cfg, storage.NewKeyGenerator(cfg))
+			defer strg.CloseDB()
+			var n int64
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					i := strconv.FormatInt(atomic.AddInt64(&n, 1), 10)
+					if _, err := strg.SetShortURL(context.Background(), "https://go.dev/"+i, "user1", storage.URLOptions{}, cfg); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
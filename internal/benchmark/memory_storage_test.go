@@ -0,0 +1,189 @@
+package bencmark
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"shortURL/internal/config"
+	"shortURL/internal/storage"
+)
+
+// memoryStore - операции хранилища в памяти, по которым сравниваются прежняя и текущая реализации.
+type memoryStore interface {
+	set(fURL, userID string) (string, error)
+	get(key string) (string, error)
+	userURLs(userID string) int
+	stats() int
+}
+
+// singleLock воспроизводит прежнее хранилище в памяти: параллельные карты под одной блокировкой,
+// список адресов пользователя и статистика получаются перебором всех адресов.
+type singleLock struct {
+	baseURL map[string]string
+	userURL map[string]string
+	keyURL  map[string]string
+	n       uint64
+	sync.RWMutex
+}
+
+func newSingleLock() *singleLock {
+	return &singleLock{baseURL: make(map[string]string), userURL: make(map[string]string), keyURL: make(map[string]string)}
+}
+
+func (s *singleLock) set(fURL, userID string) (string, error) {
+	s.Lock()
+	defer s.Unlock()
+	if key, ok := s.keyURL[userID+"\x00"+fURL]; ok {
+		return key, storage.ErrConflict
+	}
+	s.n++
+	key := strconv.FormatUint(s.n, 36)
+	s.baseURL[key] = fURL
+	s.userURL[key] = userID
+	s.keyURL[userID+"\x00"+fURL] = key
+	return key, nil
+}
+
+func (s *singleLock) get(key string) (string, error) {
+	s.RLock()
+	defer s.RUnlock()
+	fURL, ok := s.baseURL[key]
+	if !ok {
+		return "", storage.ErrNoContent
+	}
+	return fURL, nil
+}
+
+func (s *singleLock) userURLs(userID string) int {
+	s.RLock()
+	defer s.RUnlock()
+	n := 0
+	for key := range s.baseURL {
+		if s.userURL[key] == userID {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *singleLock) stats() int {
+	s.RLock()
+	defer s.RUnlock()
+	users := make(map[string]bool)
+	for _, v := range s.userURL {
+		users[v] = true
+	}
+	return len(users)
+}
+
+// sharded вызывает текущую реализацию хранилища в памяти.
+type sharded struct {
+	strg *storage.MemoryStorage
+	cfg  *config.Config
+}
+
+func newSharded() *sharded {
+	cfg := &config.Config{KeyGenerator: config.KeyCounter}
+	return &sharded{strg: storage.NewMemoryStorager(storage.NewKeyGenerator(cfg)), cfg: cfg}
+}
+
+func (s *sharded) set(fURL, userID string) (string, error) {
+	shortURL, err := s.strg.SetShortURL(context.Background(), fURL, userID, storage.URLOptions{}, s.cfg)
+	return shortURL[1:], err
+}
+
+func (s *sharded) get(key string) (string, error) {
+	return s.strg.RetFullURL(context.Background(), key)
+}
+
+func (s *sharded) userURLs(userID string) int {
+	urls, _ := s.strg.ReturnAllURLs(context.Background(), userID, storage.ListOptions{}, s.cfg)
+	return len(urls.URLs)
+}
+
+func (s *sharded) stats() int {
+	stats, _ := s.strg.ReturnStats(context.Background(), time.Time{})
+	return stats.Users
+}
+
+const (
+	benchUsers   = 1000
+	benchPreload = 100000
+)
+
+// preload заполняет хранилище адресами, равномерно распределенными между пользователями, и возвращает их ключи.
+func preload(b *testing.B, s memoryStore) []string {
+	keys := make([]string, 0, benchPreload)
+	for i := 0; i < benchPreload; i++ {
+		key, err := s.set("https://go.dev/"+strconv.Itoa(i), "user"+strconv.Itoa(i%benchUsers))
+		if err != nil {
+			b.Fatal(err)
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// BenchmarkMemoryStorage сравнивает прежнюю и текущую реализации хранилища в памяти под параллельной нагрузкой:
+// переходы по ссылкам, сокращение новых адресов и получение списков адресов пользователей.
+func BenchmarkMemoryStorage(b *testing.B) {
+	zerolog.SetGlobalLevel(zerolog.FatalLevel)
+	impls := []struct {
+		name string
+		new  func() memoryStore
+	}{
+		{name: "single lock", new: func() memoryStore { return newSingleLock() }},
+		{name: "sharded", new: func() memoryStore { return newSharded() }},
+	}
+	for _, impl := range impls {
+		b.Run(impl.name+"/mixed", func(b *testing.B) {
+			s := impl.new()
+			keys := preload(b, s)
+			var n int64
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					i := atomic.AddInt64(&n, 1)
+					switch {
+					case i%100 == 0:
+						s.userURLs("user" + strconv.FormatInt(i%benchUsers, 10))
+					case i%10 == 0:
+						if _, err := s.set("https://go.dev/new/"+strconv.FormatInt(i, 10), "user"+strconv.FormatInt(i%benchUsers, 10)); err != nil {
+							b.Fatal(err)
+						}
+					default:
+						if _, err := s.get(keys[i%benchPreload]); err != nil {
+							b.Fatal(err)
+						}
+					}
+				}
+			})
+		})
+		b.Run(impl.name+"/user urls", func(b *testing.B) {
+			s := impl.new()
+			preload(b, s)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if s.userURLs("user"+strconv.Itoa(i%benchUsers)) != benchPreload/benchUsers {
+					b.Fatal("wrong user URLs count")
+				}
+			}
+		})
+		b.Run(impl.name+"/stats", func(b *testing.B) {
+			s := impl.new()
+			preload(b, s)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if s.stats() != benchUsers {
+					b.Fatal("wrong users count")
+				}
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"encoding/json"
 	"encoding/pem"
 	"flag"
+	"fmt"
 	"math/big"
 	"net"
 	"os"
@@ -34,6 +35,76 @@ const (
 	SaveSQL
 )
 
+// Режимы генерации ключей коротких ссылок.
+const (
+	KeyRandom  = "random"
+	KeyCounter = "counter"
+	KeyHash    = "hash"
+)
+
+// Параметры генерации ключей по умолчанию.
+const (
+	defaultKeyGenerator = KeyRandom
+	defaultKeyLength    = 8
+	maxKeyLength        = 32
+)
+
+// defaultExpireInterval - интервал проверки просроченных адресов по умолчанию.
+const defaultExpireInterval = time.Minute
+
+// Форматы записей файлового хранилища.
+const (
+	FormatJSON   = "json"
+	FormatBinary = "binary"
+)
+
+// Режимы сохранения на диск записей файлового хранилища.
+const (
+	SyncAlways   = "always"
+	SyncInterval = "interval"
+	SyncNever    = "never"
+)
+
+// Параметры сохранения на диск записей файлового хранилища по умолчанию.
+const (
+	defaultFileSync         = SyncInterval
+	defaultFileSyncInterval = time.Second
+)
+
+// Ограничения времени выполнения операций хранилища по умолчанию.
+const (
+	defaultStorageReadTimeout  = 3 * time.Second
+	defaultStorageWriteTimeout = 5 * time.Second
+)
+
+// Параметры пула соединений с базой данных по умолчанию.
+const (
+	defaultDBMaxConns           = 10
+	defaultDBMaxConnLifetime    = time.Hour
+	defaultDBHealthCheckPeriod  = time.Minute
+	defaultDBStatementCacheSize = 512
+)
+
+// Время хранения записей кэша коротких ссылок по умолчанию.
+// Отсутствующие ключи хранятся меньше, чтобы созданная ссылка быстрее становилась доступной на других экземплярах сервиса.
+const (
+	defaultCacheTTL         = time.Minute
+	defaultCacheNegativeTTL = 5 * time.Second
+)
+
+// Время, в течение которого владелец может восстановить удаленную ссылку, по умолчанию.
+const defaultRestoreWindow = 24 * time.Hour
+
+// Окно, за которое считается количество созданных пользователем ссылок, по умолчанию.
+const defaultQuotaWindow = 24 * time.Hour
+
+// Параметры сжатия файлового хранилища по умолчанию.
+const (
+	defaultCompactSize     = 64 << 20
+	defaultCompactRatio    = 2
+	defaultCompactInterval = time.Minute
+)
+
 // Config хранит основные параметры конфигурации сервиса.
 type Config struct {
 	ServerAddress         string        `env:"SERVER_ADDRESS" json:"server_address"`
@@ -43,9 +114,37 @@ type Config struct {
 	EnableHTTPS           bool          `env:"ENABLE_HTTPS" json:"enable_https"`
 	Config                string        `env:"CONFIG" json:"-"`
 	TrustedSubnet         string        `env:"TRUSTED_SUBNET" json:"trusted_subnet"`
+	KeyGenerator          string        `env:"KEY_GENERATOR" json:"key_generator"`
+	KeyLength             int           `env:"KEY_LENGTH" json:"key_length"`
 	SavePlace             SaveMethod    `json:"-"`
 	DeletingBufferSize    int           `json:"-"`
 	DeletingBufferTimeout time.Duration `json:"-"`
+	ExpireInterval        time.Duration `env:"EXPIRE_INTERVAL" json:"-"`
+	FileFormat            string        `env:"FILE_FORMAT" json:"file_format"`
+	FileSync              string        `env:"FILE_SYNC" json:"file_sync"`
+	FileSyncInterval      time.Duration `env:"FILE_SYNC_INTERVAL" json:"-"`
+	CompactSize           int64         `env:"COMPACT_SIZE" json:"compact_size"`
+	CompactRatio          float64       `env:"COMPACT_RATIO" json:"compact_ratio"`
+	CompactInterval       time.Duration `env:"COMPACT_INTERVAL" json:"-"`
+	StorageReadTimeout    time.Duration `env:"STORAGE_READ_TIMEOUT" json:"-"`
+	StorageWriteTimeout   time.Duration `env:"STORAGE_WRITE_TIMEOUT" json:"-"`
+	DBMaxConns            int32         `env:"DB_MAX_CONNS" json:"db_max_conns"`
+	DBMinConns            int32         `env:"DB_MIN_CONNS" json:"db_min_conns"`
+	DBMaxConnLifetime     time.Duration `env:"DB_MAX_CONN_LIFETIME" json:"-"`
+	DBHealthCheckPeriod   time.Duration `env:"DB_HEALTH_CHECK_PERIOD" json:"-"`
+	DBStatementCacheSize  int           `env:"DB_STATEMENT_CACHE_SIZE" json:"db_statement_cache_size"`
+	CacheSize             int           `env:"CACHE_SIZE" json:"cache_size"`
+	CacheTTL              time.Duration `env:"CACHE_TTL" json:"-"`
+	CacheNegativeTTL      time.Duration `env:"CACHE_NEGATIVE_TTL" json:"-"`
+	RetentionDays         int           `env:"RETENTION_DAYS" json:"retention_days"`
+	RestoreWindow         time.Duration `env:"RESTORE_WINDOW" json:"-"`
+	ReceiptKey            string        `env:"RECEIPT_KEY" json:"receipt_key"`
+	QuotaMaxURLs          int           `env:"QUOTA_MAX_URLS" json:"quota_max_urls"`
+	QuotaMaxCreated       int           `env:"QUOTA_MAX_CREATED" json:"quota_max_created"`
+	QuotaWindow           time.Duration `env:"QUOTA_WINDOW" json:"-"`
+	ClicksQueueSize       int           `json:"-"`
+	ClicksBufferSize      int           `json:"-"`
+	ClicksBufferTimeout   time.Duration `json:"-"`
 }
 
 // NewConfig считывает основные параметры и генерирует структуру Config.
@@ -98,8 +197,112 @@ func NewConfig() (*Config, error) {
 		config.TrustedSubnet = ""
 	}
 
+	switch config.KeyGenerator {
+	case "":
+		config.KeyGenerator = defaultKeyGenerator
+	case KeyRandom, KeyCounter, KeyHash:
+	default:
+		return nil, fmt.Errorf("unknown key generator %q", config.KeyGenerator)
+	}
+	if config.KeyLength <= 0 {
+		config.KeyLength = defaultKeyLength
+	}
+	if config.KeyLength > maxKeyLength {
+		config.KeyLength = maxKeyLength
+	}
+
+	if config.ExpireInterval <= 0 {
+		config.ExpireInterval = defaultExpireInterval
+	}
+	if config.StorageReadTimeout <= 0 {
+		config.StorageReadTimeout = defaultStorageReadTimeout
+	}
+	if config.StorageWriteTimeout <= 0 {
+		config.StorageWriteTimeout = defaultStorageWriteTimeout
+	}
+	if config.DBMaxConns <= 0 {
+		config.DBMaxConns = defaultDBMaxConns
+	}
+	if config.DBMinConns < 0 {
+		config.DBMinConns = 0
+	}
+	if config.DBMinConns > config.DBMaxConns {
+		config.DBMinConns = config.DBMaxConns
+	}
+	if config.DBMaxConnLifetime <= 0 {
+		config.DBMaxConnLifetime = defaultDBMaxConnLifetime
+	}
+	if config.DBHealthCheckPeriod <= 0 {
+		config.DBHealthCheckPeriod = defaultDBHealthCheckPeriod
+	}
+	if config.DBStatementCacheSize <= 0 {
+		config.DBStatementCacheSize = defaultDBStatementCacheSize
+	}
+	if config.CacheSize < 0 {
+		config.CacheSize = 0
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = defaultCacheTTL
+	}
+	if config.CacheNegativeTTL <= 0 {
+		config.CacheNegativeTTL = defaultCacheNegativeTTL
+	}
+	if config.RetentionDays < 0 {
+		config.RetentionDays = 0
+	}
+	if config.RestoreWindow <= 0 {
+		config.RestoreWindow = defaultRestoreWindow
+	}
+	if config.QuotaMaxURLs < 0 {
+		config.QuotaMaxURLs = 0
+	}
+	if config.QuotaMaxCreated < 0 {
+		config.QuotaMaxCreated = 0
+	}
+	if config.QuotaWindow <= 0 {
+		config.QuotaWindow = defaultQuotaWindow
+	}
+	if config.ReceiptKey == "" {
+		// без заданного ключа квитанции об удалении данных нельзя проверить после перезапуска сервиса
+		key := make([]byte, 32)
+		if _, err = rand.Read(key); err != nil {
+			return nil, err
+		}
+		config.ReceiptKey = fmt.Sprintf("%x", key)
+		log.Warn().Msg("RECEIPT_KEY is not set, erasure receipts are signed with a random key")
+	}
+	switch config.FileFormat {
+	case "":
+		config.FileFormat = FormatJSON
+	case FormatJSON, FormatBinary:
+	default:
+		return nil, fmt.Errorf("unknown file format %q", config.FileFormat)
+	}
+	switch config.FileSync {
+	case "":
+		config.FileSync = defaultFileSync
+	case SyncAlways, SyncInterval, SyncNever:
+	default:
+		return nil, fmt.Errorf("unknown file sync policy %q", config.FileSync)
+	}
+	if config.FileSyncInterval <= 0 {
+		config.FileSyncInterval = defaultFileSyncInterval
+	}
+	if config.CompactSize <= 0 {
+		config.CompactSize = defaultCompactSize
+	}
+	if config.CompactRatio <= 1 {
+		config.CompactRatio = defaultCompactRatio
+	}
+	if config.CompactInterval <= 0 {
+		config.CompactInterval = defaultCompactInterval
+	}
+
 	config.DeletingBufferSize = 10
 	config.DeletingBufferTimeout = 100 * time.Millisecond
+	config.ClicksQueueSize = 1024
+	config.ClicksBufferSize = 100
+	config.ClicksBufferTimeout = time.Second
 
 	return &config, nil
 }
@@ -228,5 +431,47 @@ func ReadConfigFile(config *Config) error {
 	if config.TrustedSubnet == "" {
 		config.TrustedSubnet = fileConf.TrustedSubnet
 	}
+	if config.KeyGenerator == "" {
+		config.KeyGenerator = fileConf.KeyGenerator
+	}
+	if config.KeyLength == 0 {
+		config.KeyLength = fileConf.KeyLength
+	}
+	if config.FileFormat == "" {
+		config.FileFormat = fileConf.FileFormat
+	}
+	if config.FileSync == "" {
+		config.FileSync = fileConf.FileSync
+	}
+	if config.CompactSize == 0 {
+		config.CompactSize = fileConf.CompactSize
+	}
+	if config.CompactRatio == 0 {
+		config.CompactRatio = fileConf.CompactRatio
+	}
+	if config.DBMaxConns == 0 {
+		config.DBMaxConns = fileConf.DBMaxConns
+	}
+	if config.DBMinConns == 0 {
+		config.DBMinConns = fileConf.DBMinConns
+	}
+	if config.DBStatementCacheSize == 0 {
+		config.DBStatementCacheSize = fileConf.DBStatementCacheSize
+	}
+	if config.CacheSize == 0 {
+		config.CacheSize = fileConf.CacheSize
+	}
+	if config.RetentionDays == 0 {
+		config.RetentionDays = fileConf.RetentionDays
+	}
+	if config.QuotaMaxURLs == 0 {
+		config.QuotaMaxURLs = fileConf.QuotaMaxURLs
+	}
+	if config.QuotaMaxCreated == 0 {
+		config.QuotaMaxCreated = fileConf.QuotaMaxCreated
+	}
+	if config.ReceiptKey == "" {
+		config.ReceiptKey = fileConf.ReceiptKey
+	}
 	return nil
 }
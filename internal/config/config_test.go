@@ -24,9 +24,32 @@ func TestGetEnv(t *testing.T) {
 				Config:                "config.json",
 				EnableHTTPS:           true,
 				TrustedSubnet:         "192.168.11.0/24",
+				KeyGenerator:          KeyRandom,
+				KeyLength:             8,
 				SavePlace:             SaveSQL,
 				DeletingBufferSize:    10,
 				DeletingBufferTimeout: 100 * time.Millisecond,
+				ExpireInterval:        time.Minute,
+				FileFormat:            FormatJSON,
+				FileSync:              SyncInterval,
+				FileSyncInterval:      time.Second,
+				CompactSize:           64 << 20,
+				CompactRatio:          2,
+				CompactInterval:       time.Minute,
+				StorageReadTimeout:    3 * time.Second,
+				StorageWriteTimeout:   5 * time.Second,
+				DBMaxConns:            10,
+				DBMaxConnLifetime:     time.Hour,
+				DBHealthCheckPeriod:   time.Minute,
+				DBStatementCacheSize:  512,
+				CacheTTL:              time.Minute,
+				CacheNegativeTTL:      5 * time.Second,
+				RestoreWindow:         24 * time.Hour,
+				ReceiptKey:            "receipt-secret",
+				QuotaWindow:           24 * time.Hour,
+				ClicksQueueSize:       1024,
+				ClicksBufferSize:      100,
+				ClicksBufferTimeout:   time.Second,
 			},
 		},
 	}
@@ -39,6 +62,7 @@ func TestGetEnv(t *testing.T) {
 			os.Setenv("CONFIG", "config.json")
 			os.Setenv("ENABLE_HTTPS", "true")
 			os.Setenv("TRUSTED_SUBNET", "192.168.11.0/24")
+			os.Setenv("RECEIPT_KEY", "receipt-secret")
 			got, err := NewConfig()
 			require.NoError(t, err)
 			if !reflect.DeepEqual(got, tt.want) {
@@ -26,6 +26,16 @@ const (
 	ShortURLsServer_ReturnStats_FullMethodName      = "/grpc.ShortURLsServer/ReturnStats"
 	ShortURLsServer_PingDB_FullMethodName           = "/grpc.ShortURLsServer/PingDB"
 	ShortURLsServer_MarkToDelete_FullMethodName     = "/grpc.ShortURLsServer/MarkToDelete"
+	ShortURLsServer_ReturnURLStats_FullMethodName   = "/grpc.ShortURLsServer/ReturnURLStats"
+	ShortURLsServer_ExportURLs_FullMethodName       = "/grpc.ShortURLsServer/ExportURLs"
+	ShortURLsServer_ImportURLs_FullMethodName       = "/grpc.ShortURLsServer/ImportURLs"
+	ShortURLsServer_EraseUser_FullMethodName        = "/grpc.ShortURLsServer/EraseUser"
+	ShortURLsServer_VerifyReceipt_FullMethodName    = "/grpc.ShortURLsServer/VerifyReceipt"
+	ShortURLsServer_RestoreURLs_FullMethodName      = "/grpc.ShortURLsServer/RestoreURLs"
+	ShortURLsServer_UpdateURL_FullMethodName        = "/grpc.ShortURLsServer/UpdateURL"
+	ShortURLsServer_URLHistory_FullMethodName       = "/grpc.ShortURLsServer/URLHistory"
+	ShortURLsServer_RollbackURL_FullMethodName      = "/grpc.ShortURLsServer/RollbackURL"
+	ShortURLsServer_ReturnQuota_FullMethodName      = "/grpc.ShortURLsServer/ReturnQuota"
 )
 
 // ShortURLsServerClient is the client API for ShortURLsServer service.
@@ -36,10 +46,20 @@ type ShortURLsServerClient interface {
 	// rpc AddJSONShortURL(NewJSONRequest) returns (NewJSONResponce); //исключил, т.к. по сути если не передавать слайс байт, то метод ничем не отличается от AddShortURL
 	AddBatchShortURL(ctx context.Context, in *NewBatchRequest, opts ...grpc.CallOption) (*NewBatchResponce, error)
 	ReturnURL(ctx context.Context, in *ShortURLRequest, opts ...grpc.CallOption) (*FullURLResponce, error)
-	ReturnUserURLs(ctx context.Context, in *UserIDRequest, opts ...grpc.CallOption) (*AllUserURLsResponce, error)
+	ReturnUserURLs(ctx context.Context, in *UserURLsRequest, opts ...grpc.CallOption) (*AllUserURLsResponce, error)
 	ReturnStats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponce, error)
 	PingDB(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*StatusResponce, error)
 	MarkToDelete(ctx context.Context, in *DeleteURLsRequest, opts ...grpc.CallOption) (*StatusResponce, error)
+	ReturnURLStats(ctx context.Context, in *URLStatsRequest, opts ...grpc.CallOption) (*URLStatsResponce, error)
+	ExportURLs(ctx context.Context, in *UserIDRequest, opts ...grpc.CallOption) (ShortURLsServer_ExportURLsClient, error)
+	ImportURLs(ctx context.Context, opts ...grpc.CallOption) (ShortURLsServer_ImportURLsClient, error)
+	EraseUser(ctx context.Context, in *UserIDRequest, opts ...grpc.CallOption) (*ErasureReceipt, error)
+	VerifyReceipt(ctx context.Context, in *ErasureReceipt, opts ...grpc.CallOption) (*VerifyReceiptResponce, error)
+	RestoreURLs(ctx context.Context, in *RestoreURLsRequest, opts ...grpc.CallOption) (*RestoreURLsResponce, error)
+	UpdateURL(ctx context.Context, in *UpdateURLRequest, opts ...grpc.CallOption) (*URLVersion, error)
+	URLHistory(ctx context.Context, in *URLHistoryRequest, opts ...grpc.CallOption) (*URLHistoryResponce, error)
+	RollbackURL(ctx context.Context, in *RollbackURLRequest, opts ...grpc.CallOption) (*URLVersion, error)
+	ReturnQuota(ctx context.Context, in *UserIDRequest, opts ...grpc.CallOption) (*QuotaResponce, error)
 }
 
 type shortURLsServerClient struct {
@@ -77,7 +97,7 @@ func (c *shortURLsServerClient) ReturnURL(ctx context.Context, in *ShortURLReque
 	return out, nil
 }
 
-func (c *shortURLsServerClient) ReturnUserURLs(ctx context.Context, in *UserIDRequest, opts ...grpc.CallOption) (*AllUserURLsResponce, error) {
+func (c *shortURLsServerClient) ReturnUserURLs(ctx context.Context, in *UserURLsRequest, opts ...grpc.CallOption) (*AllUserURLsResponce, error) {
 	out := new(AllUserURLsResponce)
 	err := c.cc.Invoke(ctx, ShortURLsServer_ReturnUserURLs_FullMethodName, in, out, opts...)
 	if err != nil {
@@ -113,6 +133,141 @@ func (c *shortURLsServerClient) MarkToDelete(ctx context.Context, in *DeleteURLs
 	return out, nil
 }
 
+func (c *shortURLsServerClient) ReturnURLStats(ctx context.Context, in *URLStatsRequest, opts ...grpc.CallOption) (*URLStatsResponce, error) {
+	out := new(URLStatsResponce)
+	err := c.cc.Invoke(ctx, ShortURLsServer_ReturnURLStats_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortURLsServerClient) ExportURLs(ctx context.Context, in *UserIDRequest, opts ...grpc.CallOption) (ShortURLsServer_ExportURLsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ShortURLsServer_ServiceDesc.Streams[0], ShortURLsServer_ExportURLs_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &shortURLsServerExportURLsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ShortURLsServer_ExportURLsClient interface {
+	Recv() (*ExportedURL, error)
+	grpc.ClientStream
+}
+
+type shortURLsServerExportURLsClient struct {
+	grpc.ClientStream
+}
+
+func (x *shortURLsServerExportURLsClient) Recv() (*ExportedURL, error) {
+	m := new(ExportedURL)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *shortURLsServerClient) ImportURLs(ctx context.Context, opts ...grpc.CallOption) (ShortURLsServer_ImportURLsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ShortURLsServer_ServiceDesc.Streams[1], ShortURLsServer_ImportURLs_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &shortURLsServerImportURLsClient{stream}
+	return x, nil
+}
+
+type ShortURLsServer_ImportURLsClient interface {
+	Send(*ImportURLRequest) error
+	Recv() (*ImportURLResponce, error)
+	grpc.ClientStream
+}
+
+type shortURLsServerImportURLsClient struct {
+	grpc.ClientStream
+}
+
+func (x *shortURLsServerImportURLsClient) Send(m *ImportURLRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *shortURLsServerImportURLsClient) Recv() (*ImportURLResponce, error) {
+	m := new(ImportURLResponce)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *shortURLsServerClient) EraseUser(ctx context.Context, in *UserIDRequest, opts ...grpc.CallOption) (*ErasureReceipt, error) {
+	out := new(ErasureReceipt)
+	err := c.cc.Invoke(ctx, ShortURLsServer_EraseUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortURLsServerClient) VerifyReceipt(ctx context.Context, in *ErasureReceipt, opts ...grpc.CallOption) (*VerifyReceiptResponce, error) {
+	out := new(VerifyReceiptResponce)
+	err := c.cc.Invoke(ctx, ShortURLsServer_VerifyReceipt_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortURLsServerClient) RestoreURLs(ctx context.Context, in *RestoreURLsRequest, opts ...grpc.CallOption) (*RestoreURLsResponce, error) {
+	out := new(RestoreURLsResponce)
+	err := c.cc.Invoke(ctx, ShortURLsServer_RestoreURLs_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortURLsServerClient) UpdateURL(ctx context.Context, in *UpdateURLRequest, opts ...grpc.CallOption) (*URLVersion, error) {
+	out := new(URLVersion)
+	err := c.cc.Invoke(ctx, ShortURLsServer_UpdateURL_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortURLsServerClient) URLHistory(ctx context.Context, in *URLHistoryRequest, opts ...grpc.CallOption) (*URLHistoryResponce, error) {
+	out := new(URLHistoryResponce)
+	err := c.cc.Invoke(ctx, ShortURLsServer_URLHistory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortURLsServerClient) RollbackURL(ctx context.Context, in *RollbackURLRequest, opts ...grpc.CallOption) (*URLVersion, error) {
+	out := new(URLVersion)
+	err := c.cc.Invoke(ctx, ShortURLsServer_RollbackURL_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortURLsServerClient) ReturnQuota(ctx context.Context, in *UserIDRequest, opts ...grpc.CallOption) (*QuotaResponce, error) {
+	out := new(QuotaResponce)
+	err := c.cc.Invoke(ctx, ShortURLsServer_ReturnQuota_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ShortURLsServerServer is the server API for ShortURLsServer service.
 // All implementations must embed UnimplementedShortURLsServerServer
 // for forward compatibility
@@ -121,10 +276,20 @@ type ShortURLsServerServer interface {
 	// rpc AddJSONShortURL(NewJSONRequest) returns (NewJSONResponce); //исключил, т.к. по сути если не передавать слайс байт, то метод ничем не отличается от AddShortURL
 	AddBatchShortURL(context.Context, *NewBatchRequest) (*NewBatchResponce, error)
 	ReturnURL(context.Context, *ShortURLRequest) (*FullURLResponce, error)
-	ReturnUserURLs(context.Context, *UserIDRequest) (*AllUserURLsResponce, error)
+	ReturnUserURLs(context.Context, *UserURLsRequest) (*AllUserURLsResponce, error)
 	ReturnStats(context.Context, *StatsRequest) (*StatsResponce, error)
 	PingDB(context.Context, *PingRequest) (*StatusResponce, error)
 	MarkToDelete(context.Context, *DeleteURLsRequest) (*StatusResponce, error)
+	ReturnURLStats(context.Context, *URLStatsRequest) (*URLStatsResponce, error)
+	ExportURLs(*UserIDRequest, ShortURLsServer_ExportURLsServer) error
+	ImportURLs(ShortURLsServer_ImportURLsServer) error
+	EraseUser(context.Context, *UserIDRequest) (*ErasureReceipt, error)
+	VerifyReceipt(context.Context, *ErasureReceipt) (*VerifyReceiptResponce, error)
+	RestoreURLs(context.Context, *RestoreURLsRequest) (*RestoreURLsResponce, error)
+	UpdateURL(context.Context, *UpdateURLRequest) (*URLVersion, error)
+	URLHistory(context.Context, *URLHistoryRequest) (*URLHistoryResponce, error)
+	RollbackURL(context.Context, *RollbackURLRequest) (*URLVersion, error)
+	ReturnQuota(context.Context, *UserIDRequest) (*QuotaResponce, error)
 	mustEmbedUnimplementedShortURLsServerServer()
 }
 
@@ -141,7 +306,7 @@ func (UnimplementedShortURLsServerServer) AddBatchShortURL(context.Context, *New
 func (UnimplementedShortURLsServerServer) ReturnURL(context.Context, *ShortURLRequest) (*FullURLResponce, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ReturnURL not implemented")
 }
-func (UnimplementedShortURLsServerServer) ReturnUserURLs(context.Context, *UserIDRequest) (*AllUserURLsResponce, error) {
+func (UnimplementedShortURLsServerServer) ReturnUserURLs(context.Context, *UserURLsRequest) (*AllUserURLsResponce, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ReturnUserURLs not implemented")
 }
 func (UnimplementedShortURLsServerServer) ReturnStats(context.Context, *StatsRequest) (*StatsResponce, error) {
@@ -153,6 +318,36 @@ func (UnimplementedShortURLsServerServer) PingDB(context.Context, *PingRequest)
 func (UnimplementedShortURLsServerServer) MarkToDelete(context.Context, *DeleteURLsRequest) (*StatusResponce, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method MarkToDelete not implemented")
 }
+func (UnimplementedShortURLsServerServer) ReturnURLStats(context.Context, *URLStatsRequest) (*URLStatsResponce, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReturnURLStats not implemented")
+}
+func (UnimplementedShortURLsServerServer) ExportURLs(*UserIDRequest, ShortURLsServer_ExportURLsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportURLs not implemented")
+}
+func (UnimplementedShortURLsServerServer) ImportURLs(ShortURLsServer_ImportURLsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ImportURLs not implemented")
+}
+func (UnimplementedShortURLsServerServer) EraseUser(context.Context, *UserIDRequest) (*ErasureReceipt, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EraseUser not implemented")
+}
+func (UnimplementedShortURLsServerServer) VerifyReceipt(context.Context, *ErasureReceipt) (*VerifyReceiptResponce, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyReceipt not implemented")
+}
+func (UnimplementedShortURLsServerServer) RestoreURLs(context.Context, *RestoreURLsRequest) (*RestoreURLsResponce, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreURLs not implemented")
+}
+func (UnimplementedShortURLsServerServer) UpdateURL(context.Context, *UpdateURLRequest) (*URLVersion, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateURL not implemented")
+}
+func (UnimplementedShortURLsServerServer) URLHistory(context.Context, *URLHistoryRequest) (*URLHistoryResponce, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method URLHistory not implemented")
+}
+func (UnimplementedShortURLsServerServer) RollbackURL(context.Context, *RollbackURLRequest) (*URLVersion, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RollbackURL not implemented")
+}
+func (UnimplementedShortURLsServerServer) ReturnQuota(context.Context, *UserIDRequest) (*QuotaResponce, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReturnQuota not implemented")
+}
 func (UnimplementedShortURLsServerServer) mustEmbedUnimplementedShortURLsServerServer() {}
 
 // UnsafeShortURLsServerServer may be embedded to opt out of forward compatibility for this service.
@@ -221,7 +416,7 @@ func _ShortURLsServer_ReturnURL_Handler(srv interface{}, ctx context.Context, de
 }
 
 func _ShortURLsServer_ReturnUserURLs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UserIDRequest)
+	in := new(UserURLsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -233,7 +428,7 @@ func _ShortURLsServer_ReturnUserURLs_Handler(srv interface{}, ctx context.Contex
 		FullMethod: ShortURLsServer_ReturnUserURLs_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ShortURLsServerServer).ReturnUserURLs(ctx, req.(*UserIDRequest))
+		return srv.(ShortURLsServerServer).ReturnUserURLs(ctx, req.(*UserURLsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -292,6 +487,197 @@ func _ShortURLsServer_MarkToDelete_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ShortURLsServer_ReturnURLStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(URLStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortURLsServerServer).ReturnURLStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortURLsServer_ReturnURLStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortURLsServerServer).ReturnURLStats(ctx, req.(*URLStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortURLsServer_ExportURLs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(UserIDRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ShortURLsServerServer).ExportURLs(m, &shortURLsServerExportURLsServer{stream})
+}
+
+type ShortURLsServer_ExportURLsServer interface {
+	Send(*ExportedURL) error
+	grpc.ServerStream
+}
+
+type shortURLsServerExportURLsServer struct {
+	grpc.ServerStream
+}
+
+func (x *shortURLsServerExportURLsServer) Send(m *ExportedURL) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ShortURLsServer_ImportURLs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ShortURLsServerServer).ImportURLs(&shortURLsServerImportURLsServer{stream})
+}
+
+type ShortURLsServer_ImportURLsServer interface {
+	Send(*ImportURLResponce) error
+	Recv() (*ImportURLRequest, error)
+	grpc.ServerStream
+}
+
+type shortURLsServerImportURLsServer struct {
+	grpc.ServerStream
+}
+
+func (x *shortURLsServerImportURLsServer) Send(m *ImportURLResponce) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *shortURLsServerImportURLsServer) Recv() (*ImportURLRequest, error) {
+	m := new(ImportURLRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _ShortURLsServer_EraseUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortURLsServerServer).EraseUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortURLsServer_EraseUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortURLsServerServer).EraseUser(ctx, req.(*UserIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortURLsServer_VerifyReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ErasureReceipt)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortURLsServerServer).VerifyReceipt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortURLsServer_VerifyReceipt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortURLsServerServer).VerifyReceipt(ctx, req.(*ErasureReceipt))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortURLsServer_RestoreURLs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreURLsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortURLsServerServer).RestoreURLs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortURLsServer_RestoreURLs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortURLsServerServer).RestoreURLs(ctx, req.(*RestoreURLsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortURLsServer_UpdateURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortURLsServerServer).UpdateURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortURLsServer_UpdateURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortURLsServerServer).UpdateURL(ctx, req.(*UpdateURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortURLsServer_URLHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(URLHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortURLsServerServer).URLHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortURLsServer_URLHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortURLsServerServer).URLHistory(ctx, req.(*URLHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortURLsServer_RollbackURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortURLsServerServer).RollbackURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortURLsServer_RollbackURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortURLsServerServer).RollbackURL(ctx, req.(*RollbackURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortURLsServer_ReturnQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortURLsServerServer).ReturnQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortURLsServer_ReturnQuota_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortURLsServerServer).ReturnQuota(ctx, req.(*UserIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ShortURLsServer_ServiceDesc is the grpc.ServiceDesc for ShortURLsServer service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -327,7 +713,51 @@ var ShortURLsServer_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "MarkToDelete",
 			Handler:    _ShortURLsServer_MarkToDelete_Handler,
 		},
+		{
+			MethodName: "ReturnURLStats",
+			Handler:    _ShortURLsServer_ReturnURLStats_Handler,
+		},
+		{
+			MethodName: "EraseUser",
+			Handler:    _ShortURLsServer_EraseUser_Handler,
+		},
+		{
+			MethodName: "VerifyReceipt",
+			Handler:    _ShortURLsServer_VerifyReceipt_Handler,
+		},
+		{
+			MethodName: "RestoreURLs",
+			Handler:    _ShortURLsServer_RestoreURLs_Handler,
+		},
+		{
+			MethodName: "UpdateURL",
+			Handler:    _ShortURLsServer_UpdateURL_Handler,
+		},
+		{
+			MethodName: "URLHistory",
+			Handler:    _ShortURLsServer_URLHistory_Handler,
+		},
+		{
+			MethodName: "RollbackURL",
+			Handler:    _ShortURLsServer_RollbackURL_Handler,
+		},
+		{
+			MethodName: "ReturnQuota",
+			Handler:    _ShortURLsServer_ReturnQuota_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportURLs",
+			Handler:       _ShortURLsServer_ExportURLs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportURLs",
+			Handler:       _ShortURLsServer_ImportURLs_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/grpc.proto",
 }
@@ -3,13 +3,21 @@ package grpc
 import (
 	"context"
 	"errors"
+	"io"
 	"net"
 	"net/url"
+	"time"
 
 	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"shortURL/internal/config"
 	pb "shortURL/internal/grpc/proto"
+	"shortURL/internal/midware"
 	"shortURL/internal/storage"
 	"shortURL/internal/worker"
 )
@@ -20,15 +28,17 @@ type ShortURLsServer struct {
 	cfg       *config.Config
 	strg      storage.Storager
 	workerDel *worker.Worker
+	clicks    *worker.ClickRecorder
 	Subnet    net.IPNet
 }
 
 // NewShortURLsServer генерирует структуру для gRPC сервера.
-func NewShortURLsServer(cfg *config.Config, strg storage.Storager, wrkr *worker.Worker) *ShortURLsServer {
+func NewShortURLsServer(cfg *config.Config, strg storage.Storager, wrkr *worker.Worker, clicks *worker.ClickRecorder) *ShortURLsServer {
 	s := ShortURLsServer{
 		cfg:       cfg,
 		strg:      strg,
 		workerDel: wrkr,
+		clicks:    clicks,
 	}
 	if cfg.TrustedSubnet != "" {
 		_, subnet, _ := net.ParseCIDR(cfg.TrustedSubnet)
@@ -48,12 +58,30 @@ func (s *ShortURLsServer) AddShortURL(ctx context.Context, in *pb.NewURLRequest)
 		log.Error().Err(err).Msg("AddShortURL url.Parse err")
 		return nil, storage.ErrBadRequest
 	}
-	newAddr, err := s.strg.SetShortURL(in.Entry, in.UserID, s.cfg)
+	if in.Alias != "" {
+		if err = storage.CheckAlias(in.Alias); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	expiresAt, err := storage.ExpiryTime(timestampPtr(in.ExpiresAt), in.Ttl)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	newAddr, err := s.strg.SetShortURL(ctx, in.Entry, in.UserID, storage.URLOptions{Alias: in.Alias, ExpiresAt: expiresAt}, s.cfg)
+	if errors.Is(err, storage.ErrAliasTaken) {
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	}
+	if errors.Is(err, storage.ErrQuotaExceeded) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
 	var response pb.NewURLResponce
 	if errors.Is(err, storage.ErrConflict) {
 		response.Responce = newAddr
 		return &response, storage.ErrConflict
 	}
+	if err := contextError(err); err != nil {
+		return nil, err
+	}
 	if err != nil {
 		log.Error().Err(err).Msg("AddShortURL storage err")
 		return nil, storage.ErrInternalError
@@ -63,6 +91,7 @@ func (s *ShortURLsServer) AddShortURL(ctx context.Context, in *pb.NewURLRequest)
 }
 
 // AddBatchShortURL метод принимает от пользователя и возвращает в JSON список адресов на сокращение.
+// Каждый адрес обрабатывается отдельно и получает в ответе статус created, exists или invalid.
 func (s *ShortURLsServer) AddBatchShortURL(ctx context.Context, in *pb.NewBatchRequest) (*pb.NewBatchResponce, error) {
 	if in.UserID == "" {
 		log.Error().Msgf("AddBatchShortURL userID empty")
@@ -72,29 +101,48 @@ func (s *ShortURLsServer) AddBatchShortURL(ctx context.Context, in *pb.NewBatchR
 		log.Error().Msgf("AddBatchShortURL incoming no content")
 		return nil, storage.ErrNoContent
 	}
-	var batchURLs = make([]storage.MultiURL, len(in.Request), 0)
+	var batchURLs = make([]storage.MultiURL, 0, len(in.Request))
 	for _, v := range in.Request {
-		batchURLs = append(batchURLs, storage.MultiURL{CorrID: v.CorrID, OriginURL: v.OriginURL})
+		item := storage.MultiURL{CorrID: v.CorrID, OriginURL: v.OriginURL}
+		expiresAt, err := storage.ExpiryTime(timestampPtr(v.ExpiresAt), v.Ttl)
+		if err != nil {
+			item.Status = storage.BatchInvalid
+			item.Error = err.Error()
+		}
+		if err == nil && !expiresAt.IsZero() {
+			item.ExpiresAt = &expiresAt
+		}
+		batchURLs = append(batchURLs, item)
+	}
+	shortURLs, err := s.strg.WriteMultiURL(ctx, batchURLs, in.UserID, s.cfg)
+	if errors.Is(err, storage.ErrQuotaExceeded) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
 	}
-	shortURLs, err := s.strg.WriteMultiURL(batchURLs, in.UserID, s.cfg)
 	if errors.Is(err, storage.ErrUnsupported) {
 		log.Error().Err(err).Msg("AddBatchShortURL json error")
 		return nil, storage.ErrUnsupported
 	}
+	if err := contextError(err); err != nil {
+		return nil, err
+	}
 	if err != nil {
 		log.Error().Err(err).Msg("AddBatchShortURL storage err")
 		return nil, storage.ErrInternalError
 	}
 	var response pb.NewBatchResponce
 	for _, v := range shortURLs {
-		response.Responce = append(response.Responce, &pb.NewBatchResponce_Responce{CorrID: v.CorrID, ShortURL: v.ShortURL})
+		response.Responce = append(response.Responce, &pb.NewBatchResponce_Responce{CorrID: v.CorrID, ShortURL: v.ShortURL, Status: v.Status, Error: v.Error})
 	}
 	return &response, nil
 }
 
 // ReturnURL метод возвращает пользователю исходный адрес.
 func (s *ShortURLsServer) ReturnURL(ctx context.Context, in *pb.ShortURLRequest) (*pb.FullURLResponce, error) {
-	address, err := s.strg.RetFullURL(in.ShortURL)
+	address, err := s.strg.RetFullURL(ctx, in.ShortURL)
+	if errors.Is(err, storage.ErrExpired) {
+		log.Error().Err(err).Msg("ReturnURL address expired")
+		return nil, storage.ErrExpired
+	}
 	if errors.Is(err, storage.ErrGone) {
 		log.Error().Err(err).Msg("ReturnURL address deleted")
 		return nil, storage.ErrGone
@@ -103,38 +151,62 @@ func (s *ShortURLsServer) ReturnURL(ctx context.Context, in *pb.ShortURLRequest)
 		log.Error().Err(err).Msg("ReturnURL address not found")
 		return nil, storage.ErrNoContent
 	}
+	if err := contextError(err); err != nil {
+		return nil, err
+	}
 	if err != nil {
 		log.Error().Err(err).Msg("ReturnURL storage err")
 		return nil, storage.ErrInternalError
 	}
+	s.clicks.Add(clickFromContext(ctx, in.ShortURL))
 	var response pb.FullURLResponce
 	response.FullURL = address
 	return &response, nil
 }
 
-// ReturnURL метод возвращает пользователю список сокращенных им адресов.
-func (s *ShortURLsServer) ReturnUserURLs(ctx context.Context, in *pb.UserIDRequest) (*pb.AllUserURLsResponce, error) {
+// ReturnUserURLs метод возвращает пользователю страницу списка сокращенных им адресов.
+func (s *ShortURLsServer) ReturnUserURLs(ctx context.Context, in *pb.UserURLsRequest) (*pb.AllUserURLsResponce, error) {
 	if in.UserID == "" {
 		log.Error().Msgf("AddBatchShortURL userID empty")
 		return nil, storage.ErrUnauthorized
 	}
-	urls, err := s.strg.ReturnAllURLs(in.UserID, s.cfg)
+	opts, err := storage.CheckListOptions(storage.ListOptions{
+		Cursor:         in.Cursor,
+		Limit:          int(in.Limit),
+		Order:          in.Order,
+		Contains:       in.Contains,
+		IncludeDeleted: in.IncludeDeleted,
+	})
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	page, err := s.strg.ReturnAllURLs(ctx, in.UserID, opts, s.cfg)
 	if errors.Is(err, storage.ErrNoContent) {
 		log.Error().Err(err).Msg("ReturnURL address not found")
 		return nil, storage.ErrNoContent
 	}
+	if err := contextError(err); err != nil {
+		return nil, err
+	}
 	if err != nil {
 		log.Error().Err(err).Msg("ReturnURL storage err")
 		return nil, storage.ErrInternalError
 	}
-	var response pb.AllUserURLsResponce
-	for _, v := range urls {
-		response.Responce = append(response.Responce, &pb.AllUserURLsResponce_Responce{ShortURL: v.ShortURL, OriginalURL: v.OriginalURL})
+	response := pb.AllUserURLsResponce{NextCursor: page.NextCursor}
+	for _, v := range page.URLs {
+		response.Responce = append(response.Responce, &pb.AllUserURLsResponce_Responce{
+			ShortURL:    v.ShortURL,
+			OriginalURL: v.OriginalURL,
+			Deleted:     v.Deleted,
+			CreatedAt:   pbTimestamp(v.CreatedAt),
+			UpdatedAt:   pbTimestamp(v.UpdatedAt),
+			DeletedAt:   pbTimestamp(v.DeletedAt),
+		})
 	}
 	return &response, nil
 }
 
-// ReturnStats метод возвращает количество сокращенных URL и пользователей в сервисе.
+// ReturnStats метод возвращает статистику сервиса по адресам, созданным не ранее since, без since - по всем адресам.
 func (s *ShortURLsServer) ReturnStats(ctx context.Context, in *pb.StatsRequest) (*pb.StatsResponce, error) {
 	if s.cfg.TrustedSubnet == "" {
 		log.Error().Msgf("ReturnStats TrustedSubnet isn't determined")
@@ -149,19 +221,67 @@ func (s *ShortURLsServer) ReturnStats(ctx context.Context, in *pb.StatsRequest)
 		log.Error().Msgf("ReturnStats User IP-address isn't CIDR subnet")
 		return nil, storage.ErrForbidden
 	}
-	stats, err := s.strg.ReturnStats()
+	var since time.Time
+	if in.Since != nil {
+		since = in.Since.AsTime()
+	}
+	stats, err := s.strg.ReturnStats(ctx, since)
+	if err := contextError(err); err != nil {
+		return nil, err
+	}
 	if err != nil {
 		log.Error().Err(err).Msg("ReturnStats storage err")
 		return nil, storage.ErrInternalError
 	}
-	response := pb.StatsResponce{URLs: int32(stats.URLs), Users: int32(stats.Users)}
+	response := pb.StatsResponce{
+		URLs:         int32(stats.URLs),
+		Users:        int32(stats.Users),
+		ActiveURLs:   int32(stats.Active),
+		ExpiredURLs:  int32(stats.Expired),
+		DeletedURLs:  int32(stats.Deleted),
+		StorageBytes: stats.DataSize,
+	}
+	for _, d := range stats.CreatedPerDay {
+		response.CreatedPerDay = append(response.CreatedPerDay, &pb.StatsResponce_DailyCount{Day: d.Day, Count: int64(d.Count)})
+	}
+	for _, o := range stats.TopOwners {
+		response.TopOwners = append(response.TopOwners, &pb.StatsResponce_OwnerCount{UserID: o.UserID, URLs: int64(o.URLs)})
+	}
+	response.Since = pbTimestamp(stats.Since)
 	return &response, nil
 }
 
+// ReturnQuota метод возвращает ограничения пользователя на создание адресов и их текущее использование.
+func (s *ShortURLsServer) ReturnQuota(ctx context.Context, in *pb.UserIDRequest) (*pb.QuotaResponce, error) {
+	if in.UserID == "" {
+		log.Error().Msgf("ReturnQuota userID empty")
+		return nil, storage.ErrUnauthorized
+	}
+	quota, err := storage.UserQuota(ctx, s.strg, in.UserID, s.cfg)
+	if err := contextError(err); err != nil {
+		return nil, err
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("ReturnQuota storage err")
+		return nil, storage.ErrInternalError
+	}
+	return &pb.QuotaResponce{
+		ActiveURLs:    int64(quota.ActiveURLs),
+		MaxActiveURLs: int64(quota.MaxActiveURLs),
+		Created:       int64(quota.Created),
+		MaxCreated:    int64(quota.MaxCreated),
+		WindowSeconds: quota.WindowSeconds,
+		ResetsAt:      pbTimestamp(quota.ResetsAt),
+	}, nil
+}
+
 // PingDB метод возвращает статус наличия соединения с базой данных.
 func (s *ShortURLsServer) PingDB(ctx context.Context, in *pb.PingRequest) (*pb.StatusResponce, error) {
 	var response pb.StatusResponce
-	err := s.strg.CheckPing(s.cfg)
+	err := s.strg.CheckPing(ctx, s.cfg)
+	if err := contextError(err); err != nil {
+		return nil, err
+	}
 	if err != nil {
 		log.Error().Err(err).Msg("PingDB DB error")
 		return nil, storage.ErrInternalError
@@ -185,3 +305,321 @@ func (s *ShortURLsServer) MarkToDelete(ctx context.Context, in *pb.DeleteURLsReq
 	response.RequestStatus = "StatusAccepted"
 	return &response, nil
 }
+
+// ReturnURLStats метод возвращает владельцу статистику переходов по короткой ссылке.
+func (s *ShortURLsServer) ReturnURLStats(ctx context.Context, in *pb.URLStatsRequest) (*pb.URLStatsResponce, error) {
+	if in.UserID == "" {
+		log.Error().Msgf("ReturnURLStats userID empty")
+		return nil, storage.ErrUnauthorized
+	}
+	interval, err := storage.CheckInterval(in.Interval)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	stats, err := s.strg.ReturnClickStats(ctx, in.ShortURL, in.UserID, interval)
+	if errors.Is(err, storage.ErrNoContent) {
+		return nil, status.Error(codes.NotFound, "short URL not found")
+	}
+	if errors.Is(err, storage.ErrForbidden) {
+		return nil, status.Error(codes.PermissionDenied, "short URL belongs to another user")
+	}
+	if err := contextError(err); err != nil {
+		return nil, err
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("ReturnURLStats storage err")
+		return nil, storage.ErrInternalError
+	}
+	response := pb.URLStatsResponce{Total: int64(stats.Total), UniqueVisitors: int64(stats.Visitors)}
+	for _, v := range stats.Series {
+		response.Series = append(response.Series, &pb.URLStatsResponce_Point{Time: timestamppb.New(v.Time), Count: int64(v.Count)})
+	}
+	return &response, nil
+}
+
+// ExportURLs метод передает потоком все адреса пользователя вместе с признаком удаления.
+func (s *ShortURLsServer) ExportURLs(in *pb.UserIDRequest, stream pb.ShortURLsServer_ExportURLsServer) error {
+	if in.UserID == "" {
+		log.Error().Msgf("ExportURLs userID empty")
+		return storage.ErrUnauthorized
+	}
+	err := storage.ExportUser(stream.Context(), s.strg, in.UserID, s.cfg, func(u storage.UserURL) error {
+		return stream.Send(&pb.ExportedURL{
+			ShortURL:    u.ShortURL,
+			OriginalURL: u.OriginalURL,
+			Deleted:     u.Deleted,
+			ExpiresAt:   pbTimestamp(u.ExpiresAt),
+			CreatedAt:   pbTimestamp(u.CreatedAt),
+			UpdatedAt:   pbTimestamp(u.UpdatedAt),
+			DeletedAt:   pbTimestamp(u.DeletedAt),
+		})
+	})
+	if err := contextError(err); err != nil {
+		return err
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("ExportURLs err")
+		return storage.ErrInternalError
+	}
+	return nil
+}
+
+// ImportURLs метод принимает потоком адреса пользователя и возвращает результат загрузки каждого адреса
+// со статусом created, exists или invalid. Все сообщения потока должны относиться к одному пользователю.
+func (s *ShortURLsServer) ImportURLs(stream pb.ShortURLsServer_ImportURLsServer) error {
+	userID := ""
+	for row := int64(1); ; row++ {
+		in, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if in.UserID == "" {
+			log.Error().Msgf("ImportURLs userID empty")
+			return storage.ErrUnauthorized
+		}
+		if userID == "" {
+			userID = in.UserID
+		}
+		if in.UserID != userID {
+			return status.Error(codes.InvalidArgument, "all messages must have the same userID")
+		}
+		u := storage.UserURL{OriginalURL: in.OriginalURL, Alias: in.Alias, Deleted: in.Deleted, ExpiresAt: timestampPtr(in.ExpiresAt)}
+		result, err := storage.ImportURL(stream.Context(), s.strg, userID, u, s.cfg)
+		if err := contextError(err); err != nil {
+			return err
+		}
+		if err != nil {
+			log.Error().Err(err).Msg("ImportURLs storage err")
+			return storage.ErrInternalError
+		}
+		err = stream.Send(&pb.ImportURLResponce{
+			Row:         row,
+			OriginalURL: result.OriginalURL,
+			ShortURL:    result.ShortURL,
+			Status:      result.Status,
+			Error:       result.Error,
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// EraseUser метод удаляет все адреса пользователя вместе со статистикой переходов
+// и возвращает подписанную квитанцию об удалении. Удаление необратимо, поэтому пользователь определяется
+// по куки shortener в метаданных запроса, как и в HTTP API. Переданный userID должен совпадать с ним.
+func (s *ShortURLsServer) EraseUser(ctx context.Context, in *pb.UserIDRequest) (*pb.ErasureReceipt, error) {
+	userID, err := authUserID(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("EraseUser authentication err")
+		return nil, err
+	}
+	if in.UserID != "" && in.UserID != userID {
+		return nil, status.Error(codes.PermissionDenied, "userID does not match shortener cookie")
+	}
+	receipt, err := storage.EraseUserData(ctx, s.strg, userID, []byte(s.cfg.ReceiptKey))
+	if err := contextError(err); err != nil {
+		return nil, err
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("EraseUser storage err")
+		return nil, storage.ErrInternalError
+	}
+	log.Info().Msgf("EraseUser user data erased, receipt %s, %d URLs", receipt.ID, receipt.URLs)
+	return &pb.ErasureReceipt{
+		ReceiptID:  receipt.ID,
+		UserID:     receipt.UserID,
+		ErasedURLs: int64(receipt.URLs),
+		ErasedAt:   timestamppb.New(receipt.ErasedAt),
+		Signature:  receipt.Signature,
+	}, nil
+}
+
+// RestoreURLs метод восстанавливает удаленные адреса пользователя, если с момента удаления не истекло окно восстановления.
+func (s *ShortURLsServer) RestoreURLs(ctx context.Context, in *pb.RestoreURLsRequest) (*pb.RestoreURLsResponce, error) {
+	if in.UserID == "" {
+		log.Error().Msgf("RestoreURLs userID empty")
+		return nil, storage.ErrUnauthorized
+	}
+	if len(in.Keys) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "restore keys empty")
+	}
+	results, err := storage.RestoreUserURLs(ctx, s.strg, in.Keys, in.UserID, s.cfg.RestoreWindow)
+	if errors.Is(err, storage.ErrQuotaExceeded) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if err := contextError(err); err != nil {
+		return nil, err
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("RestoreURLs storage err")
+		return nil, storage.ErrInternalError
+	}
+	response := pb.RestoreURLsResponce{Results: make([]*pb.RestoreURLResult, 0, len(results))}
+	for _, r := range results {
+		response.Results = append(response.Results, &pb.RestoreURLResult{Key: r.Key, Status: r.Status, Error: r.Error})
+	}
+	return &response, nil
+}
+
+// UpdateURL метод заменяет исходный адрес короткой ссылки владельца и возвращает новую версию адреса.
+func (s *ShortURLsServer) UpdateURL(ctx context.Context, in *pb.UpdateURLRequest) (*pb.URLVersion, error) {
+	if in.UserID == "" {
+		log.Error().Msgf("UpdateURL userID empty")
+		return nil, storage.ErrUnauthorized
+	}
+	if _, err := url.Parse(in.OriginalURL); in.OriginalURL == "" || err != nil {
+		return nil, status.Error(codes.InvalidArgument, "wrong address")
+	}
+	v, err := s.strg.UpdateURL(ctx, in.ShortURL, in.UserID, in.OriginalURL)
+	if err := versionError(err); err != nil {
+		return nil, err
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("UpdateURL storage err")
+		return nil, storage.ErrInternalError
+	}
+	return pbVersion(*v), nil
+}
+
+// URLHistory метод возвращает владельцу историю исходных адресов короткой ссылки.
+func (s *ShortURLsServer) URLHistory(ctx context.Context, in *pb.URLHistoryRequest) (*pb.URLHistoryResponce, error) {
+	if in.UserID == "" {
+		log.Error().Msgf("URLHistory userID empty")
+		return nil, storage.ErrUnauthorized
+	}
+	history, err := s.strg.URLHistory(ctx, in.ShortURL, in.UserID)
+	if err := versionError(err); err != nil {
+		return nil, err
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("URLHistory storage err")
+		return nil, storage.ErrInternalError
+	}
+	response := pb.URLHistoryResponce{Versions: make([]*pb.URLVersion, 0, len(history))}
+	for _, v := range history {
+		response.Versions = append(response.Versions, pbVersion(v))
+	}
+	return &response, nil
+}
+
+// RollbackURL метод возвращает короткой ссылке исходный адрес прежней версии.
+func (s *ShortURLsServer) RollbackURL(ctx context.Context, in *pb.RollbackURLRequest) (*pb.URLVersion, error) {
+	if in.UserID == "" {
+		log.Error().Msgf("RollbackURL userID empty")
+		return nil, storage.ErrUnauthorized
+	}
+	if in.Version <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "version must be positive")
+	}
+	v, err := storage.RollbackURL(ctx, s.strg, in.ShortURL, in.UserID, int(in.Version))
+	if err := versionError(err); err != nil {
+		return nil, err
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("RollbackURL storage err")
+		return nil, storage.ErrInternalError
+	}
+	return pbVersion(*v), nil
+}
+
+// versionError функция переводит ошибку изменения или чтения истории короткой ссылки в статус gRPC.
+func versionError(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrNoContent):
+		return status.Error(codes.NotFound, "short URL not found")
+	case errors.Is(err, storage.ErrVersionNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, storage.ErrForbidden):
+		return status.Error(codes.PermissionDenied, "short URL belongs to another user")
+	case errors.Is(err, storage.ErrExpired):
+		return status.Error(codes.FailedPrecondition, "short URL expired")
+	case errors.Is(err, storage.ErrGone):
+		return status.Error(codes.FailedPrecondition, "short URL deleted")
+	case errors.Is(err, storage.ErrConflict):
+		return status.Error(codes.AlreadyExists, "user already has a short URL for this address")
+	}
+	return contextError(err)
+}
+
+// pbVersion функция преобразует версию адреса в сообщение gRPC.
+func pbVersion(v storage.URLVersion) *pb.URLVersion {
+	return &pb.URLVersion{Version: int64(v.Version), OriginalURL: v.URL, CreatedAt: pbTimestamp(v.CreatedAt)}
+}
+
+// VerifyReceipt метод проверяет подпись квитанции об удалении данных пользователя.
+func (s *ShortURLsServer) VerifyReceipt(ctx context.Context, in *pb.ErasureReceipt) (*pb.VerifyReceiptResponce, error) {
+	receipt := storage.ErasureReceipt{
+		ID:        in.ReceiptID,
+		UserID:    in.UserID,
+		URLs:      int(in.ErasedURLs),
+		ErasedAt:  in.ErasedAt.AsTime(),
+		Signature: in.Signature,
+	}
+	return &pb.VerifyReceiptResponce{Valid: storage.VerifyReceipt(&receipt, []byte(s.cfg.ReceiptKey))}, nil
+}
+
+// contextError функция возвращает статус gRPC, если операция хранилища не уложилась в отведенное время
+// (codes.DeadlineExceeded) или была отменена (codes.Canceled), и nil для остальных ошибок.
+func contextError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return status.FromContextError(err).Err()
+	}
+	return nil
+}
+
+// authUserID функция возвращает ID пользователя из куки shortener, переданной в метаданных запроса.
+func authUserID(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	v := md.Get("shortener")
+	if len(v) == 0 {
+		return "", status.Error(codes.Unauthenticated, "shortener cookie required")
+	}
+	userID, err := midware.UserIDFromCookie(v[0])
+	if err != nil {
+		return "", status.Error(codes.Unauthenticated, err.Error())
+	}
+	return userID, nil
+}
+
+// clickFromContext функция собирает данные о переходе из метаданных gRPC запроса.
+func clickFromContext(ctx context.Context, key string) storage.Click {
+	click := storage.Click{Key: key, Time: time.Now().UTC()}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("referer"); len(v) > 0 {
+			click.Referrer = v[0]
+		}
+		if v := md.Get("user-agent"); len(v) > 0 {
+			click.UserAgent = v[0]
+		}
+		if v := md.Get("x-real-ip"); len(v) > 0 {
+			click.IP = v[0]
+		}
+	}
+	if click.IP == "" {
+		if p, ok := peer.FromContext(ctx); ok {
+			click.IP, _, _ = net.SplitHostPort(p.Addr.String())
+		}
+	}
+	return click
+}
+
+// timestampPtr функция преобразует время из gRPC сообщения, nil означает отсутствие значения.
+func timestampPtr(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+// pbTimestamp функция преобразует необязательное время в сообщение gRPC, отсутствующее время остается пустым.
+func pbTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+
+	"shortURL/internal/midware"
+	"shortURL/internal/storage"
+)
+
+type receiptStatus struct {
+	Valid bool `json:"valid"`
+}
+
+// UserErase метод удаляет все адреса пользователя вместе со статистикой переходов
+// и возвращает подписанную квитанцию об удалении.
+func (h *Handler) UserErase(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(midware.UserID).(string)
+	if !ok {
+		http.Error(w, "userID empty", http.StatusUnauthorized)
+		return
+	}
+	receipt, err := storage.EraseUserData(r.Context(), h.strg, userID, []byte(h.cfg.ReceiptKey))
+	if writeTimeout(w, err) {
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("UserErase storage error")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	log.Info().Msgf("UserErase user data erased, receipt %s, %d URLs", receipt.ID, receipt.URLs)
+	receiptBZ, err := json.Marshal(receipt)
+	if err != nil {
+		log.Error().Err(err).Msg("UserErase json.Marshal error")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(receiptBZ)
+}
+
+// ReceiptVerify метод проверяет подпись квитанции об удалении данных пользователя.
+func (h *Handler) ReceiptVerify(w http.ResponseWriter, r *http.Request) {
+	var receipt storage.ErasureReceipt
+	if err := json.NewDecoder(r.Body).Decode(&receipt); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	statusBZ, err := json.Marshal(receiptStatus{Valid: storage.VerifyReceipt(&receipt, []byte(h.cfg.ReceiptKey))})
+	if err != nil {
+		log.Error().Err(err).Msg("ReceiptVerify json.Marshal error")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(statusBZ)
+}
@@ -0,0 +1,342 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"shortURL/internal/midware"
+	"shortURL/internal/storage"
+)
+
+// Форматы выгрузки и загрузки адресов пользователя.
+const (
+	formatJSON = "json"
+	formatCSV  = "csv"
+)
+
+// csvHeader - столбцы выгрузки адресов в формате CSV. При загрузке учитывается также столбец alias,
+// а ключ ссылки из short_url сохраняется, если он свободен.
+var csvHeader = []string{"short_url", "original_url", "deleted", "expires_at"}
+
+// URLsExport метод выгружает все адреса пользователя вместе с признаком удаления в формате JSON или CSV.
+// Адреса читаются из хранилища страницами и сразу передаются клиенту.
+func (h *Handler) URLsExport(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(midware.UserID).(string)
+	if !ok {
+		http.Error(w, "userID empty", http.StatusUnauthorized)
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = formatJSON
+	}
+	var out streamWriter
+	switch format {
+	case formatJSON:
+		out = newJSONStream(w)
+	case formatCSV:
+		out = newCSVStream(w)
+	default:
+		http.Error(w, "unknown format: "+format, http.StatusBadRequest)
+		return
+	}
+	err := storage.ExportUser(r.Context(), h.strg, userID, h.cfg, func(u storage.UserURL) error {
+		return out.Write(u)
+	})
+	if err != nil {
+		h.streamError(w, out, err, "URLsExport")
+		return
+	}
+	if err = out.Close(); err != nil {
+		log.Error().Err(err).Msg("URLsExport write error")
+	}
+}
+
+// URLsImport метод загружает адреса пользователя из JSON массива или из CSV при заголовке Content-Type: text/csv.
+// Каждый адрес сохраняется так же, как при сокращении одного адреса, и получает в ответе статус created, exists или invalid.
+// Тело запроса читается и ответ передается по мере обработки адресов.
+func (h *Handler) URLsImport(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(midware.UserID).(string)
+	if !ok {
+		http.Error(w, "userID empty", http.StatusUnauthorized)
+		return
+	}
+	var in func() (storage.UserURL, error)
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "text/csv" {
+		reader, err := newCSVReader(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		in = reader.Read
+	} else {
+		reader, err := newJSONReader(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+			return
+		}
+		in = reader.Read
+	}
+	out := newJSONStream(w)
+	for row := 1; ; row++ {
+		u, err := in()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var result storage.ImportResult
+		var rowErr *rowError
+		if errors.As(err, &rowErr) {
+			result = storage.ImportResult{OriginalURL: u.OriginalURL, Status: storage.BatchInvalid, Error: rowErr.Error()}
+		} else if err != nil {
+			if !out.Started() {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			log.Error().Err(err).Msg("URLsImport read body err")
+			return
+		} else {
+			result, err = storage.ImportURL(r.Context(), h.strg, userID, u, h.cfg)
+			if err != nil {
+				h.streamError(w, out, err, "URLsImport")
+				return
+			}
+		}
+		result.Row = row
+		if err = out.Write(result); err != nil {
+			log.Error().Err(err).Msg("URLsImport write error")
+			return
+		}
+	}
+	if err := out.Close(); err != nil {
+		log.Error().Err(err).Msg("URLsImport write error")
+	}
+}
+
+// streamError метод отвечает клиенту об ошибке хранилища. Если передача ответа уже началась,
+// статус изменить нельзя, поэтому ошибка только записывается в журнал и ответ обрывается.
+func (h *Handler) streamError(w http.ResponseWriter, out streamWriter, err error, op string) {
+	if out.Started() {
+		log.Error().Err(err).Msgf("%s storage error after response started", op)
+		return
+	}
+	if writeTimeout(w, err) {
+		return
+	}
+	log.Error().Err(err).Msgf("%s storage error", op)
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+// streamWriter - интерфейс потоковой записи элементов ответа.
+// Заголовки ответа отправляются с первым элементом, поэтому до него клиенту можно вернуть ошибку.
+type streamWriter interface {
+	Write(v any) error
+	Close() error
+	Started() bool
+}
+
+// jsonStream записывает элементы ответа в JSON массив.
+type jsonStream struct {
+	w       http.ResponseWriter
+	enc     *json.Encoder
+	started bool
+}
+
+func newJSONStream(w http.ResponseWriter) *jsonStream {
+	return &jsonStream{w: w, enc: json.NewEncoder(w)}
+}
+
+// Write метод дописывает элемент в массив.
+func (s *jsonStream) Write(v any) error {
+	sep := ","
+	if !s.started {
+		s.w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		s.w.WriteHeader(http.StatusOK)
+		s.started = true
+		sep = "["
+	}
+	if _, err := io.WriteString(s.w, sep); err != nil {
+		return err
+	}
+	return s.enc.Encode(v)
+}
+
+// Close метод закрывает массив.
+func (s *jsonStream) Close() error {
+	if !s.started {
+		s.w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		s.w.WriteHeader(http.StatusOK)
+		s.started = true
+		_, err := io.WriteString(s.w, "[]\n")
+		return err
+	}
+	_, err := io.WriteString(s.w, "]\n")
+	return err
+}
+
+// Started метод сообщает, отправлены ли клиенту заголовки ответа.
+func (s *jsonStream) Started() bool {
+	return s.started
+}
+
+// csvStream записывает адреса пользователя строками CSV.
+type csvStream struct {
+	w       http.ResponseWriter
+	csv     *csv.Writer
+	started bool
+}
+
+func newCSVStream(w http.ResponseWriter) *csvStream {
+	return &csvStream{w: w, csv: csv.NewWriter(w)}
+}
+
+// Write метод дописывает строку с адресом пользователя.
+func (s *csvStream) Write(v any) error {
+	u, ok := v.(storage.UserURL)
+	if !ok {
+		return fmt.Errorf("csv: unsupported value %T", v)
+	}
+	if err := s.start(); err != nil {
+		return err
+	}
+	expiresAt := ""
+	if u.ExpiresAt != nil {
+		expiresAt = u.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	return s.csv.Write([]string{u.ShortURL, u.OriginalURL, strconv.FormatBool(u.Deleted), expiresAt})
+}
+
+// Close метод дописывает буферизованные строки.
+func (s *csvStream) Close() error {
+	if err := s.start(); err != nil {
+		return err
+	}
+	s.csv.Flush()
+	return s.csv.Error()
+}
+
+// Started метод сообщает, отправлены ли клиенту заголовки ответа.
+func (s *csvStream) Started() bool {
+	return s.started
+}
+
+func (s *csvStream) start() error {
+	if s.started {
+		return nil
+	}
+	s.w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	s.w.Header().Set("Content-Disposition", `attachment; filename="urls.csv"`)
+	s.w.WriteHeader(http.StatusOK)
+	s.started = true
+	return s.csv.Write(csvHeader)
+}
+
+// rowError - ошибка разбора отдельного адреса, после которой чтение тела запроса продолжается.
+type rowError struct {
+	err error
+}
+
+func (e *rowError) Error() string {
+	return e.err.Error()
+}
+
+// jsonReader читает адреса пользователя из JSON массива по одному.
+type jsonReader struct {
+	dec *json.Decoder
+}
+
+func newJSONReader(r io.Reader) (*jsonReader, error) {
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return nil, errors.New("JSON array expected")
+	}
+	return &jsonReader{dec: dec}, nil
+}
+
+// Read метод возвращает следующий адрес либо io.EOF в конце массива.
+func (r *jsonReader) Read() (storage.UserURL, error) {
+	var u storage.UserURL
+	if !r.dec.More() {
+		if _, err := r.dec.Token(); err != nil {
+			return u, err
+		}
+		return u, io.EOF
+	}
+	var raw json.RawMessage
+	if err := r.dec.Decode(&raw); err != nil {
+		return u, err
+	}
+	if err := json.Unmarshal(raw, &u); err != nil {
+		return u, &rowError{err: err}
+	}
+	return u, nil
+}
+
+// csvReader читает адреса пользователя из строк CSV. Столбцы определяются по первой строке.
+type csvReader struct {
+	csv     *csv.Reader
+	columns map[string]int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("csv header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[name] = i
+	}
+	if _, ok := columns["original_url"]; !ok {
+		return nil, errors.New("csv header: original_url column is required")
+	}
+	return &csvReader{csv: reader, columns: columns}, nil
+}
+
+// Read метод возвращает следующий адрес либо io.EOF в конце данных.
+func (r *csvReader) Read() (storage.UserURL, error) {
+	var u storage.UserURL
+	line, err := r.csv.Read()
+	if err != nil {
+		return u, err
+	}
+	u.ShortURL = r.field(line, "short_url")
+	u.OriginalURL = r.field(line, "original_url")
+	u.Alias = r.field(line, "alias")
+	if v := r.field(line, "deleted"); v != "" {
+		if u.Deleted, err = strconv.ParseBool(v); err != nil {
+			return u, &rowError{err: fmt.Errorf("deleted: %w", err)}
+		}
+	}
+	if v := r.field(line, "expires_at"); v != "" {
+		expiresAt, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return u, &rowError{err: fmt.Errorf("expires_at: %w", err)}
+		}
+		u.ExpiresAt = &expiresAt
+	}
+	return u, nil
+}
+
+func (r *csvReader) field(line []string, name string) string {
+	i, ok := r.columns[name]
+	if !ok || i >= len(line) {
+		return ""
+	}
+	return line[i]
+}
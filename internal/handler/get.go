@@ -3,8 +3,12 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog/log"
@@ -13,33 +17,77 @@ import (
 	"shortURL/internal/storage"
 )
 
-// URLsGet метод возвращает пользователю список сокращенных им адресов.
+// URLsGet метод возвращает пользователю список сокращенных им адресов, по умолчанию сначала новые.
+// Параметры запроса: limit - размер страницы, cursor - курсор следующей страницы из заголовка X-Next-Cursor,
+// order - порядок по времени создания asc или desc, contains - подстрока исходного адреса,
+// include_deleted - включать ли удаленные адреса. Без limit возвращаются все адреса пользователя.
 func (h *Handler) URLsGet(w http.ResponseWriter, r *http.Request) {
 	userID, ok := r.Context().Value(midware.UserID).(string)
 	if !ok {
 		http.Error(w, "userID empty", http.StatusUnauthorized)
 		return
 	}
-	urls, err := h.strg.ReturnAllURLs(userID, h.cfg)
+	opts, err := listOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	page, err := h.strg.ReturnAllURLs(r.Context(), userID, opts, h.cfg)
 	if errors.Is(err, storage.ErrNoContent) {
 		http.Error(w, err.Error(), http.StatusNoContent)
 		return
 	}
-	urlsBZ, err := json.Marshal(urls)
+	if writeTimeout(w, err) {
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("URLsGet storage error")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	urlsBZ, err := json.Marshal(page.URLs)
 	if err != nil {
 		log.Error().Err(err).Msg("URLsGet json.Marshal error")
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if page.NextCursor != "" {
+		w.Header().Set("X-Next-Cursor", page.NextCursor)
+	}
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	w.Write(urlsBZ)
 }
 
+// listOptions функция разбирает параметры списка адресов пользователя из запроса.
+func listOptions(r *http.Request) (storage.ListOptions, error) {
+	query := r.URL.Query()
+	opts := storage.ListOptions{Cursor: query.Get("cursor"), Order: query.Get("order"), Contains: query.Get("contains")}
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			return opts, fmt.Errorf("%w: limit must be from 1 to %d", storage.ErrBadRequest, storage.MaxListLimit)
+		}
+		opts.Limit = n
+	}
+	if deleted := query.Get("include_deleted"); deleted != "" {
+		include, err := strconv.ParseBool(deleted)
+		if err != nil {
+			return opts, fmt.Errorf("%w: include_deleted must be true or false", storage.ErrBadRequest)
+		}
+		opts.IncludeDeleted = include
+	}
+	return storage.CheckListOptions(opts)
+}
+
 // IDGet метод возвращает пользователю исходный адрес.
 func (h *Handler) IDGet(w http.ResponseWriter, r *http.Request) {
 	key := chi.URLParam(r, "id")
-	address, err := h.strg.RetFullURL(key)
+	address, err := h.strg.RetFullURL(r.Context(), key)
+	if errors.Is(err, storage.ErrExpired) {
+		http.Error(w, "URL Expired", http.StatusGone)
+		return
+	}
 	if errors.Is(err, storage.ErrGone) {
 		http.Error(w, "URL Deleted", http.StatusGone)
 		return
@@ -48,17 +96,70 @@ func (h *Handler) IDGet(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Wrong address!", http.StatusBadRequest)
 		return
 	}
+	if writeTimeout(w, err) {
+		return
+	}
 	if err != nil {
 		log.Error().Err(err).Msg("IDGet storage error")
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	h.clicks.Add(storage.Click{
+		Key:       key,
+		Time:      time.Now().UTC(),
+		Referrer:  r.Referer(),
+		UserAgent: r.UserAgent(),
+		IP:        clientIP(r),
+	})
 	http.Redirect(w, r, address, http.StatusTemporaryRedirect)
 }
 
+// URLStatsGet метод возвращает владельцу статистику переходов по короткой ссылке.
+func (h *Handler) URLStatsGet(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(midware.UserID).(string)
+	if !ok {
+		http.Error(w, "userID empty", http.StatusUnauthorized)
+		return
+	}
+	interval, err := storage.CheckInterval(r.URL.Query().Get("interval"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	stats, err := h.strg.ReturnClickStats(r.Context(), chi.URLParam(r, "id"), userID, interval)
+	if errors.Is(err, storage.ErrNoContent) {
+		http.Error(w, "Wrong address!", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, storage.ErrForbidden) {
+		http.Error(w, "URL belongs to another user", http.StatusForbidden)
+		return
+	}
+	if writeTimeout(w, err) {
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("URLStatsGet storage error")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	statsBZ, err := json.Marshal(stats)
+	if err != nil {
+		log.Error().Err(err).Msg("URLStatsGet json.Marshal error")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(statsBZ)
+}
+
 // PingGet метод возвращает статус наличия соединения с базой данных.
 func (h *Handler) PingGet(w http.ResponseWriter, r *http.Request) {
-	err := h.strg.CheckPing(h.cfg)
+	err := h.strg.CheckPing(r.Context(), h.cfg)
+	if writeTimeout(w, err) {
+		return
+	}
 	if err != nil {
 		log.Error().Err(err).Msg("PingGet DB error")
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -68,7 +169,9 @@ func (h *Handler) PingGet(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
-// StatsGet метод возвращает количество сокращенных URL и пользователей в сервисе.
+// StatsGet метод возвращает статистику сервиса: количество адресов по состояниям и пользователей,
+// число созданных за день адресов, пользователей с наибольшим числом адресов и объем хранимых данных.
+// Параметр since ограничивает статистику адресами, созданными не ранее указанного времени или длительности.
 func (h *Handler) StatsGet(w http.ResponseWriter, r *http.Request) {
 	if h.cfg.TrustedSubnet == "" {
 		log.Error().Msgf("StatsGet TrustedSubnet isn't determined")
@@ -87,7 +190,15 @@ func (h *Handler) StatsGet(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	stats, err := h.strg.ReturnStats()
+	since, err := storage.ParseSince(r.URL.Query().Get("since"), time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	stats, err := h.strg.ReturnStats(r.Context(), since)
+	if writeTimeout(w, err) {
+		return
+	}
 	if err != nil {
 		log.Error().Err(err).Msg("StatsGet ReturnStats error")
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -102,3 +213,19 @@ func (h *Handler) StatsGet(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write(statsBZ)
 }
+
+// clientIP функция определяет адрес клиента по заголовкам прокси или адресу соединения.
+func clientIP(r *http.Request) string {
+	if ip := r.Header.Get("X-Real-IP"); ip != "" {
+		return ip
+	}
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		ip, _, _ := strings.Cut(forwarded, ",")
+		return strings.TrimSpace(ip)
+	}
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return ip
+}
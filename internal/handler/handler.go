@@ -1,7 +1,12 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"net"
+	"net/http"
+	"time"
+
 	"shortURL/internal/config"
 	"shortURL/internal/storage"
 	"shortURL/internal/worker"
@@ -12,15 +17,17 @@ type Handler struct {
 	cfg       *config.Config
 	strg      storage.Storager
 	workerDel *worker.Worker
+	clicks    *worker.ClickRecorder
 	Subnet    net.IPNet
 }
 
 // NewHandler генерирует структуру Handler.
-func NewHandler(cfg *config.Config, strg storage.Storager, wrkr *worker.Worker) *Handler {
+func NewHandler(cfg *config.Config, strg storage.Storager, wrkr *worker.Worker, clicks *worker.ClickRecorder) *Handler {
 	h := Handler{
 		cfg:       cfg,
 		strg:      strg,
 		workerDel: wrkr,
+		clicks:    clicks,
 	}
 	if cfg.TrustedSubnet != "" {
 		_, subnet, _ := net.ParseCIDR(cfg.TrustedSubnet)
@@ -30,6 +37,31 @@ func NewHandler(cfg *config.Config, strg storage.Storager, wrkr *worker.Worker)
 }
 
 type postURL struct {
-	GetURL string `json:"url,omitempty"`
-	SetURL string `json:"result,omitempty"`
+	GetURL    string     `json:"url,omitempty"`
+	Alias     string     `json:"alias,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	TTL       int64      `json:"ttl,omitempty"`
+	SetURL    string     `json:"result,omitempty"`
+}
+
+// writeTimeout функция отвечает клиенту, если операция хранилища не уложилась в отведенное время или была отменена.
+func writeTimeout(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		http.Error(w, "Storage timeout", http.StatusGatewayTimeout)
+	case errors.Is(err, context.Canceled):
+		http.Error(w, "Request canceled", http.StatusServiceUnavailable)
+	default:
+		return false
+	}
+	return true
+}
+
+// writeQuota функция отвечает клиенту, если пользователь превысил ограничения на создание адресов.
+func writeQuota(w http.ResponseWriter, err error) bool {
+	if !errors.Is(err, storage.ErrQuotaExceeded) {
+		return false
+	}
+	http.Error(w, err.Error(), http.StatusTooManyRequests)
+	return true
 }
@@ -14,6 +14,7 @@ import (
 )
 
 // BatchNewEtriesPost метод принимает от пользователя и возвращает JSON список адресов на сокращение.
+// Каждый адрес обрабатывается отдельно и получает в ответе статус created, exists или invalid.
 func (h *Handler) BatchNewEtriesPost(w http.ResponseWriter, r *http.Request) {
 	userID, ok := r.Context().Value(midware.UserID).(string)
 	if !ok {
@@ -35,7 +36,22 @@ func (h *Handler) BatchNewEtriesPost(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "batch URLs empty", http.StatusNoContent)
 		return
 	}
-	rMultiURLs, err := h.strg.WriteMultiURL(multiURLs, userID, h.cfg)
+	for i, v := range multiURLs {
+		multiURLs[i].Status = ""
+		multiURLs[i].Error = ""
+		expiresAt, err := storage.ExpiryTime(v.ExpiresAt, v.TTL)
+		if err != nil {
+			multiURLs[i].Status = storage.BatchInvalid
+			multiURLs[i].Error = err.Error()
+			continue
+		}
+		multiURLs[i].ExpiresAt = storage.TimePtr(expiresAt)
+		multiURLs[i].TTL = 0
+	}
+	rMultiURLs, err := h.strg.WriteMultiURL(r.Context(), multiURLs, userID, h.cfg)
+	if writeQuota(w, err) || writeTimeout(w, err) {
+		return
+	}
 	if err != nil {
 		log.Error().Err(err).Msg("BatchPost WriteMultiURL err")
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -53,6 +69,7 @@ func (h *Handler) BatchNewEtriesPost(w http.ResponseWriter, r *http.Request) {
 }
 
 // ShortenPost метод принимает от пользователя и возвращает в JSON адрес на сокращение.
+// Срок действия ссылки задается полем expires_at или временем жизни ttl в секундах.
 func (h *Handler) ShortenPost(w http.ResponseWriter, r *http.Request) {
 	userID, ok := r.Context().Value(midware.UserID).(string)
 	if !ok {
@@ -76,7 +93,25 @@ func (h *Handler) ShortenPost(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Wrong address!", http.StatusBadRequest)
 		return
 	}
-	key, err := h.strg.SetShortURL(addr.GetURL, userID, h.cfg)
+	if addr.Alias != "" {
+		if err = storage.CheckAlias(addr.Alias); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	expiresAt, err := storage.ExpiryTime(addr.ExpiresAt, addr.TTL)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	key, err := h.strg.SetShortURL(r.Context(), addr.GetURL, userID, storage.URLOptions{Alias: addr.Alias, ExpiresAt: expiresAt}, h.cfg)
+	if errors.Is(err, storage.ErrAliasTaken) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if writeQuota(w, err) {
+		return
+	}
 	if errors.Is(err, storage.ErrConflict) {
 		newAddr := postURL{SetURL: key}
 		newAddrBZ, err := json.Marshal(newAddr)
@@ -90,12 +125,15 @@ func (h *Handler) ShortenPost(w http.ResponseWriter, r *http.Request) {
 		w.Write(newAddrBZ)
 		return
 	}
+	if writeTimeout(w, err) {
+		return
+	}
 	if err != nil {
 		log.Error().Err(err).Msg("ShortenPost SetShortURL err")
 		http.Error(w, "ShortenPost json.Marshal err", http.StatusInternalServerError)
 		return
 	}
-	newAddr := postURL{SetURL: key}
+	newAddr := postURL{SetURL: key, ExpiresAt: storage.TimePtr(expiresAt)}
 	newAddrBZ, err := json.Marshal(newAddr)
 	if err != nil {
 		log.Error().Err(err).Msg("ShortenPost json.Marshal err")
@@ -108,6 +146,7 @@ func (h *Handler) ShortenPost(w http.ResponseWriter, r *http.Request) {
 }
 
 // URLPost метод принимает от пользователя и возвращает адрес на сокращение.
+// Псевдоним короткой ссылки можно передать в параметре запроса alias.
 func (h *Handler) URLPost(w http.ResponseWriter, r *http.Request) {
 	userID, ok := r.Context().Value(midware.UserID).(string)
 	if !ok {
@@ -127,13 +166,30 @@ func (h *Handler) URLPost(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Wrong address!", http.StatusBadRequest)
 		return
 	}
-	newAddr, err := h.strg.SetShortURL(fURL, userID, h.cfg)
+	alias := r.URL.Query().Get("alias")
+	if alias != "" {
+		if err = storage.CheckAlias(alias); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	newAddr, err := h.strg.SetShortURL(r.Context(), fURL, userID, storage.URLOptions{Alias: alias}, h.cfg)
+	if errors.Is(err, storage.ErrAliasTaken) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if writeQuota(w, err) {
+		return
+	}
 	if errors.Is(err, storage.ErrConflict) {
 		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 		w.WriteHeader(http.StatusConflict)
 		w.Write([]byte(newAddr))
 		return
 	}
+	if writeTimeout(w, err) {
+		return
+	}
 	if err != nil {
 		log.Error().Err(err).Msg("URLPost storage err")
 		http.Error(w, err.Error(), http.StatusInternalServerError)
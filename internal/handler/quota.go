@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+
+	"shortURL/internal/midware"
+	"shortURL/internal/storage"
+)
+
+// QuotaGet метод возвращает пользователю его ограничения на создание адресов и их текущее использование.
+func (h *Handler) QuotaGet(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(midware.UserID).(string)
+	if !ok {
+		http.Error(w, "userID empty", http.StatusUnauthorized)
+		return
+	}
+	quota, err := storage.UserQuota(r.Context(), h.strg, userID, h.cfg)
+	if writeTimeout(w, err) {
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("QuotaGet storage error")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	quotaBZ, err := json.Marshal(quota)
+	if err != nil {
+		log.Error().Err(err).Msg("QuotaGet json.Marshal error")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(quotaBZ)
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+
+	"shortURL/internal/midware"
+	"shortURL/internal/storage"
+)
+
+// URLsRestore метод восстанавливает удаленные адреса пользователя, если с момента удаления не истекло окно восстановления.
+// Результат возвращается для каждого ключа, адреса, которые нельзя восстановить, содержат описание ошибки.
+func (h *Handler) URLsRestore(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(midware.UserID).(string)
+	if !ok {
+		http.Error(w, "userID empty", http.StatusUnauthorized)
+		return
+	}
+	var keys []string
+	if err := json.NewDecoder(r.Body).Decode(&keys); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(keys) == 0 {
+		http.Error(w, "restore keys empty", http.StatusBadRequest)
+		return
+	}
+	results, err := storage.RestoreUserURLs(r.Context(), h.strg, keys, userID, h.cfg.RestoreWindow)
+	if writeQuota(w, err) || writeTimeout(w, err) {
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("URLsRestore storage error")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resultsBZ, err := json.Marshal(results)
+	if err != nil {
+		log.Error().Err(err).Msg("URLsRestore json.Marshal error")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(resultsBZ)
+}
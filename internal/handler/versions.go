@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"shortURL/internal/midware"
+	"shortURL/internal/storage"
+)
+
+type rollbackURL struct {
+	Version int `json:"version"`
+}
+
+// URLUpdate метод заменяет исходный адрес короткой ссылки владельца и возвращает новую версию адреса.
+func (h *Handler) URLUpdate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(midware.UserID).(string)
+	if !ok {
+		http.Error(w, "userID empty", http.StatusUnauthorized)
+		return
+	}
+	var addr postURL
+	if err := json.NewDecoder(r.Body).Decode(&addr); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if _, err := url.Parse(addr.GetURL); addr.GetURL == "" || err != nil {
+		http.Error(w, "Wrong address!", http.StatusBadRequest)
+		return
+	}
+	v, err := h.strg.UpdateURL(r.Context(), chi.URLParam(r, "id"), userID, addr.GetURL)
+	if writeVersionError(w, err) {
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("URLUpdate storage error")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	versionBZ, err := json.Marshal(v)
+	if err != nil {
+		log.Error().Err(err).Msg("URLUpdate json.Marshal error")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(versionBZ)
+}
+
+// URLHistoryGet метод возвращает владельцу историю исходных адресов короткой ссылки.
+func (h *Handler) URLHistoryGet(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(midware.UserID).(string)
+	if !ok {
+		http.Error(w, "userID empty", http.StatusUnauthorized)
+		return
+	}
+	history, err := h.strg.URLHistory(r.Context(), chi.URLParam(r, "id"), userID)
+	if writeVersionError(w, err) {
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("URLHistoryGet storage error")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	historyBZ, err := json.Marshal(history)
+	if err != nil {
+		log.Error().Err(err).Msg("URLHistoryGet json.Marshal error")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(historyBZ)
+}
+
+// URLRollback метод возвращает короткой ссылке исходный адрес прежней версии.
+// Откат добавляет в историю новую версию, которая и возвращается в ответе.
+func (h *Handler) URLRollback(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(midware.UserID).(string)
+	if !ok {
+		http.Error(w, "userID empty", http.StatusUnauthorized)
+		return
+	}
+	var rollback rollbackURL
+	if err := json.NewDecoder(r.Body).Decode(&rollback); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if rollback.Version <= 0 {
+		http.Error(w, "version must be positive", http.StatusBadRequest)
+		return
+	}
+	v, err := storage.RollbackURL(r.Context(), h.strg, chi.URLParam(r, "id"), userID, rollback.Version)
+	if writeVersionError(w, err) {
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("URLRollback storage error")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	versionBZ, err := json.Marshal(v)
+	if err != nil {
+		log.Error().Err(err).Msg("URLRollback json.Marshal error")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(versionBZ)
+}
+
+// writeVersionError функция отвечает клиенту на ошибку изменения или чтения истории короткой ссылки.
+func writeVersionError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, storage.ErrNoContent):
+		http.Error(w, "Wrong address!", http.StatusNotFound)
+	case errors.Is(err, storage.ErrVersionNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, storage.ErrForbidden):
+		http.Error(w, "URL belongs to another user", http.StatusForbidden)
+	case errors.Is(err, storage.ErrExpired):
+		http.Error(w, "URL expired", http.StatusGone)
+	case errors.Is(err, storage.ErrGone):
+		http.Error(w, "URL deleted", http.StatusGone)
+	case errors.Is(err, storage.ErrConflict):
+		http.Error(w, "user already has a short URL for this address", http.StatusConflict)
+	default:
+		return writeTimeout(w, err)
+	}
+	return true
+}
@@ -50,6 +50,13 @@ func Cookies(next http.Handler) http.Handler {
 	})
 }
 
+// UserIDFromCookie функция возвращает ID пользователя из значения куки shortener,
+// например переданного в метаданных gRPC запроса.
+func UserIDFromCookie(value string) (string, error) {
+	c := MyCookie{cookie: http.Cookie{Name: "shortener", Value: value}}
+	return c.checkCookie()
+}
+
 // generateCookie метод генерирует новые куки.
 func (c *MyCookie) generateCookie() (string, error) {
 	key := []byte("myShortenerURL00")
@@ -93,7 +100,7 @@ func (c *MyCookie) returnID() (string, error) {
 	}
 	id := make([]byte, 16)
 	val, err := hex.DecodeString(c.cookie.Value)
-	if err != nil {
+	if err != nil || len(val) != aes.BlockSize {
 		return "", errors.New("cannot decode cookie")
 	}
 	aesblock.Decrypt(id, val)
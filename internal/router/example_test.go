@@ -28,12 +28,14 @@ func ExampleNewRouter() {
 	}
 	strg := storage.NewStorage(cfg)
 	wrkr := worker.NewWorker()
-	hndlr := handler.NewHandler(cfg, strg, wrkr)
+	clicks := worker.NewClickRecorder(cfg.ClicksQueueSize)
+	hndlr := handler.NewHandler(cfg, strg, wrkr, clicks)
 
 	router := NewRouter(hndlr)
 
 	// Запуск воркера
 	wrkr.Run(strg, cfg.DeletingBufferSize, cfg.DeletingBufferTimeout)
+	clicks.Run(strg, cfg.ClicksBufferSize, cfg.ClicksBufferTimeout)
 
 	// запуск сервера
 	listener, err := net.Listen("tcp", cfg.ServerAddress)
@@ -74,4 +76,5 @@ func ExampleNewRouter() {
 
 	// Останавливаем воркер перед выходом из приложения
 	wrkr.Stop()
+	clicks.Stop()
 }
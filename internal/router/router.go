@@ -20,14 +20,25 @@ func NewRouter(h *handler.Handler) *chi.Mux {
 
 	r.Post("/api/shorten/batch", h.BatchNewEtriesPost)
 	r.Post("/api/shorten", h.ShortenPost)
+	r.Post("/api/user/urls/import", h.URLsImport)
+	r.Post("/api/user/urls/restore", h.URLsRestore)
+	r.Post("/api/user/urls/{id}/rollback", h.URLRollback)
+	r.Post("/api/user/erasure/verify", h.ReceiptVerify)
 	r.Post("/", h.URLPost)
 
 	r.Get("/api/user/urls", h.URLsGet)
+	r.Get("/api/user/urls/export", h.URLsExport)
+	r.Get("/api/user/urls/{id}/stats", h.URLStatsGet)
+	r.Get("/api/user/urls/{id}/history", h.URLHistoryGet)
+	r.Get("/api/user/quota", h.QuotaGet)
 	r.Get("/api/internal/stats", h.StatsGet)
 	r.Get("/{id}", h.IDGet)
 	r.Get("/ping", h.PingGet)
 
+	r.Patch("/api/user/urls/{id}", h.URLUpdate)
+
 	r.Delete("/api/user/urls", h.URLsDelete)
+	r.Delete("/api/user", h.UserErase)
 
 	return r
 }
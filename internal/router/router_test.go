@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strconv"
 	"testing"
 	"time"
 
@@ -41,6 +42,7 @@ type test struct {
 
 type postURLs struct {
 	GetURL string `json:"url,omitempty"`
+	Alias  string `json:"alias,omitempty"`
 	SetURL string `json:"result,omitempty"`
 }
 
@@ -51,9 +53,11 @@ func TestRouter(t *testing.T) {
 	require.NoError(t, err)
 	storage := storage.NewStorage(cnfg)
 	deletingWorker := worker.NewWorker()
-	handlers := handler.NewHandler(cnfg, storage, deletingWorker)
+	clickRecorder := worker.NewClickRecorder(cnfg.ClicksQueueSize)
+	handlers := handler.NewHandler(cnfg, storage, deletingWorker, clickRecorder)
 	router := NewRouter(handlers)
 	deletingWorker.Run(storage, cnfg.DeletingBufferSize, cnfg.DeletingBufferTimeout)
+	clickRecorder.Run(storage, cnfg.ClicksBufferSize, 50*time.Millisecond)
 	listener, err := net.Listen("tcp", cnfg.ServerAddress)
 	if err != nil {
 		log.Fatal(err)
@@ -115,11 +119,30 @@ func TestRouter(t *testing.T) {
 	}
 	multiURL(testServer, t)
 
+	aliasURL(testServer, t)
+
+	expiringURL(testServer, t)
+
+	clickStats(testServer, t)
+
 	DeletedURL(testServer, t)
 
+	exportImport(testServer, t)
+
+	restoreURL(testServer, t)
+
+	editURL(testServer, t)
+
+	listURLs(testServer, t)
+
+	eraseUser(testServer, t)
+
 	getStats(testServer, t)
 
+	userQuota(testServer, t, cnfg)
+
 	deletingWorker.Stop()
+	clickRecorder.Stop()
 	log.Println("Done")
 
 }
@@ -225,6 +248,8 @@ func multiURL(ts *httptest.Server, t *testing.T) {
 		CorrID    string `json:"correlation_id"`
 		OriginURL string `json:"original_url,omitempty"`
 		ShortURL  string `json:"short_url,omitempty"`
+		Status    string `json:"status,omitempty"`
+		Error     string `json:"error,omitempty"`
 	}
 	t.Run("MultiURL", func(t *testing.T) {
 		multi := []multiURL{
@@ -236,6 +261,13 @@ func multiURL(ts *httptest.Server, t *testing.T) {
 				CorrID:    "def456",
 				OriginURL: "/postgrespro.ru/docs/postgrespro/13/sql-syntax",
 			},
+			{
+				CorrID:    "ghi789",
+				OriginURL: "/github.com/Yandex-Practicum/go-autotests",
+			},
+			{
+				CorrID: "jkl012",
+			},
 		}
 		multiURLsBZ, err := json.Marshal(multi)
 		if err != nil {
@@ -252,13 +284,187 @@ func multiURL(ts *httptest.Server, t *testing.T) {
 		require.NoError(t, err)
 		err = result.Body.Close()
 		require.NoError(t, err)
-		multis := make([]multiURL, 2)
+		multis := make([]multiURL, 0, len(multi))
 		err = json.Unmarshal(userResult1, &multis)
 		require.NoError(t, err)
+		require.Len(t, multis, len(multi))
+		assert.Equal(t, "created", multis[0].Status)
+		assert.Equal(t, "created", multis[1].Status)
+		assert.Equal(t, "exists", multis[2].Status)
+		assert.Equal(t, multis[0].ShortURL, multis[2].ShortURL)
+		assert.Equal(t, "invalid", multis[3].Status)
+		assert.Empty(t, multis[3].ShortURL)
+		assert.NotEmpty(t, multis[3].Error)
 	})
 
 }
 
+func aliasURL(ts *httptest.Server, t *testing.T) {
+	t.Run("AliasURL", func(t *testing.T) {
+		// псевдонимы уникальны для каждого запуска, т.к. хранилище может быть постоянным
+		suffix := strconv.FormatInt(time.Now().UnixNano()%1e9, 36)
+		tests := []struct {
+			name       string
+			req        postURLs
+			statusCode int
+		}{
+			{name: "new alias", req: postURLs{GetURL: "/pkg.go.dev/errors", Alias: "go-errors-" + suffix}, statusCode: 201},
+			{name: "taken alias", req: postURLs{GetURL: "/pkg.go.dev/fmt", Alias: "go-errors-" + suffix}, statusCode: 409},
+			{name: "reserved alias", req: postURLs{GetURL: "/pkg.go.dev/fmt", Alias: "api"}, statusCode: 400},
+			{name: "wrong alphabet", req: postURLs{GetURL: "/pkg.go.dev/fmt", Alias: "go/fmt"}, statusCode: 400},
+			{name: "too short", req: postURLs{GetURL: "/pkg.go.dev/fmt", Alias: "go"}, statusCode: 400},
+		}
+		for _, tt := range tests {
+			reqBz, err := json.Marshal(tt.req)
+			require.NoError(t, err)
+			result, err := http.Post(ts.URL+"/api/shorten", "application/json", bytes.NewReader(reqBz))
+			require.NoError(t, err)
+			assert.Equal(t, tt.statusCode, result.StatusCode, tt.name)
+			err = result.Body.Close()
+			require.NoError(t, err)
+		}
+
+		request, err := http.NewRequest(http.MethodGet, ts.URL+"/go-errors-"+suffix, nil)
+		require.NoError(t, err)
+		result, err := http.DefaultTransport.RoundTrip(request)
+		require.NoError(t, err)
+		assert.Equal(t, 307, result.StatusCode)
+		assert.Equal(t, "/pkg.go.dev/errors", result.Header.Get("Location"))
+		err = result.Body.Close()
+		require.NoError(t, err)
+
+		result, err = http.Post(ts.URL+"/?alias=go-strconv-"+suffix, "text/plain", bytes.NewReader([]byte(`/pkg.go.dev/strconv`)))
+		require.NoError(t, err)
+		userResult, err := io.ReadAll(result.Body)
+		require.NoError(t, err)
+		err = result.Body.Close()
+		require.NoError(t, err)
+		assert.Equal(t, 201, result.StatusCode)
+		assert.Equal(t, ts.URL+"/go-strconv-"+suffix, string(userResult))
+	})
+}
+
+func expiringURL(ts *httptest.Server, t *testing.T) {
+	t.Run("ExpiringURL", func(t *testing.T) {
+		type expiringURL struct {
+			GetURL    string     `json:"url,omitempty"`
+			ExpiresAt *time.Time `json:"expires_at,omitempty"`
+			TTL       int64      `json:"ttl,omitempty"`
+			SetURL    string     `json:"result,omitempty"`
+		}
+		past := time.Now().Add(-time.Minute)
+		soon := time.Now().Add(300 * time.Millisecond)
+		tests := []struct {
+			name       string
+			req        expiringURL
+			statusCode int
+		}{
+			{name: "expires_at in the past", req: expiringURL{GetURL: "/pkg.go.dev/time", ExpiresAt: &past}, statusCode: 400},
+			{name: "negative ttl", req: expiringURL{GetURL: "/pkg.go.dev/time", TTL: -1}, statusCode: 400},
+			{name: "both expires_at and ttl", req: expiringURL{GetURL: "/pkg.go.dev/time", ExpiresAt: &soon, TTL: 10}, statusCode: 400},
+			{name: "expires soon", req: expiringURL{GetURL: "/pkg.go.dev/time", ExpiresAt: &soon}, statusCode: 201},
+		}
+		var res expiringURL
+		for _, tt := range tests {
+			reqBz, err := json.Marshal(tt.req)
+			require.NoError(t, err)
+			result, err := http.Post(ts.URL+"/api/shorten", "application/json", bytes.NewReader(reqBz))
+			require.NoError(t, err)
+			assert.Equal(t, tt.statusCode, result.StatusCode, tt.name)
+			if result.StatusCode == 201 {
+				err = json.NewDecoder(result.Body).Decode(&res)
+				require.NoError(t, err)
+			}
+			err = result.Body.Close()
+			require.NoError(t, err)
+		}
+		require.NotNil(t, res.ExpiresAt)
+
+		request, err := http.NewRequest(http.MethodGet, res.SetURL, nil)
+		require.NoError(t, err)
+		result, err := http.DefaultTransport.RoundTrip(request)
+		require.NoError(t, err)
+		assert.Equal(t, 307, result.StatusCode)
+		err = result.Body.Close()
+		require.NoError(t, err)
+
+		time.Sleep(time.Until(soon))
+		result, err = http.DefaultTransport.RoundTrip(request)
+		require.NoError(t, err)
+		assert.Equal(t, 410, result.StatusCode)
+		err = result.Body.Close()
+		require.NoError(t, err)
+	})
+}
+
+func clickStats(ts *httptest.Server, t *testing.T) {
+	t.Run("ClickStats", func(t *testing.T) {
+		alias := "clicks-" + strconv.FormatInt(time.Now().UnixNano()%1e9, 36)
+		reqBz, err := json.Marshal(postURLs{GetURL: "/pkg.go.dev/net/http", Alias: alias})
+		require.NoError(t, err)
+		result, err := http.Post(ts.URL+"/api/shorten", "application/json", bytes.NewReader(reqBz))
+		require.NoError(t, err)
+		assert.Equal(t, 201, result.StatusCode)
+		var c http.Cookie
+		for _, cookie := range result.Cookies() {
+			if cookie.Name == "shortener" {
+				c = *cookie
+				break
+			}
+		}
+		err = result.Body.Close()
+		require.NoError(t, err)
+
+		for _, ip := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.1"} {
+			request, err := http.NewRequest(http.MethodGet, ts.URL+"/"+alias, nil)
+			require.NoError(t, err)
+			request.Header.Set("X-Real-IP", ip)
+			request.Header.Set("Referer", "https://example.com/")
+			result, err := http.DefaultTransport.RoundTrip(request)
+			require.NoError(t, err)
+			assert.Equal(t, 307, result.StatusCode)
+			err = result.Body.Close()
+			require.NoError(t, err)
+		}
+
+		getStats := func(path string, cookie *http.Cookie) (*http.Response, storage.ClickStats) {
+			var stats storage.ClickStats
+			request, err := http.NewRequest(http.MethodGet, ts.URL+path, nil)
+			require.NoError(t, err)
+			if cookie != nil {
+				request.AddCookie(cookie)
+			}
+			result, err := http.DefaultClient.Do(request)
+			require.NoError(t, err)
+			if result.StatusCode == 200 {
+				err = json.NewDecoder(result.Body).Decode(&stats)
+				require.NoError(t, err)
+			}
+			err = result.Body.Close()
+			require.NoError(t, err)
+			return result, stats
+		}
+
+		// переходы сохраняются в фоне, поэтому ожидаем их появления в статистике
+		var stats storage.ClickStats
+		require.Eventually(t, func() bool {
+			var result *http.Response
+			result, stats = getStats("/api/user/urls/"+alias+"/stats?interval=hour", &c)
+			return result.StatusCode == 200 && stats.Total == 3
+		}, 2*time.Second, 50*time.Millisecond)
+		assert.Equal(t, 2, stats.Visitors)
+		assert.Equal(t, storage.IntervalHour, stats.Interval)
+		require.NotEmpty(t, stats.Series)
+
+		result, _ = getStats("/api/user/urls/"+alias+"/stats?interval=week", &c)
+		assert.Equal(t, 400, result.StatusCode)
+		result, _ = getStats("/api/user/urls/"+alias+"/stats", nil)
+		assert.Equal(t, 403, result.StatusCode)
+		result, _ = getStats("/api/user/urls/"+alias+"-missing/stats", &c)
+		assert.Equal(t, 404, result.StatusCode)
+	})
+}
+
 func DeletedURL(ts *httptest.Server, t *testing.T) {
 	t.Run("DeletedURL", func(t *testing.T) {
 		tests := []struct {
@@ -356,6 +562,442 @@ func DeletedURL(ts *httptest.Server, t *testing.T) {
 	})
 }
 
+func exportImport(ts *httptest.Server, t *testing.T) {
+	type userURL struct {
+		ShortURL    string `json:"short_url,omitempty"`
+		OriginalURL string `json:"original_url"`
+		Deleted     bool   `json:"deleted"`
+	}
+	type importResult struct {
+		Row         int    `json:"row"`
+		OriginalURL string `json:"original_url"`
+		ShortURL    string `json:"short_url"`
+		Status      string `json:"status"`
+		Error       string `json:"error"`
+	}
+	newCookie := func() http.Cookie { return newUserCookie(ts, t) }
+	do := func(c http.Cookie, method, path, contentType string, body []byte) (int, string, []byte) {
+		return doRequest(ts, t, c, method, path, contentType, body)
+	}
+	t.Run("ExportImport", func(t *testing.T) {
+		c := newCookie()
+		code, _, data := do(c, http.MethodPost, "/api/user/urls/import", "application/json", []byte(`[
+			{"original_url": "/pkg.go.dev/encoding/csv"},
+			{"original_url": "/pkg.go.dev/encoding/json", "deleted": true},
+			{"original_url": "/pkg.go.dev/encoding/csv"},
+			{"original_url": ""},
+			{"original_url": "/pkg.go.dev/mime", "deleted": "yes"}
+		]`))
+		require.Equal(t, 200, code)
+		results := make([]importResult, 0)
+		require.NoError(t, json.Unmarshal(data, &results))
+		require.Len(t, results, 5)
+		for i, status := range []string{"created", "created", "exists", "invalid", "invalid"} {
+			assert.Equal(t, i+1, results[i].Row)
+			assert.Equal(t, status, results[i].Status, results[i].Error)
+		}
+		assert.Equal(t, results[0].ShortURL, results[2].ShortURL)
+
+		code, contentType, data := do(c, http.MethodGet, "/api/user/urls/export", "", nil)
+		require.Equal(t, 200, code)
+		assert.Equal(t, "application/json; charset=utf-8", contentType)
+		exported := make([]userURL, 0)
+		require.NoError(t, json.Unmarshal(data, &exported))
+		require.Len(t, exported, 2)
+		deleted := make(map[string]bool)
+		for _, u := range exported {
+			deleted[u.OriginalURL] = u.Deleted
+		}
+		assert.Equal(t, map[string]bool{"/pkg.go.dev/encoding/csv": false, "/pkg.go.dev/encoding/json": true}, deleted)
+
+		code, contentType, csvData := do(c, http.MethodGet, "/api/user/urls/export?format=csv", "", nil)
+		require.Equal(t, 200, code)
+		assert.Equal(t, "text/csv; charset=utf-8", contentType)
+		assert.True(t, bytes.HasPrefix(csvData, []byte("short_url,original_url,deleted,expires_at\n")))
+		assert.Equal(t, 3, bytes.Count(csvData, []byte("\n")))
+
+		code, _, _ = do(c, http.MethodGet, "/api/user/urls/export?format=xml", "", nil)
+		assert.Equal(t, 400, code)
+
+		// выгрузка в CSV загружается другим пользователем с сохранением признака удаления
+		other := newCookie()
+		code, _, data = do(other, http.MethodPost, "/api/user/urls/import", "text/csv", csvData)
+		require.Equal(t, 200, code)
+		results = results[:0]
+		require.NoError(t, json.Unmarshal(data, &results))
+		require.Len(t, results, 2)
+		for _, res := range results {
+			assert.Equal(t, "created", res.Status, res.Error)
+			request, err := http.NewRequest(http.MethodGet, res.ShortURL, nil)
+			require.NoError(t, err)
+			result, err := http.DefaultTransport.RoundTrip(request)
+			require.NoError(t, err)
+			require.NoError(t, result.Body.Close())
+			wantCode := 307
+			if deleted[res.OriginalURL] {
+				wantCode = 410
+			}
+			assert.Equal(t, wantCode, result.StatusCode)
+		}
+
+		// после удаления данных пользователь загружает выгрузку с прежними короткими ссылками
+		code, _, _ = do(c, http.MethodDelete, "/api/user", "", nil)
+		require.Equal(t, 200, code)
+		code, _, data = do(c, http.MethodPost, "/api/user/urls/import", "text/csv", csvData)
+		require.Equal(t, 200, code)
+		results = results[:0]
+		require.NoError(t, json.Unmarshal(data, &results))
+		require.Len(t, results, 2)
+		for _, res := range results {
+			assert.Equal(t, "created", res.Status, res.Error)
+			assert.True(t, bytes.Contains(csvData, []byte(res.ShortURL+",")), res.ShortURL)
+		}
+	})
+}
+
+func restoreURL(ts *httptest.Server, t *testing.T) {
+	t.Run("RestoreURL", func(t *testing.T) {
+		c := newUserCookie(ts, t)
+		code, _, data := doRequest(ts, t, c, http.MethodPost, "/", "text/plain", []byte("/pkg.go.dev/context"))
+		require.Equal(t, 201, code)
+		shortURL := string(data)
+		key := string(data[bytes.LastIndex(data, []byte(`/`))+1:])
+		keysBZ, err := json.Marshal([]string{key, "missing-key"})
+		require.NoError(t, err)
+		code, _, _ = doRequest(ts, t, c, http.MethodDelete, "/api/user/urls", "application/json", keysBZ)
+		require.Equal(t, 202, code)
+		time.Sleep(300 * time.Millisecond)
+
+		// чужой адрес не восстанавливается
+		code, _, data = doRequest(ts, t, newUserCookie(ts, t), http.MethodPost, "/api/user/urls/restore", "application/json", keysBZ)
+		require.Equal(t, 200, code)
+		assert.JSONEq(t, `[{"key":"`+key+`","status":"not_found","error":"short URL not found"},
+			{"key":"missing-key","status":"not_found","error":"short URL not found"}]`, string(data))
+		request, err := http.NewRequest(http.MethodGet, shortURL, nil)
+		require.NoError(t, err)
+		result, err := http.DefaultTransport.RoundTrip(request)
+		require.NoError(t, err)
+		require.NoError(t, result.Body.Close())
+		assert.Equal(t, 410, result.StatusCode)
+
+		code, contentType, data := doRequest(ts, t, c, http.MethodPost, "/api/user/urls/restore", "application/json", keysBZ)
+		require.Equal(t, 200, code)
+		assert.Equal(t, "application/json; charset=utf-8", contentType)
+		assert.JSONEq(t, `[{"key":"`+key+`","status":"restored"},
+			{"key":"missing-key","status":"not_found","error":"short URL not found"}]`, string(data))
+		result, err = http.DefaultTransport.RoundTrip(request)
+		require.NoError(t, err)
+		require.NoError(t, result.Body.Close())
+		assert.Equal(t, 307, result.StatusCode)
+		assert.Equal(t, "/pkg.go.dev/context", result.Header.Get("Location"))
+
+		code, _, _ = doRequest(ts, t, c, http.MethodPost, "/api/user/urls/restore", "application/json", []byte("[]"))
+		assert.Equal(t, 400, code)
+		code, _, _ = doRequest(ts, t, c, http.MethodPost, "/api/user/urls/restore", "application/json", []byte("keys"))
+		assert.Equal(t, 400, code)
+	})
+}
+
+func editURL(ts *httptest.Server, t *testing.T) {
+	type version struct {
+		Version   int        `json:"version"`
+		URL       string     `json:"original_url"`
+		CreatedAt *time.Time `json:"created_at"`
+	}
+	t.Run("EditURL", func(t *testing.T) {
+		c := newUserCookie(ts, t)
+		code, _, data := doRequest(ts, t, c, http.MethodPost, "/", "text/plain", []byte("/pkg.go.dev/errors"))
+		require.Equal(t, 201, code)
+		shortURL := string(data)
+		path := "/api/user/urls/" + string(data[bytes.LastIndex(data, []byte(`/`))+1:])
+
+		code, contentType, data := doRequest(ts, t, c, http.MethodPatch, path, "application/json", []byte(`{"url":"/pkg.go.dev/fmt"}`))
+		require.Equal(t, 200, code)
+		assert.Equal(t, "application/json; charset=utf-8", contentType)
+		var v version
+		require.NoError(t, json.Unmarshal(data, &v))
+		assert.Equal(t, 2, v.Version)
+		assert.Equal(t, "/pkg.go.dev/fmt", v.URL)
+		assert.NotNil(t, v.CreatedAt)
+		request, err := http.NewRequest(http.MethodGet, shortURL, nil)
+		require.NoError(t, err)
+		result, err := http.DefaultTransport.RoundTrip(request)
+		require.NoError(t, err)
+		require.NoError(t, result.Body.Close())
+		assert.Equal(t, 307, result.StatusCode)
+		assert.Equal(t, "/pkg.go.dev/fmt", result.Header.Get("Location"))
+
+		// изменять ссылку может только владелец
+		other := newUserCookie(ts, t)
+		code, _, _ = doRequest(ts, t, other, http.MethodPatch, path, "application/json", []byte(`{"url":"/pkg.go.dev/io"}`))
+		assert.Equal(t, 403, code)
+		code, _, _ = doRequest(ts, t, other, http.MethodGet, path+"/history", "", nil)
+		assert.Equal(t, 403, code)
+		code, _, _ = doRequest(ts, t, c, http.MethodPatch, path, "application/json", []byte(`{"url":""}`))
+		assert.Equal(t, 400, code)
+		code, _, _ = doRequest(ts, t, c, http.MethodPatch, "/api/user/urls/missing-key", "application/json", []byte(`{"url":"/pkg.go.dev/io"}`))
+		assert.Equal(t, 404, code)
+
+		code, _, data = doRequest(ts, t, c, http.MethodPost, path+"/rollback", "application/json", []byte(`{"version":1}`))
+		require.Equal(t, 200, code)
+		require.NoError(t, json.Unmarshal(data, &v))
+		assert.Equal(t, 3, v.Version)
+		assert.Equal(t, "/pkg.go.dev/errors", v.URL)
+		code, _, _ = doRequest(ts, t, c, http.MethodPost, path+"/rollback", "application/json", []byte(`{"version":7}`))
+		assert.Equal(t, 404, code)
+
+		code, _, data = doRequest(ts, t, c, http.MethodGet, path+"/history", "", nil)
+		require.Equal(t, 200, code)
+		var history []version
+		require.NoError(t, json.Unmarshal(data, &history))
+		require.Len(t, history, 3)
+		for i, fURL := range []string{"/pkg.go.dev/errors", "/pkg.go.dev/fmt", "/pkg.go.dev/errors"} {
+			assert.Equal(t, i+1, history[i].Version)
+			assert.Equal(t, fURL, history[i].URL)
+		}
+		result, err = http.DefaultTransport.RoundTrip(request)
+		require.NoError(t, err)
+		require.NoError(t, result.Body.Close())
+		assert.Equal(t, "/pkg.go.dev/errors", result.Header.Get("Location"))
+	})
+}
+
+func listURLs(ts *httptest.Server, t *testing.T) {
+	type userURL struct {
+		ShortURL    string     `json:"short_url"`
+		OriginalURL string     `json:"original_url"`
+		Deleted     bool       `json:"deleted"`
+		CreatedAt   *time.Time `json:"created_at"`
+		UpdatedAt   *time.Time `json:"updated_at"`
+		DeletedAt   *time.Time `json:"deleted_at"`
+	}
+	t.Run("ListURLs", func(t *testing.T) {
+		c := newUserCookie(ts, t)
+		links := make([]string, 0, 3)
+		for _, fURL := range []string{"/pkg.go.dev/io", "/pkg.go.dev/io/fs", "/pkg.go.dev/os"} {
+			code, _, data := doRequest(ts, t, c, http.MethodPost, "/", "text/plain", []byte(fURL))
+			require.Equal(t, 201, code)
+			links = append(links, string(data))
+			time.Sleep(2 * time.Millisecond)
+		}
+		list := func(query string) (int, []userURL, string) {
+			t.Helper()
+			request, err := http.NewRequest(http.MethodGet, ts.URL+"/api/user/urls"+query, nil)
+			require.NoError(t, err)
+			request.AddCookie(&c)
+			result, err := http.DefaultClient.Do(request)
+			require.NoError(t, err)
+			defer result.Body.Close()
+			var page []userURL
+			if result.StatusCode == http.StatusOK {
+				require.NoError(t, json.NewDecoder(result.Body).Decode(&page))
+			}
+			return result.StatusCode, page, result.Header.Get("X-Next-Cursor")
+		}
+
+		code, page, cursor := list("?limit=2")
+		require.Equal(t, 200, code)
+		require.Len(t, page, 2)
+		assert.Equal(t, links[2], page[0].ShortURL)
+		assert.Equal(t, links[1], page[1].ShortURL)
+		require.NotEmpty(t, cursor)
+		code, page, cursor = list("?limit=2&cursor=" + cursor)
+		require.Equal(t, 200, code)
+		require.Len(t, page, 1)
+		assert.Equal(t, links[0], page[0].ShortURL)
+		assert.Empty(t, cursor)
+
+		code, page, _ = list("?order=asc&contains=/io")
+		require.Equal(t, 200, code)
+		require.Len(t, page, 2)
+		assert.Equal(t, links[0], page[0].ShortURL)
+		assert.Equal(t, links[1], page[1].ShortURL)
+
+		keysBZ, err := json.Marshal([]string{links[0][bytes.LastIndex([]byte(links[0]), []byte(`/`))+1:]})
+		require.NoError(t, err)
+		code, _, _ = doRequest(ts, t, c, http.MethodDelete, "/api/user/urls", "application/json", keysBZ)
+		require.Equal(t, 202, code)
+		time.Sleep(300 * time.Millisecond)
+		code, page, _ = list("")
+		require.Equal(t, 200, code)
+		assert.Len(t, page, 2)
+		code, page, _ = list("?include_deleted=true&order=asc")
+		require.Equal(t, 200, code)
+		require.Len(t, page, 3)
+		assert.True(t, page[0].Deleted)
+		require.NotNil(t, page[0].DeletedAt)
+		require.NotNil(t, page[0].UpdatedAt)
+		assert.True(t, page[0].DeletedAt.Equal(*page[0].UpdatedAt))
+		assert.False(t, page[1].Deleted)
+		assert.Nil(t, page[1].DeletedAt)
+		require.NotNil(t, page[1].CreatedAt)
+		assert.True(t, page[1].CreatedAt.Equal(*page[1].UpdatedAt))
+
+		code, _, _ = list("?contains=missing")
+		assert.Equal(t, 204, code)
+		for _, query := range []string{"?limit=0", "?limit=1001", "?order=random", "?include_deleted=maybe", "?cursor=%21"} {
+			code, _, _ = list(query)
+			assert.Equal(t, 400, code, query)
+		}
+	})
+}
+
+func eraseUser(ts *httptest.Server, t *testing.T) {
+	type receipt struct {
+		ID        string    `json:"receipt_id"`
+		UserID    string    `json:"user_id"`
+		URLs      int       `json:"erased_urls"`
+		ErasedAt  time.Time `json:"erased_at"`
+		Signature string    `json:"signature"`
+	}
+	t.Run("EraseUser", func(t *testing.T) {
+		c := newUserCookie(ts, t)
+		shortURLs := make([]string, 0, 2)
+		for _, fURL := range []string{"/pkg.go.dev/crypto/hmac", "/pkg.go.dev/crypto/sha256"} {
+			code, _, data := doRequest(ts, t, c, http.MethodPost, "/", "text/plain", []byte(fURL))
+			require.Equal(t, 201, code)
+			shortURLs = append(shortURLs, string(data))
+		}
+
+		code, contentType, data := doRequest(ts, t, c, http.MethodDelete, "/api/user", "", nil)
+		require.Equal(t, 200, code)
+		assert.Equal(t, "application/json; charset=utf-8", contentType)
+		var r receipt
+		require.NoError(t, json.Unmarshal(data, &r))
+		assert.Equal(t, 2, r.URLs)
+		assert.NotEmpty(t, r.ID)
+		assert.NotEmpty(t, r.Signature)
+
+		for _, shortURL := range shortURLs {
+			request, err := http.NewRequest(http.MethodGet, shortURL, nil)
+			require.NoError(t, err)
+			result, err := http.DefaultTransport.RoundTrip(request)
+			require.NoError(t, err)
+			require.NoError(t, result.Body.Close())
+			assert.Equal(t, 400, result.StatusCode)
+		}
+		code, _, _ = doRequest(ts, t, c, http.MethodGet, "/api/user/urls", "", nil)
+		assert.Equal(t, 204, code)
+
+		// квитанцию может проверить любой, измененная квитанция не проходит проверку
+		code, _, data = doRequest(ts, t, newUserCookie(ts, t), http.MethodPost, "/api/user/erasure/verify", "application/json", data)
+		require.Equal(t, 200, code)
+		assert.JSONEq(t, `{"valid": true}`, string(data))
+		r.URLs = 1
+		tampered, err := json.Marshal(r)
+		require.NoError(t, err)
+		code, _, data = doRequest(ts, t, c, http.MethodPost, "/api/user/erasure/verify", "application/json", tampered)
+		require.Equal(t, 200, code)
+		assert.JSONEq(t, `{"valid": false}`, string(data))
+		code, _, _ = doRequest(ts, t, c, http.MethodPost, "/api/user/erasure/verify", "application/json", []byte("receipt"))
+		assert.Equal(t, 400, code)
+	})
+}
+
+func userQuota(ts *httptest.Server, t *testing.T, cnfg *config.Config) {
+	type quota struct {
+		ActiveURLs    int        `json:"active_urls"`
+		MaxActiveURLs int        `json:"max_active_urls"`
+		Created       int        `json:"created"`
+		MaxCreated    int        `json:"max_created"`
+		WindowSeconds int64      `json:"window_seconds"`
+		ResetsAt      *time.Time `json:"resets_at"`
+	}
+	t.Run("UserQuota", func(t *testing.T) {
+		c := newUserCookie(ts, t)
+		code, contentType, data := doRequest(ts, t, c, http.MethodGet, "/api/user/quota", "", nil)
+		require.Equal(t, 200, code)
+		assert.Equal(t, "application/json; charset=utf-8", contentType)
+		var q quota
+		require.NoError(t, json.Unmarshal(data, &q))
+		assert.Equal(t, quota{WindowSeconds: int64(cnfg.QuotaWindow / time.Second)}, q)
+
+		code, _, _ = doRequest(ts, t, c, http.MethodPost, "/", "text/plain", []byte("/pkg.go.dev/golang.org/x/time/rate"))
+		require.Equal(t, 201, code)
+		_, _, data = doRequest(ts, t, c, http.MethodGet, "/api/user/quota", "", nil)
+		require.NoError(t, json.Unmarshal(data, &q))
+		assert.Equal(t, 1, q.ActiveURLs)
+		assert.Equal(t, 1, q.Created)
+		require.NotNil(t, q.ResetsAt)
+		assert.WithinDuration(t, time.Now().Add(cnfg.QuotaWindow), *q.ResetsAt, time.Minute)
+	})
+	t.Run("UserQuotaExceeded", func(t *testing.T) {
+		limited := *cnfg
+		limited.SavePlace = config.SaveMemory
+		limited.QuotaMaxURLs = 2
+		limited.QuotaMaxCreated = 3
+		h := handler.NewHandler(&limited, storage.NewStorage(&limited), worker.NewWorker(), worker.NewClickRecorder(limited.ClicksQueueSize))
+		qs := httptest.NewServer(NewRouter(h))
+		defer qs.Close()
+
+		c := newUserCookie(qs, t)
+		code, _, _ := doRequest(qs, t, c, http.MethodPost, "/", "text/plain", []byte("/pkg.go.dev/sync"))
+		require.Equal(t, 201, code)
+		code, _, _ = doRequest(qs, t, c, http.MethodPost, "/api/shorten", "application/json", []byte(`{"url": "/pkg.go.dev/sync/atomic"}`))
+		require.Equal(t, 201, code)
+		code, _, _ = doRequest(qs, t, c, http.MethodPost, "/", "text/plain", []byte("/pkg.go.dev/context"))
+		assert.Equal(t, 429, code)
+		code, _, _ = doRequest(qs, t, c, http.MethodPost, "/api/shorten", "application/json", []byte(`{"url": "/pkg.go.dev/context"}`))
+		assert.Equal(t, 429, code)
+		code, _, _ = doRequest(qs, t, c, http.MethodPost, "/api/shorten/batch", "application/json",
+			[]byte(`[{"correlation_id": "1", "original_url": "/pkg.go.dev/context"}]`))
+		assert.Equal(t, 429, code)
+
+		// повторное сокращение адреса на пределе ограничений возвращает прежнюю ссылку
+		code, _, _ = doRequest(qs, t, c, http.MethodPost, "/", "text/plain", []byte("/pkg.go.dev/sync"))
+		assert.Equal(t, 409, code)
+		code, _, _ = doRequest(qs, t, c, http.MethodPost, "/api/shorten", "application/json", []byte(`{"url": "/pkg.go.dev/sync/atomic"}`))
+		assert.Equal(t, 409, code)
+		code, _, _ = doRequest(qs, t, c, http.MethodPost, "/api/shorten/batch", "application/json",
+			[]byte(`[{"correlation_id": "1", "original_url": "/pkg.go.dev/sync"}, {"correlation_id": "2", "original_url": "/pkg.go.dev/sync/atomic"}]`))
+		assert.Equal(t, 201, code)
+
+		// ограничения другого пользователя не расходуются
+		code, _, _ = doRequest(qs, t, newUserCookie(qs, t), http.MethodPost, "/", "text/plain", []byte("/pkg.go.dev/context"))
+		assert.Equal(t, 201, code)
+
+		code, _, data := doRequest(qs, t, c, http.MethodGet, "/api/user/quota", "", nil)
+		require.Equal(t, 200, code)
+		var q quota
+		require.NoError(t, json.Unmarshal(data, &q))
+		assert.Equal(t, 2, q.ActiveURLs)
+		assert.Equal(t, 2, q.MaxActiveURLs)
+		assert.Equal(t, 2, q.Created)
+		assert.Equal(t, 3, q.MaxCreated)
+	})
+}
+
+// newUserCookie функция возвращает куки нового пользователя. Каждый новый пользователь получает свой набор адресов,
+// поэтому тесты не зависят от постоянного хранилища.
+func newUserCookie(ts *httptest.Server, t *testing.T) http.Cookie {
+	result, err := http.Get(ts.URL + "/ping")
+	require.NoError(t, err)
+	require.NoError(t, result.Body.Close())
+	for _, cookie := range result.Cookies() {
+		if cookie.Name == "shortener" {
+			return *cookie
+		}
+	}
+	t.Fatal("cookie not set")
+	return http.Cookie{}
+}
+
+// doRequest функция выполняет запрос от имени пользователя и возвращает код ответа, тип и тело ответа.
+func doRequest(ts *httptest.Server, t *testing.T, c http.Cookie, method, path, contentType string, body []byte) (int, string, []byte) {
+	request, err := http.NewRequest(method, ts.URL+path, bytes.NewReader(body))
+	require.NoError(t, err)
+	request.AddCookie(&c)
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	result, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	data, err := io.ReadAll(result.Body)
+	require.NoError(t, err)
+	require.NoError(t, result.Body.Close())
+	return result.StatusCode, result.Header.Get("Content-Type"), data
+}
+
 func getStats(ts *httptest.Server, t *testing.T) {
 	request, err := http.NewRequest(http.MethodGet, ts.URL+"/api/internal/stats", nil)
 	require.NoError(t, err)
@@ -368,7 +1010,43 @@ func getStats(ts *httptest.Server, t *testing.T) {
 	request.Header.Add("X-Real-IP", "192.168.11.22")
 	result, err = http.DefaultClient.Do(request)
 	require.NoError(t, err)
+	var stats struct {
+		URLs          int                  `json:"urls"`
+		Active        int                  `json:"active_urls"`
+		Expired       int                  `json:"expired_urls"`
+		Deleted       int                  `json:"deleted_urls"`
+		CreatedPerDay []storage.DailyCount `json:"created_per_day"`
+		TopOwners     []storage.OwnerCount `json:"top_owners"`
+		Since         *time.Time           `json:"since"`
+	}
+	require.NoError(t, json.NewDecoder(result.Body).Decode(&stats))
 	err = result.Body.Close()
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusOK, result.StatusCode)
+	assert.Equal(t, stats.URLs, stats.Active+stats.Expired+stats.Deleted)
+	assert.NotEmpty(t, stats.TopOwners)
+	assert.Nil(t, stats.Since)
+
+	for since, code := range map[string]int{"168h": http.StatusOK, "2999-01-01T00:00:00Z": http.StatusOK, "week": http.StatusBadRequest, "-1h": http.StatusBadRequest} {
+		request, err = http.NewRequest(http.MethodGet, ts.URL+"/api/internal/stats?since="+since, nil)
+		require.NoError(t, err)
+		request.Header.Add("X-Real-IP", "192.168.11.22")
+		result, err = http.DefaultClient.Do(request)
+		require.NoError(t, err)
+		data, err := io.ReadAll(result.Body)
+		require.NoError(t, err)
+		require.NoError(t, result.Body.Close())
+		require.Equal(t, code, result.StatusCode, since)
+		if code != http.StatusOK {
+			continue
+		}
+		stats.Since = nil
+		require.NoError(t, json.Unmarshal(data, &stats))
+		assert.NotNil(t, stats.Since, since)
+		if since == "2999-01-01T00:00:00Z" {
+			assert.Zero(t, stats.URLs)
+			assert.Empty(t, stats.TopOwners)
+			assert.Empty(t, stats.CreatedPerDay)
+		}
+	}
 }
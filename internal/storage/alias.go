@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Ограничения на длину пользовательского псевдонима короткой ссылки.
+// Псевдоним может совпасть со сгенерированным ключом, поэтому занятость псевдонима проверяет хранилище.
+const (
+	AliasMinLen = 3
+	AliasMaxLen = 24
+)
+
+// reservedAliases - слова, которые не могут быть псевдонимами или сгенерированными ключами, т.к. совпадают с маршрутами сервиса.
+var reservedAliases = map[string]bool{
+	"api":     true,
+	"ping":    true,
+	"healthz": true,
+	"debug":   true,
+	"metrics": true,
+	"admin":   true,
+	"static":  true,
+}
+
+// CheckAlias функция проверяет псевдоним на соответствие допустимому алфавиту, длине и списку зарезервированных слов.
+func CheckAlias(alias string) error {
+	if len(alias) < AliasMinLen || len(alias) > AliasMaxLen {
+		return fmt.Errorf("%w: length must be from %d to %d characters", ErrInvalidAlias, AliasMinLen, AliasMaxLen)
+	}
+	for _, c := range alias {
+		if !isAliasChar(c) {
+			return fmt.Errorf("%w: only latin letters, digits, '-' and '_' are allowed", ErrInvalidAlias)
+		}
+	}
+	if isReserved(alias) {
+		return fmt.Errorf("%w: %q is reserved", ErrInvalidAlias, alias)
+	}
+	return nil
+}
+
+// isReserved функция сообщает, совпадает ли ключ без учета регистра с зарезервированным словом.
+func isReserved(key string) bool {
+	return reservedAliases[strings.ToLower(key)]
+}
+
+func isAliasChar(c rune) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '-' || c == '_'
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Результаты обработки элемента batch запроса.
+const (
+	BatchCreated = "created"
+	BatchExists  = "exists"
+	BatchInvalid = "invalid"
+)
+
+// CheckBatchURL функция проверяет адрес элемента batch запроса.
+func CheckBatchURL(fURL string) error {
+	if fURL == "" {
+		return fmt.Errorf("%w: original_url is empty", ErrInvalidURL)
+	}
+	if _, err := url.Parse(fURL); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	return nil
+}
+
+// newBatchResult функция заполняет результаты batch запроса идентификаторами элементов
+// и помечает недопустимыми элементы, не прошедшие проверку. Остальные элементы обрабатываются хранилищем.
+func newBatchResult(m []MultiURL) []MultiURL {
+	r := make([]MultiURL, len(m))
+	for i, v := range m {
+		r[i].CorrID = v.CorrID
+		if v.Status == BatchInvalid {
+			r[i].Status = BatchInvalid
+			r[i].Error = v.Error
+			continue
+		}
+		if err := CheckBatchURL(v.OriginURL); err != nil {
+			r[i].Status = BatchInvalid
+			r[i].Error = err.Error()
+		}
+	}
+	return r
+}
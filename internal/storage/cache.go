@@ -0,0 +1,313 @@
+package storage
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"shortURL/internal/config"
+)
+
+// CacheStats структура со статистикой кэша коротких ссылок.
+// Hits включает попадания в записи об отсутствующих ключах, которые дополнительно считаются в NegativeHits.
+type CacheStats struct {
+	Hits          uint64 `json:"hits"`
+	NegativeHits  uint64 `json:"negative_hits"`
+	Misses        uint64 `json:"misses"`
+	Evictions     uint64 `json:"evictions"`
+	Invalidations uint64 `json:"invalidations"`
+	Size          int    `json:"size"`
+	Capacity      int    `json:"capacity"`
+}
+
+// cachingStorage кэширует записи коротких ссылок для переходов по ним.
+// Одновременные запросы отсутствующего в кэше ключа выполняют одно обращение к хранилищу. Обращение не зависит
+// от контекста запроса, который его начал, и ограничено временем timeout, поэтому отмена одного запроса
+// не прерывает ожидающие того же ключа.
+// Запись хранится вместе со сроком действия и признаком удаления, поэтому срок действия ссылки проверяется
+// при каждом переходе. Методы, изменяющие ссылки, удаляют их из кэша.
+type cachingStorage struct {
+	Storager
+	records RecordStorager
+	cache   *urlCache
+	group   singleflight.Group
+	timeout time.Duration
+}
+
+// WithCache функция возвращает хранилище, которое кэширует до size записей коротких ссылок.
+// Найденные записи хранятся ttl, записи об отсутствующих ключах - negativeTTL.
+// Чтение записи из хранилища ограничено временем timeout, нулевое значение означает отсутствие ограничения.
+func WithCache(strg RecordStorager, size int, ttl, negativeTTL, timeout time.Duration) Storager {
+	return &cachingStorage{Storager: strg, records: strg, cache: newURLCache(size, ttl, negativeTTL), timeout: timeout}
+}
+
+// RetFullURL метод возвращает полный адрес по ключу от короткой ссылки из кэша либо из хранилища.
+func (s *cachingStorage) RetFullURL(ctx context.Context, key string) (string, error) {
+	if e, ok := s.cache.get(key); ok {
+		return e.result()
+	}
+	ch := s.group.DoChan(key, func() (any, error) {
+		lookupCtx, cancel := withTimeout(context.Background(), s.timeout)
+		defer cancel()
+		token := s.cache.begin(key)
+		recs, err := s.records.LookupRecords(lookupCtx, []string{key})
+		if err != nil {
+			s.cache.abort(key, token)
+			return nil, err
+		}
+		var e cacheEntry
+		if len(recs) == 1 {
+			e.rec, e.found = recs[0], true
+		}
+		s.cache.fill(key, token, e)
+		return e, nil
+	})
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return "", res.Err
+		}
+		return res.Val.(cacheEntry).result()
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// SetShortURL метод сохраняет адрес и удаляет из кэша запись об отсутствии его ключа.
+func (s *cachingStorage) SetShortURL(ctx context.Context, fURL string, userID string, opts URLOptions, cfg *config.Config) (string, error) {
+	shortURL, err := s.Storager.SetShortURL(ctx, fURL, userID, opts, cfg)
+	keys := []string{opts.Alias}
+	if shortURL != "" {
+		keys = append(keys, strings.TrimPrefix(shortURL, cfg.BaseURL+"/"))
+	}
+	s.cache.invalidate(keys...)
+	return shortURL, err
+}
+
+// WriteMultiURL метод сохраняет пакет адресов и удаляет из кэша записи об отсутствии их ключей.
+func (s *cachingStorage) WriteMultiURL(ctx context.Context, m []MultiURL, userID string, cfg *config.Config) ([]MultiURL, error) {
+	r, err := s.Storager.WriteMultiURL(ctx, m, userID, cfg)
+	keys := make([]string, 0, len(r))
+	for _, v := range r {
+		if v.ShortURL != "" {
+			keys = append(keys, strings.TrimPrefix(v.ShortURL, cfg.BaseURL+"/"))
+		}
+	}
+	s.cache.invalidate(keys...)
+	return r, err
+}
+
+// MarkDeleted метод помечает адреса удаленными и удаляет их из кэша.
+func (s *cachingStorage) MarkDeleted(ctx context.Context, keys []string, ids []string) error {
+	err := s.Storager.MarkDeleted(ctx, keys, ids)
+	s.cache.invalidate(keys...)
+	return err
+}
+
+// PurgeDeleted метод окончательно удаляет адреса и удаляет из кэша адреса, удаленные не позднее before.
+func (s *cachingStorage) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	n, err := s.Storager.PurgeDeleted(ctx, before)
+	if n > 0 {
+		s.cache.invalidateDeleted(before)
+	}
+	return n, err
+}
+
+// EraseUser метод удаляет адреса пользователя и удаляет их из кэша.
+func (s *cachingStorage) EraseUser(ctx context.Context, userID string) ([]string, error) {
+	keys, err := s.Storager.EraseUser(ctx, userID)
+	s.cache.invalidate(keys...)
+	return keys, err
+}
+
+// RestoreURLs метод восстанавливает удаленные адреса и удаляет их из кэша.
+func (s *cachingStorage) RestoreURLs(ctx context.Context, keys []string, userID string, since time.Time) ([]RestoreResult, error) {
+	results, err := s.Storager.RestoreURLs(ctx, keys, userID, since)
+	s.cache.invalidate(keys...)
+	return results, err
+}
+
+// UpdateURL метод заменяет исходный адрес ссылки и удаляет ее из кэша.
+func (s *cachingStorage) UpdateURL(ctx context.Context, key, userID, fURL string) (*URLVersion, error) {
+	v, err := s.Storager.UpdateURL(ctx, key, userID, fURL)
+	s.cache.invalidate(key)
+	return v, err
+}
+
+// ReturnStats метод возвращает статистику хранилища вместе со статистикой кэша.
+func (s *cachingStorage) ReturnStats(ctx context.Context, since time.Time) (*stats, error) {
+	st, err := s.Storager.ReturnStats(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	cacheStats := s.cache.stats()
+	st.Cache = &cacheStats
+	return st, nil
+}
+
+// cacheEntry - запись кэша. found равно false для ключа, отсутствующего в хранилище.
+type cacheEntry struct {
+	key     string
+	rec     Record
+	found   bool
+	expires time.Time
+}
+
+// result метод возвращает адрес либо ошибку так же, как RetFullURL хранилища.
+func (e cacheEntry) result() (string, error) {
+	if !e.found {
+		return "", ErrNoContent
+	}
+	if e.rec.ExpiresAt != nil && expired(*e.rec.ExpiresAt, time.Now()) {
+		return "", ErrExpired
+	}
+	if e.rec.Deleted {
+		return "", ErrGone
+	}
+	return e.rec.Value, nil
+}
+
+// urlCache - ограниченный по количеству записей кэш с вытеснением давно не использованных записей.
+// Чтение из хранилища регистрируется в pending, и его результат сохраняется, только если ключ
+// не был изменен за время чтения.
+type urlCache struct {
+	mu          sync.Mutex
+	ll          *list.List
+	items       map[string]*list.Element
+	pending     map[string]uint64
+	seq         uint64
+	size        int
+	ttl         time.Duration
+	negativeTTL time.Duration
+	now         func() time.Time
+	st          CacheStats
+}
+
+func newURLCache(size int, ttl, negativeTTL time.Duration) *urlCache {
+	return &urlCache{
+		ll:          list.New(),
+		items:       make(map[string]*list.Element, size),
+		pending:     make(map[string]uint64),
+		size:        size,
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		now:         time.Now,
+	}
+}
+
+// get метод возвращает действующую запись кэша.
+func (c *urlCache) get(key string) (cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if ok {
+		e := el.Value.(*cacheEntry)
+		if c.now().Before(e.expires) {
+			c.ll.MoveToFront(el)
+			c.st.Hits++
+			if !e.found {
+				c.st.NegativeHits++
+			}
+			return *e, true
+		}
+		c.remove(el)
+	}
+	c.st.Misses++
+	return cacheEntry{}, false
+}
+
+// begin метод регистрирует начало чтения ключа из хранилища и возвращает его номер.
+func (c *urlCache) begin(key string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.seq++
+	c.pending[key] = c.seq
+	return c.seq
+}
+
+// abort метод снимает регистрацию чтения, завершившегося ошибкой.
+func (c *urlCache) abort(key string, token uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pending[key] == token {
+		delete(c.pending, key)
+	}
+}
+
+// fill метод сохраняет прочитанную запись, если ключ не изменялся за время чтения.
+func (c *urlCache) fill(key string, token uint64, e cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pending[key] != token {
+		return
+	}
+	delete(c.pending, key)
+	ttl := c.ttl
+	if !e.found {
+		ttl = c.negativeTTL
+	}
+	e.key, e.expires = key, c.now().Add(ttl)
+	if el, ok := c.items[key]; ok {
+		el.Value = &e
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&e)
+	for c.ll.Len() > c.size {
+		c.remove(c.ll.Back())
+		c.st.Evictions++
+	}
+}
+
+// invalidate метод удаляет записи из кэша и отменяет сохранение результатов незавершенных чтений.
+func (c *urlCache) invalidate(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		delete(c.pending, key)
+		if el, ok := c.items[key]; ok {
+			c.remove(el)
+			c.st.Invalidations++
+		}
+	}
+}
+
+// invalidateDeleted метод удаляет из кэша записи адресов, удаленных не позднее before,
+// и отменяет сохранение результатов всех незавершенных чтений.
+func (c *urlCache) invalidateDeleted(before time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pending = make(map[string]uint64)
+	for el := c.ll.Front(); el != nil; {
+		next := el.Next()
+		rec := el.Value.(*cacheEntry).rec
+		if rec.Deleted && rec.DeletedAt != nil && !rec.DeletedAt.After(before) {
+			c.remove(el)
+			c.st.Invalidations++
+		}
+		el = next
+	}
+}
+
+// remove метод удаляет элемент списка. Вызывается под блокировкой.
+func (c *urlCache) remove(el *list.Element) {
+	c.ll.Remove(el)
+	delete(c.items, el.Value.(*cacheEntry).key)
+}
+
+// stats метод возвращает статистику кэша.
+func (c *urlCache) stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	st := c.st
+	st.Size = c.ll.Len()
+	st.Capacity = c.size
+	return st
+}
@@ -0,0 +1,175 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"shortURL/internal/config"
+)
+
+// countingStorage считает чтения записей и может задерживать возврат прочитанного до закрытия gate.
+type countingStorage struct {
+	*MemoryStorage
+	lookups int32
+	gate    chan struct{}
+}
+
+func (s *countingStorage) LookupRecords(ctx context.Context, keys []string) ([]Record, error) {
+	recs, err := s.MemoryStorage.LookupRecords(ctx, keys)
+	atomic.AddInt32(&s.lookups, 1)
+	if s.gate != nil {
+		<-s.gate
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return recs, err
+}
+
+func newCountingStorage(t *testing.T, cfg *config.Config) *countingStorage {
+	strg := &countingStorage{MemoryStorage: NewMemoryStorager(NewKeyGenerator(cfg))}
+	_, err := strg.SetShortURL(context.Background(), "https://go.dev/", "user1", URLOptions{Alias: "go-dev"}, cfg)
+	require.NoError(t, err)
+	return strg
+}
+
+func TestCacheHitsAndInvalidation(t *testing.T) {
+	cfg := &config.Config{BaseURL: "http://127.0.0.1:8080", KeyLength: 8}
+	strg := newCountingStorage(t, cfg)
+	cached := WithCache(strg, 10, time.Minute, time.Minute, time.Second)
+
+	for i := 0; i < 3; i++ {
+		fURL, err := cached.RetFullURL(context.Background(), "go-dev")
+		require.NoError(t, err)
+		assert.Equal(t, "https://go.dev/", fURL)
+		_, err = cached.RetFullURL(context.Background(), "go-doc")
+		assert.ErrorIs(t, err, ErrNoContent)
+	}
+	assert.EqualValues(t, 2, strg.lookups)
+
+	// созданный ключ больше не считается отсутствующим
+	_, err := cached.SetShortURL(context.Background(), "https://go.dev/doc/", "user1", URLOptions{Alias: "go-doc"}, cfg)
+	require.NoError(t, err)
+	fURL, err := cached.RetFullURL(context.Background(), "go-doc")
+	require.NoError(t, err)
+	assert.Equal(t, "https://go.dev/doc/", fURL)
+
+	require.NoError(t, cached.MarkDeleted(context.Background(), []string{"go-dev"}, []string{"user1"}))
+	_, err = cached.RetFullURL(context.Background(), "go-dev")
+	assert.ErrorIs(t, err, ErrGone)
+	assert.EqualValues(t, 4, strg.lookups)
+
+	st, err := cached.ReturnStats(context.Background(), time.Time{})
+	require.NoError(t, err)
+	require.NotNil(t, st.Cache)
+	assert.Equal(t, CacheStats{Hits: 4, NegativeHits: 2, Misses: 4, Invalidations: 2, Size: 2, Capacity: 10}, *st.Cache)
+}
+
+func TestCacheExpiry(t *testing.T) {
+	cfg := &config.Config{BaseURL: "http://127.0.0.1:8080", KeyLength: 8}
+	strg := newCountingStorage(t, cfg)
+	_, err := strg.SetShortURL(context.Background(), "https://go.dev/blog/", "user1", URLOptions{Alias: "go-blog", ExpiresAt: time.Now().Add(50 * time.Millisecond)}, cfg)
+	require.NoError(t, err)
+	cached := WithCache(strg, 2, time.Minute, time.Minute, time.Second).(*cachingStorage)
+	now := time.Now()
+	cached.cache.now = func() time.Time { return now }
+
+	_, err = cached.RetFullURL(context.Background(), "go-blog")
+	require.NoError(t, err)
+	// срок действия ссылки проверяется при каждом переходе, даже если запись еще в кэше
+	time.Sleep(60 * time.Millisecond)
+	_, err = cached.RetFullURL(context.Background(), "go-blog")
+	assert.ErrorIs(t, err, ErrExpired)
+	assert.EqualValues(t, 1, strg.lookups)
+
+	// запись кэша устаревает по истечении ttl
+	now = now.Add(2 * time.Minute)
+	_, err = cached.RetFullURL(context.Background(), "go-blog")
+	assert.ErrorIs(t, err, ErrExpired)
+	assert.EqualValues(t, 2, strg.lookups)
+
+	// при переполнении вытесняется давно не использованная запись
+	cached.RetFullURL(context.Background(), "go-dev")
+	cached.RetFullURL(context.Background(), "missing")
+	cached.RetFullURL(context.Background(), "go-blog")
+	assert.EqualValues(t, 5, strg.lookups)
+	assert.EqualValues(t, 2, cached.cache.stats().Evictions)
+}
+
+func TestCacheSingleflight(t *testing.T) {
+	cfg := &config.Config{BaseURL: "http://127.0.0.1:8080", KeyLength: 8}
+	strg := newCountingStorage(t, cfg)
+	strg.gate = make(chan struct{})
+	cached := WithCache(strg, 10, time.Minute, time.Minute, time.Second)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fURL, err := cached.RetFullURL(context.Background(), "go-dev")
+			assert.NoError(t, err)
+			assert.Equal(t, "https://go.dev/", fURL)
+		}()
+	}
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&strg.lookups) == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(strg.gate)
+	wg.Wait()
+	assert.EqualValues(t, 1, strg.lookups)
+}
+
+func TestCacheSingleflightCanceled(t *testing.T) {
+	cfg := &config.Config{BaseURL: "http://127.0.0.1:8080", KeyLength: 8}
+	strg := newCountingStorage(t, cfg)
+	strg.gate = make(chan struct{})
+	cached := WithCache(strg, 10, time.Minute, time.Minute, time.Second)
+
+	// отмена запроса, начавшего чтение, не прерывает чтение для остальных запросов того же ключа
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		_, err := cached.RetFullURL(ctx, "go-dev")
+		first <- err
+	}()
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&strg.lookups) == 1 }, time.Second, time.Millisecond)
+	second := make(chan error)
+	go func() {
+		_, err := cached.RetFullURL(context.Background(), "go-dev")
+		second <- err
+	}()
+	cancel()
+	assert.ErrorIs(t, <-first, context.Canceled)
+	close(strg.gate)
+	assert.NoError(t, <-second)
+	assert.EqualValues(t, 1, strg.lookups)
+}
+
+func TestCacheInvalidateDuringLookup(t *testing.T) {
+	cfg := &config.Config{BaseURL: "http://127.0.0.1:8080", KeyLength: 8}
+	strg := newCountingStorage(t, cfg)
+	strg.gate = make(chan struct{})
+	cached := WithCache(strg, 10, time.Minute, time.Minute, time.Second)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := cached.RetFullURL(context.Background(), "go-dev")
+		assert.NoError(t, err)
+	}()
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&strg.lookups) == 1 }, time.Second, time.Millisecond)
+	// удаление во время чтения не должно оставить в кэше прочитанное до него состояние
+	require.NoError(t, cached.MarkDeleted(context.Background(), []string{"go-dev"}, []string{"user1"}))
+	close(strg.gate)
+	<-done
+
+	_, err := cached.RetFullURL(context.Background(), "go-dev")
+	assert.ErrorIs(t, err, ErrGone)
+	assert.EqualValues(t, 2, strg.lookups)
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Интервалы группировки переходов по коротким ссылкам.
+const (
+	IntervalHour = "hour"
+	IntervalDay  = "day"
+)
+
+// Click структура с данными о переходе по короткой ссылке.
+type Click struct {
+	Key       string    `json:"key"`
+	Time      time.Time `json:"time"`
+	Referrer  string    `json:"referrer,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	IP        string    `json:"ip,omitempty"`
+}
+
+// ClickStats структура со статистикой переходов по короткой ссылке.
+type ClickStats struct {
+	Key      string       `json:"key"`
+	Total    int          `json:"total"`
+	Visitors int          `json:"unique_visitors"`
+	Interval string       `json:"interval"`
+	Series   []ClickPoint `json:"series"`
+}
+
+// ClickPoint структура с количеством переходов за интервал, начинающийся в Time.
+type ClickPoint struct {
+	Time  time.Time `json:"time"`
+	Count int       `json:"count"`
+}
+
+// CheckInterval функция проверяет интервал группировки переходов, пустое значение означает группировку по дням.
+func CheckInterval(interval string) (string, error) {
+	switch interval {
+	case "":
+		return IntervalDay, nil
+	case IntervalHour, IntervalDay:
+		return interval, nil
+	default:
+		return "", fmt.Errorf("%w: interval must be %q or %q", ErrBadRequest, IntervalHour, IntervalDay)
+	}
+}
+
+// clickStats функция считает статистику по списку переходов.
+func clickStats(key string, clicks []Click, interval string) *ClickStats {
+	stats := ClickStats{Key: key, Total: len(clicks), Interval: interval, Series: make([]ClickPoint, 0)}
+	visitors := make(map[string]bool)
+	buckets := make(map[time.Time]int)
+	for _, c := range clicks {
+		visitors[c.IP] = true
+		buckets[truncateTime(c.Time, interval)]++
+	}
+	stats.Visitors = len(visitors)
+	for t, count := range buckets {
+		stats.Series = append(stats.Series, ClickPoint{Time: t, Count: count})
+	}
+	sort.Slice(stats.Series, func(i, j int) bool { return stats.Series[i].Time.Before(stats.Series[j].Time) })
+	return &stats
+}
+
+// truncateTime функция возвращает начало интервала, в который попадает время.
+func truncateTime(t time.Time, interval string) time.Time {
+	t = t.UTC()
+	if interval == IntervalHour {
+		return t.Truncate(time.Hour)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
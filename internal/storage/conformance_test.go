@@ -0,0 +1,55 @@
+package storage_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"shortURL/internal/config"
+	"shortURL/internal/storage"
+	"shortURL/internal/storage/storagetest"
+)
+
+// TestConformance проверяет все реализации хранилища общим набором проверок.
+// Хранилище в базе данных проверяется, если задана переменная окружения DATABASE_DSN.
+func TestConformance(t *testing.T) {
+	cfg := &config.Config{BaseURL: "http://127.0.0.1:8080", KeyGenerator: config.KeyRandom, KeyLength: 8}
+	t.Run("memory", func(t *testing.T) {
+		storagetest.Run(t, cfg, func(t *testing.T) storage.Storager {
+			return storage.NewMemoryStorager(storage.NewKeyGenerator(cfg))
+		})
+	})
+	for _, format := range []string{config.FormatJSON, config.FormatBinary} {
+		format := format
+		t.Run("file "+format, func(t *testing.T) {
+			storagetest.Run(t, cfg, func(t *testing.T) storage.Storager {
+				fileCfg := *cfg
+				fileCfg.FileStoragePath = filepath.Join(t.TempDir(), "storage")
+				fileCfg.FileFormat = format
+				return storage.NewFileStorager(&fileCfg, storage.NewKeyGenerator(&fileCfg))
+			})
+		})
+	}
+	t.Run("cache", func(t *testing.T) {
+		storagetest.Run(t, cfg, func(t *testing.T) storage.Storager {
+			return storage.WithCache(storage.NewMemoryStorager(storage.NewKeyGenerator(cfg)), 16, time.Minute, time.Second, time.Second)
+		})
+	})
+	t.Run("timeouts", func(t *testing.T) {
+		storagetest.Run(t, cfg, func(t *testing.T) storage.Storager {
+			return storage.WithTimeouts(storage.NewMemoryStorager(storage.NewKeyGenerator(cfg)), time.Second, time.Second)
+		})
+	})
+	dsn := os.Getenv("DATABASE_DSN")
+	if dsn == "" {
+		return
+	}
+	t.Run("sql", func(t *testing.T) {
+		sqlCfg := *cfg
+		sqlCfg.DatabaseDSN = dsn
+		storagetest.Run(t, &sqlCfg, func(t *testing.T) storage.Storager {
+			return storage.NewSQLStorager(&sqlCfg)
+		})
+	})
+}
@@ -0,0 +1,323 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErasureReceipt структура с подтверждением удаления всех данных пользователя.
+// Signature - HMAC-SHA256 остальных полей квитанции, по ней сервис подтверждает, что квитанция выдана им и не изменена.
+type ErasureReceipt struct {
+	ID        string    `json:"receipt_id"`
+	UserID    string    `json:"user_id"`
+	URLs      int       `json:"erased_urls"`
+	ErasedAt  time.Time `json:"erased_at"`
+	Signature string    `json:"signature"`
+}
+
+// EraseUserData функция удаляет из хранилища все адреса пользователя вместе с переходами по ним
+// и возвращает подписанную ключом key квитанцию об удалении.
+func EraseUserData(ctx context.Context, strg Storager, userID string, key []byte) (*ErasureReceipt, error) {
+	keys, err := strg.EraseUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 16)
+	if _, err = rand.Read(id); err != nil {
+		return nil, err
+	}
+	r := ErasureReceipt{ID: hex.EncodeToString(id), UserID: userID, URLs: len(keys), ErasedAt: time.Now().UTC()}
+	r.Signature = r.sign(key)
+	return &r, nil
+}
+
+// VerifyReceipt функция проверяет подпись квитанции об удалении данных.
+func VerifyReceipt(r *ErasureReceipt, key []byte) bool {
+	signature, err := hex.DecodeString(r.Signature)
+	if err != nil {
+		return false
+	}
+	expected, _ := hex.DecodeString(r.sign(key))
+	return hmac.Equal(signature, expected)
+}
+
+// sign метод возвращает подпись полей квитанции.
+func (r *ErasureReceipt) sign(key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(r.ID + "\n" + r.UserID + "\n" + strconv.Itoa(r.URLs) + "\n" + r.ErasedAt.UTC().Format(time.RFC3339Nano)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// deletedTime функция возвращает время удаления адреса. Для адресов, удаленных до появления времени удаления
+// в хранилище, используется текущее время, поэтому срок их хранения отсчитывается с момента обновления сервиса.
+func deletedTime(deletedAt *time.Time) *time.Time {
+	if deletedAt == nil || deletedAt.IsZero() {
+		now := time.Now().UTC()
+		return &now
+	}
+	return deletedAt
+}
+
+// updatedTime функция возвращает время изменения адреса. Для адресов, сохраненных до появления времени изменения,
+// им считается последнее из известных времени создания и удаления.
+func updatedTime(updatedAt, createdAt, deletedAt *time.Time) *time.Time {
+	if updatedAt != nil && !updatedAt.IsZero() {
+		return updatedAt
+	}
+	if deletedAt != nil && (createdAt == nil || deletedAt.After(*createdAt)) {
+		return deletedAt
+	}
+	return createdAt
+}
+
+// storedKeys функция возвращает ключи записей.
+func storedKeys(recs []storageStruct) []string {
+	keys := make([]string, 0, len(recs))
+	for _, rec := range recs {
+		keys = append(keys, rec.Key)
+	}
+	return keys
+}
+
+// PurgeDeleted метод окончательно удаляет адреса, удаленные не позднее before, и возвращает их количество.
+func (s *MemoryStorage) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	return len(s.purge(s.deletedKeys(before), deletedBefore(before))), nil
+}
+
+// EraseUser метод удаляет все адреса пользователя вместе с переходами по ним и возвращает их ключи.
+func (s *MemoryStorage) EraseUser(ctx context.Context, userID string) ([]string, error) {
+	return storedKeys(s.eraseUser(userID)), nil
+}
+
+// deletedBefore функция возвращает условие отбора адресов, удаленных не позднее before.
+func deletedBefore(before time.Time) func(urlRecord) bool {
+	deadline := before.UnixNano()
+	return func(r urlRecord) bool {
+		return r.deleted && r.deletedAt <= deadline
+	}
+}
+
+// deletedKeys метод возвращает ключи адресов, удаленных не позднее before.
+func (s *MemoryStorage) deletedKeys(before time.Time) []string {
+	match := deletedBefore(before)
+	keys := make([]string, 0)
+	for i := range s.urls {
+		sh := &s.urls[i]
+		sh.RLock()
+		for key, r := range sh.urls {
+			if match(r) {
+				keys = append(keys, key)
+			}
+		}
+		sh.RUnlock()
+	}
+	return keys
+}
+
+// purge метод окончательно удаляет адреса, удовлетворяющие match, вместе с переходами по ним и историей изменений
+// и возвращает удаленные.
+// Индекс пользователя, у которого не осталось адресов, удаляется.
+func (s *MemoryStorage) purge(keys []string, match func(urlRecord) bool) []storageStruct {
+	purged := make([]storageStruct, 0, len(keys))
+	for _, key := range keys {
+		r, ok := s.record(key)
+		if !ok {
+			continue
+		}
+		us := s.userShard(r.owner.id)
+		us.Lock()
+		sh := s.urlShard(key)
+		sh.Lock()
+		// до блокировки индекса пользователя запись могла быть изменена
+		if cur, ok := sh.urls[key]; ok && cur.owner == r.owner && match(cur) {
+			delete(sh.urls, key)
+			delete(sh.clicks, key)
+			delete(sh.versions, key)
+			if cur.owner.keys[cur.value] == key {
+				delete(cur.owner.keys, cur.value)
+			}
+			if len(cur.owner.keys) == 0 && us.users[cur.owner.id] == cur.owner {
+				delete(us.users, cur.owner.id)
+			}
+			purged = append(purged, storageStruct{UserID: cur.owner.id, Key: key})
+		}
+		sh.Unlock()
+		us.Unlock()
+	}
+	return purged
+}
+
+// eraseUser метод удаляет адреса пользователя, переходы по ним и индекс пользователя и возвращает удаленные адреса.
+// Просматриваются все адреса хранилища, чтобы удалить и адреса, отсутствующие в индексе пользователя.
+func (s *MemoryStorage) eraseUser(userID string) []storageStruct {
+	us := s.userShard(userID)
+	us.Lock()
+	defer us.Unlock()
+	owner, ok := us.users[userID]
+	if !ok {
+		return nil
+	}
+	erased := make([]storageStruct, 0, len(owner.keys))
+	for i := range s.urls {
+		sh := &s.urls[i]
+		sh.Lock()
+		for key, r := range sh.urls {
+			if r.owner == owner {
+				delete(sh.urls, key)
+				delete(sh.clicks, key)
+				delete(sh.versions, key)
+				erased = append(erased, storageStruct{UserID: userID, Key: key})
+			}
+		}
+		sh.Unlock()
+	}
+	delete(us.users, userID)
+	return erased
+}
+
+// PurgeDeleted метод окончательно удаляет адреса, удаленные не позднее before, дописывает в файл записи об их удалении
+// и удаляет переходы по ним из файла переходов. Прежние записи адресов убираются из файла хранилища сразу, как при
+// удалении данных пользователя.
+func (s *FileStorage) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	s.Lock()
+	purged := s.purge(s.deletedKeys(before), deletedBefore(before))
+	done := s.appendLog(purgeRecords(purged))
+	s.Unlock()
+	if err := waitDone(done); err != nil {
+		return len(purged), err
+	}
+	if err := s.dropClicks(purged); err != nil {
+		return len(purged), err
+	}
+	return len(purged), s.dropHistory(purged)
+}
+
+// EraseUser метод удаляет все адреса пользователя вместе с переходами по ним и возвращает их ключи.
+// Прежние записи пользователя остаются в файле хранилища до сжатия, поэтому файл сжимается сразу после удаления.
+// Копия файла, сохраненная при смене формата, тоже содержит адреса пользователя и удаляется.
+func (s *FileStorage) EraseUser(ctx context.Context, userID string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.Lock()
+	erased := s.eraseUser(userID)
+	done := s.appendLog(purgeRecords(erased))
+	s.Unlock()
+	if err := waitDone(done); err != nil {
+		return nil, err
+	}
+	if err := s.dropClicks(erased); err != nil {
+		return nil, err
+	}
+	if err := s.dropHistory(erased); err != nil {
+		return nil, err
+	}
+	return storedKeys(erased), nil
+}
+
+// dropHistory метод убирает прежние записи удаленных адресов из файла хранилища сжатием
+// и удаляет копию файла, сохраненную при смене формата.
+func (s *FileStorage) dropHistory(recs []storageStruct) error {
+	if len(recs) == 0 {
+		return nil
+	}
+	if err := s.compact(); err != nil {
+		return err
+	}
+	if err := os.Remove(s.path + ".bak"); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// purgeRecords функция формирует записи журнала об окончательном удалении адресов.
+func purgeRecords(recs []storageStruct) []logRecord {
+	logRecs := make([]logRecord, 0, len(recs))
+	for _, rec := range recs {
+		logRecs = append(logRecs, purgeRecord(rec))
+	}
+	return logRecs
+}
+
+// dropClicks метод переписывает файл переходов без переходов по удаленным адресам.
+func (s *FileStorage) dropClicks(recs []storageStruct) error {
+	if len(recs) == 0 {
+		return nil
+	}
+	drop := make(map[string]bool, len(recs))
+	for _, rec := range recs {
+		drop[rec.Key] = true
+	}
+	s.clicksMu.Lock()
+	defer s.clicksMu.Unlock()
+	src, err := os.Open(s.clicksPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	tmpPath := s.clicksPath + ".tmp"
+	dst, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0777)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(src)
+	encoder := json.NewEncoder(dst)
+	for decoder.More() {
+		var c Click
+		if err = decoder.Decode(&c); err != nil {
+			break
+		}
+		if drop[c.Key] {
+			continue
+		}
+		if err = encoder.Encode(&c); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = dst.Sync()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return os.Rename(tmpPath, s.clicksPath)
+}
+
+// PurgeDeleted метод окончательно удаляет адреса, удаленные не позднее before, вместе с переходами по ним
+// одним запросом и возвращает количество удаленных адресов.
+func (s *SQLStorage) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	var n int
+	err := s.Pool.QueryRow(ctx, `WITH purged AS (DELETE FROM Short_URLs WHERE deleted AND deleted_at <= $1 RETURNING key),
+		clicks AS (DELETE FROM Clicks WHERE key IN (SELECT key FROM purged)),
+		versions AS (DELETE FROM URL_Versions WHERE key IN (SELECT key FROM purged))
+		SELECT count(*) FROM purged`, before).Scan(&n)
+	return n, err
+}
+
+// EraseUser метод удаляет все адреса пользователя вместе с переходами по ним одним запросом и возвращает их ключи.
+func (s *SQLStorage) EraseUser(ctx context.Context, userID string) ([]string, error) {
+	rows, err := s.Pool.Query(ctx, `WITH erased AS (DELETE FROM Short_URLs WHERE user_id = $1 RETURNING key),
+		clicks AS (DELETE FROM Clicks WHERE key IN (SELECT key FROM erased)),
+		versions AS (DELETE FROM URL_Versions WHERE key IN (SELECT key FROM erased))
+		SELECT key FROM erased`, userID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
@@ -0,0 +1,153 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"shortURL/internal/config"
+)
+
+func TestFileStoragePurge(t *testing.T) {
+	cfg := &config.Config{BaseURL: "http://127.0.0.1:8080", FileStoragePath: filepath.Join(t.TempDir(), "storage.json"), KeyLength: 8}
+	strg := NewFileStorager(cfg, NewKeyGenerator(cfg))
+	_, err := strg.SetShortURL(context.Background(), "https://go.dev/", "user1", URLOptions{Alias: "go-dev"}, cfg)
+	require.NoError(t, err)
+	_, err = strg.SetShortURL(context.Background(), "https://go.dev/blog/", "user1", URLOptions{Alias: "go-blog"}, cfg)
+	require.NoError(t, err)
+	deletedAt := time.Now()
+	require.NoError(t, strg.MarkDeleted(context.Background(), []string{"go-dev"}, []string{"user1"}))
+	require.NoError(t, strg.SaveClicks(context.Background(), []Click{
+		{Key: "go-dev", Time: time.Now().UTC(), IP: "192.0.2.1"},
+		{Key: "go-blog", Time: time.Now().UTC(), IP: "192.0.2.2"},
+	}))
+	strg.CloseDB()
+
+	// время удаления восстанавливается из файла, срок хранения не отсчитывается заново
+	restored := NewFileStorager(cfg, NewKeyGenerator(cfg))
+	n, err := restored.PurgeDeleted(context.Background(), deletedAt.Add(-time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	n, err = restored.PurgeDeleted(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	restored.CloseDB()
+
+	// прежние записи окончательно удаленного адреса не остаются в файле хранилища
+	data, err := os.ReadFile(cfg.FileStoragePath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "go-dev")
+	assert.NotContains(t, string(data), `"https://go.dev/"`)
+
+	restored = NewFileStorager(cfg, NewKeyGenerator(cfg))
+	_, err = restored.RetFullURL(context.Background(), "go-dev")
+	assert.ErrorIs(t, err, ErrNoContent)
+	_, err = restored.ReturnClickStats(context.Background(), "go-dev", "user1", IntervalDay)
+	assert.ErrorIs(t, err, ErrNoContent)
+	st, err := restored.ReturnClickStats(context.Background(), "go-blog", "user1", IntervalDay)
+	require.NoError(t, err)
+	assert.Equal(t, 1, st.Total)
+}
+
+func TestFileStorageErase(t *testing.T) {
+	cfg := &config.Config{BaseURL: "http://127.0.0.1:8080", FileStoragePath: filepath.Join(t.TempDir(), "storage.json"), KeyLength: 8}
+	strg := NewFileStorager(cfg, NewKeyGenerator(cfg))
+	_, err := strg.SetShortURL(context.Background(), "https://go.dev/erased", "erased-user", URLOptions{Alias: "erased-key"}, cfg)
+	require.NoError(t, err)
+	_, err = strg.SetShortURL(context.Background(), "https://go.dev/kept", "user2", URLOptions{Alias: "kept-key"}, cfg)
+	require.NoError(t, err)
+	require.NoError(t, strg.MarkDeleted(context.Background(), []string{"erased-key"}, []string{"erased-user"}))
+	require.NoError(t, strg.SaveClicks(context.Background(), []Click{
+		{Key: "erased-key", Time: time.Now().UTC(), IP: "192.0.2.1"},
+		{Key: "kept-key", Time: time.Now().UTC(), IP: "192.0.2.2"},
+	}))
+
+	keys, err := strg.EraseUser(context.Background(), "erased-user")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"erased-key"}, keys)
+	strg.CloseDB()
+
+	// после удаления в файлах не остается данных пользователя
+	for _, path := range []string{cfg.FileStoragePath, cfg.FileStoragePath + ".clicks"} {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "erased")
+		assert.NotContains(t, string(data), "192.0.2.1")
+	}
+	restored := NewFileStorager(cfg, NewKeyGenerator(cfg))
+	_, err = restored.RetFullURL(context.Background(), "erased-key")
+	assert.ErrorIs(t, err, ErrNoContent)
+	fURL, err := restored.RetFullURL(context.Background(), "kept-key")
+	require.NoError(t, err)
+	assert.Equal(t, "https://go.dev/kept", fURL)
+}
+
+func TestFileStorageEraseBackup(t *testing.T) {
+	cfg := &config.Config{BaseURL: "http://127.0.0.1:8080", FileStoragePath: filepath.Join(t.TempDir(), "storage.json"), KeyLength: 8}
+	strg := NewFileStorager(cfg, NewKeyGenerator(cfg))
+	_, err := strg.SetShortURL(context.Background(), "https://go.dev/erased", "erased-user", URLOptions{Alias: "erased-key"}, cfg)
+	require.NoError(t, err)
+	strg.CloseDB()
+
+	// копия файла, оставшаяся после смены формата, удаляется вместе с данными пользователя
+	cfg.FileFormat = config.FormatBinary
+	strg = NewFileStorager(cfg, NewKeyGenerator(cfg))
+	_, err = os.Stat(cfg.FileStoragePath + ".bak")
+	require.NoError(t, err)
+	_, err = strg.EraseUser(context.Background(), "erased-user")
+	require.NoError(t, err)
+	strg.CloseDB()
+	_, err = os.Stat(cfg.FileStoragePath + ".bak")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestSaveClicksMissing(t *testing.T) {
+	cfg := &config.Config{BaseURL: "http://127.0.0.1:8080", FileStoragePath: filepath.Join(t.TempDir(), "storage.json"), KeyLength: 8}
+	strg := NewFileStorager(cfg, NewKeyGenerator(cfg))
+	_, err := strg.SetShortURL(context.Background(), "https://go.dev/erased", "erased-user", URLOptions{Alias: "erased-key"}, cfg)
+	require.NoError(t, err)
+	_, err = strg.EraseUser(context.Background(), "erased-user")
+	require.NoError(t, err)
+
+	// переход, сохраненный после удаления адреса, не попадает в файл переходов
+	require.NoError(t, strg.SaveClicks(context.Background(), []Click{{Key: "erased-key", Time: time.Now().UTC(), IP: "192.0.2.1"}}))
+	strg.CloseDB()
+	data, err := os.ReadFile(cfg.FileStoragePath + ".clicks")
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "192.0.2.1")
+}
+
+func TestErasureReceipt(t *testing.T) {
+	cfg := &config.Config{BaseURL: "http://127.0.0.1:8080", KeyLength: 8}
+	strg := NewMemoryStorager(NewKeyGenerator(cfg))
+	for _, fURL := range []string{"https://go.dev/", "https://go.dev/blog/"} {
+		_, err := strg.SetShortURL(context.Background(), fURL, "user1", URLOptions{}, cfg)
+		require.NoError(t, err)
+	}
+	key := []byte("receipt-secret")
+	receipt, err := EraseUserData(context.Background(), strg, "user1", key)
+	require.NoError(t, err)
+	assert.Equal(t, "user1", receipt.UserID)
+	assert.Equal(t, 2, receipt.URLs)
+	assert.NotEmpty(t, receipt.ID)
+	assert.True(t, VerifyReceipt(receipt, key))
+
+	// подпись сохраняется при передаче квитанции в JSON
+	data, err := json.Marshal(receipt)
+	require.NoError(t, err)
+	var decoded ErasureReceipt
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, VerifyReceipt(&decoded, key))
+
+	assert.False(t, VerifyReceipt(receipt, []byte("other-secret")))
+	decoded.URLs = 3
+	assert.False(t, VerifyReceipt(&decoded, key))
+	decoded.URLs, decoded.Signature = 2, "not hex"
+	assert.False(t, VerifyReceipt(&decoded, key))
+}
@@ -4,13 +4,22 @@ import "errors"
 
 // Переменные для передачи хэндлену идентификатора ошибки.
 var (
-	ErrNoContent     error = errors.New("StatusNoContent")
-	ErrConflict      error = errors.New("StatusConflict")
-	ErrGone          error = errors.New("StatusGone")
-	ErrUnsupported   error = errors.New("StatusUnsupportedMediaType")
-	ErrBadRequest    error = errors.New("StatusBadRequest")
-	ErrUnauthorized  error = errors.New("StatusUnauthorized")
-	ErrInternalError error = errors.New("ErrInternalServerError")
-	ErrForbidden     error = errors.New("StatusForbidden")
-	ErrUnavailable   error = errors.New("StatusServiceUnavailable")
+	ErrNoContent       error = errors.New("StatusNoContent")
+	ErrConflict        error = errors.New("StatusConflict")
+	ErrGone            error = errors.New("StatusGone")
+	ErrUnsupported     error = errors.New("StatusUnsupportedMediaType")
+	ErrBadRequest      error = errors.New("StatusBadRequest")
+	ErrUnauthorized    error = errors.New("StatusUnauthorized")
+	ErrInternalError   error = errors.New("ErrInternalServerError")
+	ErrForbidden       error = errors.New("StatusForbidden")
+	ErrUnavailable     error = errors.New("StatusServiceUnavailable")
+	ErrAliasTaken      error = errors.New("alias already taken")
+	ErrInvalidAlias    error = errors.New("invalid alias")
+	ErrKeyCollision    error = errors.New("no free short key found")
+	ErrExpired         error = errors.New("StatusGone: URL expired")
+	ErrInvalidExpiry   error = errors.New("invalid expiration time")
+	ErrInvalidURL      error = errors.New("invalid URL")
+	ErrRestoreWindow   error = errors.New("restore window has passed")
+	ErrVersionNotFound error = errors.New("version not found")
+	ErrQuotaExceeded   error = errors.New("quota exceeded")
 )
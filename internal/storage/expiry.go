@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// ExpiryTime функция вычисляет срок действия ссылки по абсолютному времени либо по времени жизни в секундах.
+// Нулевое значение означает бессрочную ссылку.
+func ExpiryTime(expiresAt *time.Time, ttl int64) (time.Time, error) {
+	if expiresAt != nil && ttl != 0 {
+		return time.Time{}, fmt.Errorf("%w: only one of expires_at and ttl may be set", ErrInvalidExpiry)
+	}
+	if ttl < 0 {
+		return time.Time{}, fmt.Errorf("%w: ttl must be positive", ErrInvalidExpiry)
+	}
+	now := time.Now()
+	if ttl > 0 {
+		return now.Add(time.Duration(ttl) * time.Second).UTC(), nil
+	}
+	if expiresAt == nil {
+		return time.Time{}, nil
+	}
+	if !expiresAt.After(now) {
+		return time.Time{}, fmt.Errorf("%w: expires_at is in the past", ErrInvalidExpiry)
+	}
+	return expiresAt.UTC(), nil
+}
+
+// expired функция проверяет, истек ли срок действия ссылки.
+func expired(expiresAt time.Time, now time.Time) bool {
+	return !expiresAt.IsZero() && !expiresAt.After(now)
+}
+
+// TimePtr функция возвращает ссылку на время либо nil для нулевого значения, например для бессрочной ссылки.
+func TimePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,192 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"shortURL/internal/config"
+)
+
+// minCompactRecords - минимальное количество записей в файле, при котором проверяется соотношение записей и адресов.
+const minCompactRecords = 1000
+
+// compaction структура с состоянием фонового сжатия файла хранилища.
+type compaction struct {
+	tail         []logRecord // записи, сделанные во время сжатия, nil вне сжатия
+	running      sync.Mutex  // одновременно выполняется не более одного сжатия
+	compactSize  int64
+	compactRatio float64
+	stop         chan struct{}
+	finished     chan struct{}
+}
+
+// runCompaction метод запускает фоновую проверку необходимости сжатия файла хранилища.
+func (s *FileStorage) runCompaction(cfg *config.Config) {
+	s.compactSize = cfg.CompactSize
+	s.compactRatio = cfg.CompactRatio
+	s.stop = make(chan struct{})
+	s.finished = make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(cfg.CompactInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				close(s.finished)
+				return
+			case <-ticker.C:
+				if !s.needCompact() {
+					continue
+				}
+				if err := s.compact(); err != nil {
+					log.Error().Err(err).Msg("FileStorage compact err")
+				}
+			}
+		}
+	}()
+}
+
+// stopCompaction метод останавливает фоновое сжатие, дожидаясь завершения текущего.
+func (s *FileStorage) stopCompaction() {
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	<-s.finished
+}
+
+// needCompact метод проверяет, превышены ли размер файла или соотношение записей в файле и сохраненных адресов.
+func (s *FileStorage) needCompact() bool {
+	s.Lock()
+	defer s.Unlock()
+	// снимок хранилища содержит по записи на адрес и на каждую версию адреса
+	live := s.count() + s.versionCount()
+	if s.logRecords <= live {
+		return false
+	}
+	if s.compactSize > 0 && s.logSize >= s.compactSize {
+		return true
+	}
+	return s.logRecords >= minCompactRecords && float64(s.logRecords) >= s.compactRatio*float64(live)
+}
+
+// compact метод переписывает файл хранилища снимком текущих адресов.
+// Снимок пишется во временный файл без блокировки хранилища, записи, сделанные за это время,
+// дописываются в его конец, после чего временный файл заменяет файл хранилища.
+func (s *FileStorage) compact() error {
+	s.running.Lock()
+	defer s.running.Unlock()
+	s.Lock()
+	recs := s.snapshot()
+	s.tail = make([]logRecord, 0)
+	s.Unlock()
+
+	tmpPath := s.path + ".compact"
+	size, err := writeSnapshot(tmpPath, s.format, recs)
+
+	s.Lock()
+	defer s.Unlock()
+	tail := s.tail
+	s.tail = nil
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	file, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_APPEND, 0777)
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	n, err := writeRecords(file, s.format, tail)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err = os.Rename(tmpPath, s.path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	syncDir(filepath.Dir(s.path))
+	if err = <-s.writer.reopen(); err != nil {
+		log.Error().Err(err).Msg("FileStorage reopen after compact err")
+	}
+	log.Info().Msgf("FileStorage compacted: %d records, %d bytes -> %d records, %d bytes",
+		s.logRecords, s.logSize, len(recs)+len(tail), size+n)
+	s.logRecords = len(recs) + len(tail)
+	s.logSize = size + n
+	return nil
+}
+
+// snapshot метод возвращает записи, из которых восстанавливается текущее состояние хранилища.
+// Вызывается под блокировкой файлового хранилища, поэтому изменения во время снимка не выполняются.
+func (s *MemoryStorage) snapshot() []logRecord {
+	recs := make([]logRecord, 0, s.count())
+	for i := range s.urls {
+		sh := &s.urls[i]
+		sh.RLock()
+		for key, r := range sh.urls {
+			recs = append(recs, setRecord(r.toStorageStruct(key)))
+			for _, v := range sh.versions[key] {
+				recs = append(recs, versionRecord(key, v))
+			}
+		}
+		sh.RUnlock()
+	}
+	return recs
+}
+
+// writeSnapshot функция записывает снимок хранилища в новый файл и возвращает его размер.
+func writeSnapshot(path, format string, recs []logRecord) (int64, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0777)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	var size int64
+	if format == config.FormatBinary {
+		if _, err = file.WriteString(binaryMagic); err != nil {
+			return 0, err
+		}
+		size = int64(len(binaryMagic))
+	}
+	// снимок пишется частями, чтобы не держать в памяти его полную копию в JSON
+	const chunk = 1000
+	for i := 0; i < len(recs); i += chunk {
+		end := i + chunk
+		if end > len(recs) {
+			end = len(recs)
+		}
+		n, err := writeRecords(file, format, recs[i:end])
+		if err != nil {
+			return 0, err
+		}
+		size += n
+	}
+	if err = file.Sync(); err != nil {
+		return 0, err
+	}
+	return size, file.Close()
+}
+
+// syncDir функция сохраняет на диск изменения каталога после переименования файла.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		log.Error().Err(err).Msg("syncDir open err")
+		return
+	}
+	defer d.Close()
+	if err = d.Sync(); err != nil {
+		log.Error().Err(err).Msg("syncDir sync err")
+	}
+}
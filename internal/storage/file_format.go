@@ -0,0 +1,323 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+
+	"github.com/rs/zerolog/log"
+
+	"shortURL/internal/config"
+)
+
+// Файл в двоичном формате начинается с binaryMagic, за которым следуют записи вида
+// [длина данных, 4 байта][CRC32-C данных, 4 байта][данные: запись журнала в JSON].
+const (
+	binaryMagic      = "SURLLOG\x01"
+	frameHeaderSize  = 8
+	maxFrameDataSize = 1 << 20
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// errDamagedRecord - ошибка чтения поврежденной или недописанной записи.
+var errDamagedRecord = errors.New("damaged record")
+
+// encodeRecords функция кодирует записи журнала в заданном формате.
+func encodeRecords(format string, recs []logRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	for i := range recs {
+		data, err := json.Marshal(&recs[i])
+		if err != nil {
+			return nil, err
+		}
+		if format == config.FormatBinary {
+			var header [frameHeaderSize]byte
+			binary.LittleEndian.PutUint32(header[:4], uint32(len(data)))
+			binary.LittleEndian.PutUint32(header[4:], crc32.Checksum(data, crcTable))
+			buf.Write(header[:])
+			buf.Write(data)
+			continue
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// logReader - структура для последовательного чтения записей журнала.
+type logReader struct {
+	file   *os.File
+	r      *bufio.Reader
+	format string
+	offset int64 // смещение конца последней прочитанной целой записи
+}
+
+// newLogReader функция определяет формат файла по его началу и возвращает читатель записей.
+// Пустой файл считается файлом в формате JSON.
+func newLogReader(file *os.File) (*logReader, error) {
+	r := logReader{file: file, r: bufio.NewReaderSize(file, 64<<10), format: config.FormatJSON}
+	magic, err := r.r.Peek(len(binaryMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if string(magic) == binaryMagic {
+		r.r.Discard(len(binaryMagic))
+		r.format = config.FormatBinary
+		r.offset = int64(len(binaryMagic))
+	}
+	return &r, nil
+}
+
+// next метод возвращает следующую запись журнала, io.EOF в конце файла или errDamagedRecord.
+func (r *logReader) next() (logRecord, error) {
+	var rec logRecord
+	var data []byte
+	var size int
+	if r.format == config.FormatBinary {
+		var header [frameHeaderSize]byte
+		n, err := io.ReadFull(r.r, header[:])
+		if errors.Is(err, io.EOF) {
+			return rec, io.EOF
+		}
+		if err != nil {
+			return rec, fmt.Errorf("%w: %d bytes of header", errDamagedRecord, n)
+		}
+		length := binary.LittleEndian.Uint32(header[:4])
+		if length > maxFrameDataSize {
+			return rec, fmt.Errorf("%w: length %d", errDamagedRecord, length)
+		}
+		data = make([]byte, length)
+		if n, err = io.ReadFull(r.r, data); err != nil {
+			return rec, fmt.Errorf("%w: %d of %d bytes", errDamagedRecord, n, length)
+		}
+		if crc32.Checksum(data, crcTable) != binary.LittleEndian.Uint32(header[4:]) {
+			return rec, fmt.Errorf("%w: checksum mismatch", errDamagedRecord)
+		}
+		size = frameHeaderSize + len(data)
+	} else {
+		for len(bytes.TrimSpace(data)) == 0 {
+			line, err := r.r.ReadBytes('\n')
+			if errors.Is(err, io.EOF) && len(bytes.TrimSpace(line)) == 0 {
+				return rec, io.EOF
+			}
+			// запись без перевода строки в конце файла недописана
+			if err != nil {
+				return rec, fmt.Errorf("%w: unterminated line", errDamagedRecord)
+			}
+			data = append(data, line...)
+		}
+		size = len(data)
+	}
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return rec, fmt.Errorf("%w: %v", errDamagedRecord, err)
+	}
+	r.offset += int64(size)
+	return rec, nil
+}
+
+// skip метод пропускает поврежденную запись, начинающуюся с текущего смещения, и продолжает чтение
+// со следующей целой записи. Возвращает количество пропущенных байт или -1, если целых записей дальше нет,
+// и количество пропущенных записей, включая недописанные.
+func (r *logReader) skip() (int64, int, error) {
+	if _, err := r.file.Seek(r.offset, io.SeekStart); err != nil {
+		return 0, 0, err
+	}
+	tail, err := io.ReadAll(r.file)
+	if err != nil {
+		return 0, 0, err
+	}
+	next := nextRecord(r.format, tail)
+	if next < 0 {
+		return -1, countRecords(r.format, tail), nil
+	}
+	r.offset += int64(next)
+	if _, err = r.file.Seek(r.offset, io.SeekStart); err != nil {
+		return 0, 0, err
+	}
+	r.r.Reset(r.file)
+	return int64(next), countRecords(r.format, tail[:next]), nil
+}
+
+// countRecords функция возвращает количество записей в поврежденных данных.
+// Для формата JSON это количество непустых строк, для двоичного - количество записей, которые удается пройти
+// по заголовкам, до первого заголовка с недопустимой длиной включительно.
+func countRecords(format string, data []byte) int {
+	n := 0
+	if format != config.FormatBinary {
+		for _, line := range bytes.Split(data, []byte{'\n'}) {
+			if len(bytes.TrimSpace(line)) > 0 {
+				n++
+			}
+		}
+		return n
+	}
+	for i := 0; i < len(data); n++ {
+		if i+frameHeaderSize > len(data) {
+			return n + 1
+		}
+		length := int(binary.LittleEndian.Uint32(data[i : i+4]))
+		if length > maxFrameDataSize {
+			return n + 1
+		}
+		i += frameHeaderSize + length
+	}
+	return n
+}
+
+// nextRecord функция возвращает смещение первой целой записи после поврежденной записи в начале tail или -1.
+// Для двоичного формата целой считается запись с верной контрольной суммой и разбираемыми данными.
+func nextRecord(format string, tail []byte) int {
+	var rec logRecord
+	if format != config.FormatBinary {
+		for i := bytes.IndexByte(tail, '\n') + 1; i > 0 && i < len(tail); {
+			end := bytes.IndexByte(tail[i:], '\n')
+			// строка без перевода строки в конце файла недописана
+			if end < 0 {
+				return -1
+			}
+			line := tail[i : i+end+1]
+			if len(bytes.TrimSpace(line)) > 0 && json.Unmarshal(line, &rec) == nil {
+				return i
+			}
+			i += end + 1
+		}
+		return -1
+	}
+	for i := 1; i+frameHeaderSize <= len(tail); i++ {
+		length := int(binary.LittleEndian.Uint32(tail[i : i+4]))
+		end := i + frameHeaderSize + length
+		if length <= maxFrameDataSize && end <= len(tail) &&
+			crc32.Checksum(tail[i+frameHeaderSize:end], crcTable) == binary.LittleEndian.Uint32(tail[i+4:i+8]) &&
+			json.Unmarshal(tail[i+frameHeaderSize:end], &rec) == nil {
+			return i
+		}
+	}
+	return -1
+}
+
+// readStorage функция восстанавливает хранилище из файла.
+// Поврежденный конец файла, например после сбоя во время записи, отбрасывается. Поврежденная запись,
+// за которой следуют целые записи, пропускается и остается в файле до сжатия. О количестве отброшенных записей
+// сообщается в журнал, оно сохраняется в lostRecords. Если формат файла отличается от заданного в конфигурации, файл переписывается в заданном формате.
+func readStorage(cfg *config.Config, fs *FileStorage) {
+	file, err := os.OpenFile(fs.path, os.O_RDONLY|os.O_CREATE, 0777)
+	if err != nil {
+		log.Fatal().Err(err).Msg("readStorage open file err")
+	}
+	r, err := newLogReader(file)
+	if err != nil {
+		log.Fatal().Err(err).Msg("readStorage read file err")
+	}
+	fs.Lock()
+	var damage error
+	var tailRecords int
+	for {
+		rec, err := r.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if errors.Is(err, errDamagedRecord) {
+			offset := r.offset
+			skipped, records, skipErr := r.skip()
+			if skipErr != nil {
+				log.Fatal().Err(skipErr).Msg("readStorage read file err")
+			}
+			fs.lostRecords += records
+			if skipped < 0 {
+				damage, tailRecords = err, records
+				break
+			}
+			log.Warn().Err(err).Msgf("readStorage damaged records at offset %d skipped: %d records, %d bytes", offset, records, skipped)
+			continue
+		}
+		if err != nil {
+			log.Fatal().Err(err).Msg("readStorage read file err")
+		}
+		fs.apply(rec)
+		fs.logRecords++
+		if n, ok := counterValue(rec.Key); ok && n > fs.lastKey {
+			fs.lastKey = n
+		}
+	}
+	fs.Unlock()
+	file.Close()
+	fs.logSize = r.offset
+
+	if damage != nil {
+		lost, err := recoverTail(fs.path, r.offset)
+		if err != nil {
+			log.Fatal().Err(err).Msg("readStorage truncate damaged tail err")
+		}
+		log.Warn().Err(damage).Msgf("readStorage damaged tail truncated at offset %d: %d records, %d bytes lost", r.offset, tailRecords, lost)
+	}
+
+	empty := r.offset == 0 || (r.format == config.FormatBinary && r.offset == int64(len(binaryMagic)))
+	switch {
+	case cfg.FileFormat == "" || cfg.FileFormat == r.format:
+	case empty:
+		header := ""
+		if cfg.FileFormat == config.FormatBinary {
+			header = binaryMagic
+		}
+		if err = os.WriteFile(fs.path, []byte(header), 0777); err != nil {
+			log.Fatal().Err(err).Msg("readStorage write header err")
+		}
+		fs.logSize = int64(len(header))
+	default:
+		if err = convertStorage(fs, r.format, cfg.FileFormat); err != nil {
+			log.Fatal().Err(err).Msg("readStorage convert file err")
+		}
+	}
+	fs.format = r.format
+	if cfg.FileFormat != "" {
+		fs.format = cfg.FileFormat
+	}
+}
+
+// recoverTail функция отрезает поврежденный конец файла, в котором нет целых записей, и возвращает его размер.
+func recoverTail(path string, offset int64) (int64, error) {
+	file, err := os.OpenFile(path, os.O_RDWR, 0777)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if err = file.Truncate(offset); err != nil {
+		return 0, err
+	}
+	return stat.Size() - offset, file.Sync()
+}
+
+// convertStorage функция однократно переписывает файл хранилища в новом формате.
+// Исходный файл сохраняется рядом с расширением .bak до первого удаления данных пользователя.
+func convertStorage(fs *FileStorage, from, to string) error {
+	fs.Lock()
+	recs := fs.snapshot()
+	fs.Unlock()
+	tmpPath := fs.path + ".convert"
+	size, err := writeSnapshot(tmpPath, to, recs)
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err = os.Rename(fs.path, fs.path+".bak"); err != nil {
+		return err
+	}
+	if err = os.Rename(tmpPath, fs.path); err != nil {
+		return err
+	}
+	log.Info().Msgf("FileStorage converted from %s to %s: %d records, previous file saved to %s", from, to, len(recs), fs.path+".bak")
+	fs.logRecords = len(recs)
+	fs.logSize = size
+	return nil
+}
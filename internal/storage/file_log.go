@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Типы записей журнала файлового хранилища.
+// Записи без типа, оставшиеся от прежнего формата файла, считаются записями opSet.
+// Время изменения адреса, отсутствующее в записях прежних версий, определяется по времени создания и удаления.
+const (
+	opSet     = "set"
+	opUpdate  = "update"
+	opDelete  = "delete"
+	opPurge   = "purge"
+	opRestore = "restore"
+	opVersion = "version"
+)
+
+// logRecord - запись журнала файлового хранилища.
+// Файл хранит последовательность записей, при запуске они применяются к хранилищу в порядке записи.
+// opSet добавляет адрес, opUpdate заменяет сохраненное состояние адреса, opDelete помечает адрес удаленным,
+// opPurge удаляет адрес из хранилища вместе с переходами по нему, opRestore снимает с адреса отметку об удалении,
+// opVersion добавляет версию в историю изменения адреса.
+type logRecord struct {
+	Op        string     `json:"op,omitempty"`
+	UserID    string     `json:"ID,omitempty"`
+	Key       string     `json:"key"`
+	Value     string     `json:"value,omitempty"`
+	Deleted   bool       `json:"deleted,omitempty"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Version   int        `json:"version,omitempty"`
+	VersionAt *time.Time `json:"version_at,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// setRecord функция формирует запись о добавлении адреса.
+func setRecord(rec storageStruct) logRecord {
+	return logRecord{Op: opSet, UserID: rec.UserID, Key: rec.Key, Value: rec.Value, Deleted: rec.Deleted, DeletedAt: rec.DeletedAt, ExpiresAt: rec.ExpiresAt, CreatedAt: rec.CreatedAt, UpdatedAt: rec.UpdatedAt}
+}
+
+// updateRecord функция формирует запись о замене сохраненного состояния адреса.
+func updateRecord(rec storageStruct) logRecord {
+	return logRecord{Op: opUpdate, UserID: rec.UserID, Key: rec.Key, Value: rec.Value, Deleted: rec.Deleted, DeletedAt: rec.DeletedAt, ExpiresAt: rec.ExpiresAt, CreatedAt: rec.CreatedAt, UpdatedAt: rec.UpdatedAt}
+}
+
+// versionRecord функция формирует запись о версии адреса.
+func versionRecord(key string, v URLVersion) logRecord {
+	return logRecord{Op: opVersion, Key: key, Value: v.URL, Version: v.Version, VersionAt: v.CreatedAt}
+}
+
+// deleteRecord функция формирует запись об удалении адреса.
+func deleteRecord(rec storageStruct) logRecord {
+	return logRecord{Op: opDelete, UserID: rec.UserID, Key: rec.Key, DeletedAt: rec.DeletedAt}
+}
+
+// restoreRecord функция формирует запись о восстановлении адреса.
+func restoreRecord(rec storageStruct) logRecord {
+	return logRecord{Op: opRestore, UserID: rec.UserID, Key: rec.Key, UpdatedAt: rec.UpdatedAt}
+}
+
+// purgeRecord функция формирует запись об окончательном удалении адреса.
+func purgeRecord(rec storageStruct) logRecord {
+	return logRecord{Op: opPurge, UserID: rec.UserID, Key: rec.Key}
+}
+
+// apply метод применяет запись журнала к хранилищу.
+func (s *MemoryStorage) apply(rec logRecord) {
+	switch rec.Op {
+	case "", opSet:
+		s.put(storageStruct{UserID: rec.UserID, Key: rec.Key, Value: rec.Value, Deleted: rec.Deleted, DeletedAt: rec.DeletedAt, ExpiresAt: rec.ExpiresAt, CreatedAt: rec.CreatedAt, UpdatedAt: rec.UpdatedAt})
+	case opUpdate:
+		if !s.update(storageStruct{Key: rec.Key, Value: rec.Value, Deleted: rec.Deleted, DeletedAt: rec.DeletedAt, ExpiresAt: rec.ExpiresAt, CreatedAt: rec.CreatedAt, UpdatedAt: rec.UpdatedAt}) {
+			log.Error().Msgf("apply update of unknown key %s", rec.Key)
+		}
+	case opDelete:
+		sh := s.urlShard(rec.Key)
+		sh.Lock()
+		if r, ok := sh.urls[rec.Key]; ok && !r.deleted {
+			r.deleted, r.deletedAt = true, deletedTime(rec.DeletedAt).UnixNano()
+			r.updated = r.deletedAt
+			sh.urls[rec.Key] = r
+		}
+		sh.Unlock()
+	case opRestore:
+		sh := s.urlShard(rec.Key)
+		sh.Lock()
+		if r, ok := sh.urls[rec.Key]; ok {
+			r.deleted, r.deletedAt = false, 0
+			if rec.UpdatedAt != nil {
+				r.updated = rec.UpdatedAt.UnixNano()
+			}
+			sh.urls[rec.Key] = r
+		}
+		sh.Unlock()
+	case opVersion:
+		s.addVersion(rec.Key, URLVersion{Version: rec.Version, URL: rec.Value, CreatedAt: rec.VersionAt})
+	case opPurge:
+		s.purge([]string{rec.Key}, func(urlRecord) bool { return true })
+	default:
+		log.Error().Msgf("apply unknown record type %q", rec.Op)
+	}
+}
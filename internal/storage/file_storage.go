@@ -1,10 +1,12 @@
 package storage
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/rs/zerolog/log"
 
@@ -12,218 +14,266 @@ import (
 )
 
 // FileStorage структура для хранения оперативных данных базы данных.
+// Оперативные данные хранятся в памяти, каждое изменение дописывается в файл.
+// Изменения выполняются под общей блокировкой, чтобы порядок записей в файле совпадал с порядком изменений,
+// чтение идет напрямую из хранилища в памяти.
 type FileStorage struct {
-	baseURL    map[string]string
-	userURL    map[string]string
-	deletedURL map[string]bool
-	sync.RWMutex
+	*MemoryStorage
+	sync.Mutex
+	path        string
+	format      string
+	clicksPath  string
+	clicksMu    sync.Mutex // блокировка файла переходов
+	writer      *fileWriter
+	closed      bool
+	logRecords  int    // количество записей в файле
+	logSize     int64  // размер файла в байтах
+	lastKey     uint64 // наибольшее значение счетчика среди ключей файла
+	lostRecords int    // количество записей, отброшенных при восстановлении из файла
+	compaction
 }
 
 // NewFileStorager метод генерирует хранилище данных.
-func NewFileStorager(cfg *config.Config) *FileStorage {
+func NewFileStorager(cfg *config.Config, keyGen KeyGenerator) *FileStorage {
 	fs := FileStorage{
-		baseURL:    make(map[string]string),
-		userURL:    make(map[string]string),
-		deletedURL: make(map[string]bool),
+		MemoryStorage: NewMemoryStorager(keyGen),
+		path:          cfg.FileStoragePath,
+		clicksPath:    cfg.FileStoragePath + ".clicks",
 	}
 	readStorage(cfg, &fs)
+	readClicks(&fs)
+	if counter, ok := keyGen.(*CounterGenerator); ok {
+		// ключи выданы не подряд, если часть адресов удалена окончательно, поэтому счетчик продолжается
+		// с наибольшего выданного ключа, а не с количества адресов
+		counter.Seed(fs.lastKey)
+	}
+	fs.writer = newFileWriter(cfg)
+	if cfg.CompactInterval > 0 {
+		fs.runCompaction(cfg)
+	}
 	return &fs
 }
 
 // SetShortURL метод генерирует ключ для короткой ссылки, проверяет его наличие и сохраняет данные.
 // Данные передаются и возвращаются текстом в теле запроса.
-func (s *FileStorage) SetShortURL(fURL, userID string, cfg *config.Config) (string, error) {
-	key := hashStr(fURL)
-	s.RLock()
-	id := s.userURL[key]
-	s.RUnlock()
-	if id == userID {
-		return cfg.BaseURL + "/" + key, ErrConflict
+// Если запись не удалось сохранить, адрес удаляется из памяти под блокировкой хранилища.
+func (s *FileStorage) SetShortURL(ctx context.Context, fURL, userID string, opts URLOptions, cfg *config.Config) (string, error) {
+	// запись ставится в очередь под блокировкой хранилища, чтобы порядок записей совпадал с порядком изменений,
+	// а ожидание записи на диск выполняется без блокировки
+	if err := ctx.Err(); err != nil {
+		return "", err
 	}
 	s.Lock()
-	s.baseURL[key] = fURL
-	s.userURL[key] = userID
-	s.deletedURL[key] = false
-	s.Unlock()
-	file, err := newWriterFile(cfg)
+	rec, err := s.addURL(ctx, fURL, userID, opts)
+	if errors.Is(err, ErrConflict) {
+		s.Unlock()
+		return cfg.BaseURL + "/" + rec.Key, err
+	}
 	if err != nil {
-		log.Fatal().Err(err).Msg("SetShortURL NewWriterFile err")
+		s.Unlock()
+		return "", err
 	}
-	defer file.close()
-	err = file.writeFile(key, userID, fURL)
-	return cfg.BaseURL + "/" + key, err
-}
-
-// RetFullURL метод возвращает полный адрес по ключу от короткой ссылки.
-func (s *FileStorage) RetFullURL(key string) (string, error) {
-	s.RLock()
-	del := s.deletedURL[key]
-	s.RUnlock()
-	if del {
-		return key, ErrGone
-	}
-	s.RLock()
-	fURL, ok := s.baseURL[key]
-	s.RUnlock()
-	if !ok {
-		return "", ErrNoContent
-	}
-	return fURL, nil
+	done := s.appendLog([]logRecord{setRecord(rec)})
+	s.Unlock()
+	if err = waitDone(done); err != nil {
+		s.dropUnsaved([]storageStruct{rec})
+		return "", err
+	}
+	return cfg.BaseURL + "/" + rec.Key, nil
 }
 
-// ReturnAllURLs метод возвращает список сокращенных адресов по ID пользователя.
-func (s *FileStorage) ReturnAllURLs(userID string, cfg *config.Config) ([]urls, error) {
-	if len(s.baseURL) == 0 {
-		return nil, ErrNoContent
-	}
-	var allURLs = make([]urls, 0)
-	for key, value := range s.baseURL {
-		s.RLock()
-		id := s.userURL[key]
-		s.RUnlock()
-		if id == userID {
-			allURLs = append(allURLs, urls{cfg.BaseURL + "/" + key, value})
-		}
-	}
-	if len(allURLs) == 0 {
-		return nil, ErrNoContent
-	}
-	return allURLs, nil
+// dropUnsaved метод удаляет из памяти добавленные адреса, записи о которых не удалось сохранить в файл.
+func (s *FileStorage) dropUnsaved(recs []storageStruct) {
+	s.Lock()
+	s.dropAdded(recs)
+	s.Unlock()
 }
 
 // CheckPing метод возвращает статус подключения к базе данных.
-func (s *FileStorage) CheckPing(P *config.Config) error {
+func (s *FileStorage) CheckPing(ctx context.Context, P *config.Config) error {
 	return errors.New("wrong DB used: file storage")
 }
 
 // WriteMultiURL метод обрабатывает, сохраняет и возвращает batch список сокращенных адресов.
-func (s *FileStorage) WriteMultiURL(m []MultiURL, userID string, cfg *config.Config) ([]MultiURL, error) {
-	r := make([]MultiURL, len(m))
-	file, err := newWriterFile(cfg)
-	if err != nil {
-		log.Fatal().Err(err).Msg("WriteMultiURL NewWriterFile err")
+// Если записи не удалось сохранить, добавленные адреса пакета удаляются из памяти.
+func (s *FileStorage) WriteMultiURL(ctx context.Context, m []MultiURL, userID string, cfg *config.Config) ([]MultiURL, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
-	defer file.close()
+	r := newBatchResult(m)
+	added := make([]storageStruct, 0, len(m))
+	recs := make([]logRecord, 0, len(m))
+	s.Lock()
 	for i, v := range m {
-		key := hashStr(v.OriginURL)
-		s.Lock()
-		s.baseURL[key] = v.OriginURL
-		s.userURL[key] = userID
-		s.deletedURL[key] = false
-		s.Unlock()
-		err := file.writeFile(key, userID, v.OriginURL)
-		if err != nil {
+		if r[i].Status == BatchInvalid {
+			continue
+		}
+		rec, err := s.addURL(ctx, v.OriginURL, userID, batchOptions(v))
+		r[i].Status = BatchCreated
+		switch {
+		case errors.Is(err, ErrConflict):
+			r[i].Status = BatchExists
+		case err != nil:
+			// уже добавленные адреса пакета должны попасть в файл вместе с памятью
+			done := s.appendLog(recs)
+			s.Unlock()
+			if werr := waitDone(done); werr != nil {
+				s.dropUnsaved(added)
+				return nil, werr
+			}
 			return nil, err
+		default:
+			added = append(added, rec)
+			recs = append(recs, setRecord(rec))
 		}
-		r[i].CorrID = v.CorrID
-		r[i].ShortURL = string(cfg.BaseURL + "/" + key)
+		r[i].ShortURL = string(cfg.BaseURL + "/" + rec.Key)
+		r[i].ExpiresAt = rec.ExpiresAt
+	}
+	done := s.appendLog(recs)
+	s.Unlock()
+	if err := waitDone(done); err != nil {
+		s.dropUnsaved(added)
+		return nil, err
 	}
 	return r, nil
 }
 
-// CloseDB метод закрывает соединение с хранилищем данных.
-func (s *FileStorage) CloseDB() {
-	log.Info().Msg("file closed")
+// MarkDeleted метод помечает на удаление адреса пользователя и дописывает в файл записи об удалении.
+// Если записи не удалось сохранить, отметки об удалении снимаются, чтобы повторный вызов записал их снова.
+// Отметки снимаются под блокировкой хранилища, как и любое другое изменение.
+func (s *FileStorage) MarkDeleted(ctx context.Context, keys []string, ids []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.Lock()
+	deleted := s.markDeleted(keys, ids)
+	recs := make([]logRecord, 0, len(deleted))
+	for _, rec := range deleted {
+		recs = append(recs, deleteRecord(rec))
+	}
+	done := s.appendLog(recs)
+	s.Unlock()
+	err := waitDone(done)
+	if err != nil {
+		s.Lock()
+		s.unmarkDeleted(deleted)
+		s.Unlock()
+	}
+	return err
 }
 
-// MarkDeleted метод помечает на удаление адреса пользователя в хранилище.
-func (s *FileStorage) MarkDeleted(keys []string, ids []string) {
+// ExpireURLs метод помечает удаленными адреса с истекшим сроком действия и дописывает в файл записи об удалении.
+func (s *FileStorage) ExpireURLs(ctx context.Context, now time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
 	s.Lock()
-	for i, key := range keys {
-		if s.userURL[key] == ids[i] {
-			s.deletedURL[key] = true
-		}
+	expiredURLs := s.expireURLs(now)
+	recs := make([]logRecord, 0, len(expiredURLs))
+	for _, rec := range expiredURLs {
+		recs = append(recs, deleteRecord(rec))
 	}
+	done := s.appendLog(recs)
 	s.Unlock()
+	return len(expiredURLs), waitDone(done)
 }
 
-// ReturnStats метод возвращает статистику по количеству сохраненных сокращенных URL и пользователей.
-func (s *FileStorage) ReturnStats() (*stats, error) {
-	temp := make(map[string]bool)
-	for _, v := range s.userURL {
-		if !temp[v] {
-			temp[v] = true
-		}
+// appendLog метод ставит записи в очередь на запись в файл хранилища и возвращает канал с результатом записи.
+// Вызывается под блокировкой. Во время сжатия записи также сохраняются в буфер,
+// который дописывается в конец нового файла.
+func (s *FileStorage) appendLog(recs []logRecord) chan error {
+	if len(recs) == 0 {
+		return doneCh(nil)
 	}
-	stats := stats{
-		URLs:  len(s.baseURL),
-		Users: len(temp),
+	if s.closed {
+		return doneCh(ErrUnavailable)
 	}
-	return &stats, nil
+	data, err := encodeRecords(s.format, recs)
+	if err != nil {
+		return doneCh(err)
+	}
+	if s.tail != nil {
+		s.tail = append(s.tail, recs...)
+	}
+	s.logRecords += len(recs)
+	s.logSize += int64(len(data))
+	return s.writer.write(data)
 }
 
-type readerFile struct {
-	file    *os.File
-	decoder *json.Decoder
+// waitDone функция ожидает результат записи в файл. Поставленная в очередь запись не отменяется,
+// поэтому результат не зависит от контекста запроса и соответствует сохраненным данным.
+// Контекст проверяется до изменения памяти и постановки записи в очередь.
+func waitDone(done chan error) error {
+	return <-done
 }
 
-func readStorage(cfg *config.Config, fs *FileStorage) {
-	file, err := newReaderFile(cfg)
-	if err != nil {
-		log.Fatal().Err(err).Msg("ReadStorage NewWriterFile err")
-	}
-	defer file.close()
-	file.readFile(fs)
+// doneCh функция возвращает канал с готовым результатом записи.
+func doneCh(err error) chan error {
+	done := make(chan error, 1)
+	done <- err
+	return done
 }
 
-func newReaderFile(cfg *config.Config) (*readerFile, error) {
-	file, err := os.OpenFile(cfg.FileStoragePath, os.O_RDONLY|os.O_CREATE, 0777)
+// writeRecords функция записывает в файл записи журнала и возвращает количество записанных байт.
+func writeRecords(file *os.File, format string, recs []logRecord) (int64, error) {
+	data, err := encodeRecords(format, recs)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	return &readerFile{
-		file:    file,
-		decoder: json.NewDecoder(file),
-	}, nil
+	n, err := file.Write(data)
+	return int64(n), err
 }
 
-func (r *readerFile) readFile(fs *FileStorage) {
-	var fileBZ = make([]byte, 0)
-	_, err := r.file.Read(fileBZ)
+// SaveClicks метод дописывает пакет переходов в файл переходов и сохраняет их в памяти.
+// Переходы по отсутствующим адресам отбрасываются. Отбор выполняется под блокировкой файла переходов,
+// поэтому переход по адресу, удаленному после отбора, убирается из файла при удалении адреса.
+func (s *FileStorage) SaveClicks(ctx context.Context, clicks []Click) error {
+	s.clicksMu.Lock()
+	defer s.clicksMu.Unlock()
+	clicks = s.existingClicks(clicks)
+	if len(clicks) == 0 {
+		return nil
+	}
+	file, err := os.OpenFile(s.clicksPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0777)
 	if err != nil {
-		log.Error().Err(err).Msg("ReadFile reading file err")
-		return
-	}
-	for r.decoder.More() {
-		var t storageStruct
-		err := r.decoder.Decode(&t)
-		if err != nil {
-			log.Error().Err(err).Msg("ReadFile decoder err")
-			return
+		return err
+	}
+	defer file.Close()
+	encoder := json.NewEncoder(file)
+	for i := range clicks {
+		if err = encoder.Encode(&clicks[i]); err != nil {
+			return err
 		}
-		fs.Lock()
-		fs.baseURL[t.Key] = t.Value
-		fs.userURL[t.Key] = t.UserID
-		fs.deletedURL[t.Key] = t.Deleted
-		fs.Unlock()
 	}
+	return s.MemoryStorage.SaveClicks(ctx, clicks)
 }
 
-func (r *readerFile) close() error {
-	return r.file.Close()
-}
-
-type writerFile struct {
-	file    *os.File
-	encoder *json.Encoder
+// CloseDB метод закрывает соединение с хранилищем данных.
+func (s *FileStorage) CloseDB() {
+	s.stopCompaction()
+	s.Lock()
+	s.closed = true
+	s.Unlock()
+	s.writer.close()
+	log.Info().Msg("file closed")
 }
 
-func newWriterFile(cfg *config.Config) (*writerFile, error) {
-	file, err := os.OpenFile(cfg.FileStoragePath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0777)
+// readClicks функция восстанавливает переходы по коротким ссылкам из файла переходов.
+func readClicks(fs *FileStorage) {
+	file, err := os.OpenFile(fs.clicksPath, os.O_RDONLY|os.O_CREATE, 0777)
 	if err != nil {
-		return nil, err
+		log.Fatal().Err(err).Msg("readClicks open file err")
 	}
-	return &writerFile{
-		file:    file,
-		encoder: json.NewEncoder(file),
-	}, nil
-}
-
-func (w *writerFile) writeFile(key, userID, value string) error {
-	t := storageStruct{UserID: userID, Key: key, Value: value, Deleted: false}
-	return w.encoder.Encode(&t)
-}
-
-func (w *writerFile) close() error {
-	return w.file.Close()
+	defer file.Close()
+	decoder := json.NewDecoder(file)
+	clicks := make([]Click, 0)
+	for decoder.More() {
+		var c Click
+		if err = decoder.Decode(&c); err != nil {
+			log.Error().Err(err).Msg("readClicks decoder err")
+			break
+		}
+		clicks = append(clicks, c)
+	}
+	fs.MemoryStorage.SaveClicks(context.Background(), clicks)
 }
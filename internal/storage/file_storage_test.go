@@ -0,0 +1,456 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"shortURL/internal/config"
+)
+
+func TestFileStorageReplay(t *testing.T) {
+	cfg := &config.Config{
+		BaseURL:         "http://127.0.0.1:8080",
+		FileStoragePath: filepath.Join(t.TempDir(), "storage.json"),
+		KeyGenerator:    config.KeyRandom,
+		KeyLength:       8,
+	}
+	strg := NewFileStorager(cfg, NewKeyGenerator(cfg))
+	_, err := strg.SetShortURL(context.Background(), "https://go.dev/", "user1", URLOptions{Alias: "go-dev"}, cfg)
+	require.NoError(t, err)
+	_, err = strg.SetShortURL(context.Background(), "https://pkg.go.dev/", "user1", URLOptions{Alias: "pkg-go-dev"}, cfg)
+	require.NoError(t, err)
+	_, err = strg.SetShortURL(context.Background(), "https://go.dev/blog/", "user2", URLOptions{Alias: "go-blog", ExpiresAt: time.Now().Add(time.Hour)}, cfg)
+	require.NoError(t, err)
+
+	// удаление чужого адреса игнорируется
+	strg.MarkDeleted(context.Background(), []string{"go-dev", "go-blog"}, []string{"user1", "user1"})
+	n, err := strg.ExpireURLs(context.Background(), time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	restored := NewFileStorager(cfg, NewKeyGenerator(cfg))
+	_, err = restored.RetFullURL(context.Background(), "go-dev")
+	assert.ErrorIs(t, err, ErrGone)
+	fURL, err := restored.RetFullURL(context.Background(), "pkg-go-dev")
+	require.NoError(t, err)
+	assert.Equal(t, "https://pkg.go.dev/", fURL)
+	r, ok := restored.record("go-blog")
+	require.True(t, ok)
+	assert.True(t, r.deleted)
+
+	// удаленный адрес не восстанавливается повторным сохранением
+	_, err = restored.SetShortURL(context.Background(), "https://go.dev/", "user1", URLOptions{}, cfg)
+	assert.ErrorIs(t, err, ErrConflict)
+}
+
+func TestFileStorageReplayRecords(t *testing.T) {
+	cfg := &config.Config{FileStoragePath: filepath.Join(t.TempDir(), "storage.json"), KeyLength: 8}
+	// первые строки записаны в прежнем формате файла без типа записи
+	data := `{"ID":"user1","key":"a1","value":"https://go.dev/","deleted":false}
+{"ID":"user1","key":"a2","value":"https://pkg.go.dev/","deleted":true}
+{"op":"set","ID":"user2","key":"a3","value":"https://go.dev/blog/"}
+{"op":"update","key":"a3","value":"https://go.dev/doc/"}
+{"op":"delete","ID":"user1","key":"a1"}
+`
+	require.NoError(t, os.WriteFile(cfg.FileStoragePath, []byte(data), 0600))
+
+	strg := NewFileStorager(cfg, NewKeyGenerator(cfg))
+	_, err := strg.RetFullURL(context.Background(), "a1")
+	assert.ErrorIs(t, err, ErrGone)
+	_, err = strg.RetFullURL(context.Background(), "a2")
+	assert.ErrorIs(t, err, ErrGone)
+	fURL, err := strg.RetFullURL(context.Background(), "a3")
+	require.NoError(t, err)
+	assert.Equal(t, "https://go.dev/doc/", fURL)
+	page, err := strg.ReturnAllURLs(context.Background(), "user2", ListOptions{}, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []urls{{ShortURL: "/a3", OriginalURL: "https://go.dev/doc/"}}, page.URLs)
+}
+
+func TestFileStorageReplayTimestamps(t *testing.T) {
+	cfg := &config.Config{FileStoragePath: filepath.Join(t.TempDir(), "storage.json"), KeyLength: 8}
+	// записи прежних версий без времени изменения
+	data := `{"op":"set","ID":"user1","key":"a1","value":"https://go.dev/","created_at":"2024-01-01T10:00:00Z"}
+{"op":"set","ID":"user1","key":"a2","value":"https://pkg.go.dev/","created_at":"2024-01-01T10:00:00Z"}
+{"op":"delete","ID":"user1","key":"a2","deleted_at":"2024-01-02T10:00:00Z"}
+{"op":"set","ID":"user1","key":"a3","value":"https://go.dev/blog/"}
+{"op":"update","key":"a1","value":"https://go.dev/doc/"}
+`
+	require.NoError(t, os.WriteFile(cfg.FileStoragePath, []byte(data), 0600))
+	created := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	deleted := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	strg := NewFileStorager(cfg, NewKeyGenerator(cfg))
+	recs, err := strg.ExportUserURLs(context.Background(), "user1", "", 10)
+	require.NoError(t, err)
+	require.Len(t, recs, 3)
+	assert.Equal(t, created, *recs[0].UpdatedAt)
+	assert.Equal(t, deleted, *recs[1].UpdatedAt)
+	assert.Equal(t, deleted, *recs[1].DeletedAt)
+	assert.Nil(t, recs[2].CreatedAt)
+	assert.Nil(t, recs[2].UpdatedAt)
+
+	// время изменения новых записей сохраняется в файле и при сжатии
+	_, err = strg.UpdateURL(context.Background(), "a1", "user1", "https://go.dev/play/")
+	require.NoError(t, err)
+	_, err = strg.RestoreURLs(context.Background(), []string{"a2"}, "user1", deleted)
+	require.NoError(t, err)
+	want, err := strg.ExportUserURLs(context.Background(), "user1", "", 10)
+	require.NoError(t, err)
+	assert.True(t, want[0].UpdatedAt.After(created))
+	assert.True(t, want[1].UpdatedAt.After(deleted))
+	strg.CloseDB()
+	for _, compact := range []bool{true, false} {
+		restored := NewFileStorager(cfg, NewKeyGenerator(cfg))
+		got, err := restored.ExportUserURLs(context.Background(), "user1", "", 10)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+		if compact {
+			require.NoError(t, restored.compact())
+		}
+		restored.CloseDB()
+	}
+}
+
+func TestFileStorageCompact(t *testing.T) {
+	cfg := &config.Config{
+		BaseURL:         "http://127.0.0.1:8080",
+		FileStoragePath: filepath.Join(t.TempDir(), "storage.json"),
+		KeyLength:       8,
+	}
+	strg := NewFileStorager(cfg, NewKeyGenerator(cfg))
+	keys := make([]string, 0, 100)
+	ids := make([]string, 0, 100)
+	for i := 0; i < 100; i++ {
+		key := "url-" + strconv.Itoa(i)
+		_, err := strg.SetShortURL(context.Background(), "https://go.dev/"+key, "user1", URLOptions{Alias: key}, cfg)
+		require.NoError(t, err)
+		keys = append(keys, key)
+		ids = append(ids, "user1")
+	}
+	for i := 0; i < 5; i++ {
+		strg.MarkDeleted(context.Background(), keys[:50], ids[:50])
+	}
+	assert.Equal(t, 150, strg.logRecords)
+	strg.compactRatio = 1.2
+	assert.False(t, strg.needCompact())
+	strg.logRecords = minCompactRecords
+
+	// запись во время сжатия попадает в буфер и дописывается в новый файл
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			_, err := strg.SetShortURL(context.Background(), "https://go.dev/tail/"+strconv.Itoa(i), "user2", URLOptions{}, cfg)
+			assert.NoError(t, err)
+		}
+	}()
+	require.True(t, strg.needCompact())
+	require.NoError(t, strg.compact())
+	<-done
+	assert.Nil(t, strg.tail)
+
+	restored := NewFileStorager(cfg, NewKeyGenerator(cfg))
+	assert.Equal(t, 120, restored.count())
+	assert.LessOrEqual(t, restored.logRecords, 140)
+	_, err := restored.RetFullURL(context.Background(), "url-0")
+	assert.ErrorIs(t, err, ErrGone)
+	fURL, err := restored.RetFullURL(context.Background(), "url-99")
+	require.NoError(t, err)
+	assert.Equal(t, "https://go.dev/url-99", fURL)
+	page, err := restored.ReturnAllURLs(context.Background(), "user2", ListOptions{}, cfg)
+	require.NoError(t, err)
+	assert.Len(t, page.URLs, 20)
+}
+
+func TestFileStorageWriter(t *testing.T) {
+	for _, policy := range []string{config.SyncAlways, config.SyncInterval, config.SyncNever} {
+		t.Run(policy, func(t *testing.T) {
+			cfg := &config.Config{
+				BaseURL:          "http://127.0.0.1:8080",
+				FileStoragePath:  filepath.Join(t.TempDir(), "storage.json"),
+				KeyLength:        8,
+				FileSync:         policy,
+				FileSyncInterval: 10 * time.Millisecond,
+			}
+			strg := NewFileStorager(cfg, NewKeyGenerator(cfg))
+			var wg sync.WaitGroup
+			for i := 0; i < 50; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					_, err := strg.SetShortURL(context.Background(), "https://go.dev/"+strconv.Itoa(i), "user1", URLOptions{}, cfg)
+					assert.NoError(t, err)
+				}(i)
+			}
+			wg.Wait()
+			strg.CloseDB()
+
+			// после остановки хранилища запись возвращает ошибку, а не завершает процесс
+			_, err := strg.SetShortURL(context.Background(), "https://go.dev/closed", "user1", URLOptions{}, cfg)
+			assert.ErrorIs(t, err, ErrUnavailable)
+
+			restored := NewFileStorager(cfg, NewKeyGenerator(cfg))
+			assert.Equal(t, 50, restored.logRecords)
+			assert.Equal(t, 50, restored.count())
+		})
+	}
+}
+
+func TestFileStorageCanceledWrite(t *testing.T) {
+	cfg := &config.Config{
+		BaseURL:         "http://127.0.0.1:8080",
+		FileStoragePath: filepath.Join(t.TempDir(), "storage.json"),
+		KeyLength:       8,
+	}
+	strg := NewFileStorager(cfg, NewKeyGenerator(cfg))
+	_, err := strg.SetShortURL(context.Background(), "https://go.dev/", "user1", URLOptions{Alias: "go-dev"}, cfg)
+	require.NoError(t, err)
+
+	// отмененный запрос не меняет ни память, ни файл
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = strg.SetShortURL(ctx, "https://go.dev/doc/", "user1", URLOptions{Alias: "go-doc"}, cfg)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = strg.WriteMultiURL(ctx, []MultiURL{{CorrID: "1", OriginURL: "https://go.dev/blog/"}}, "user1", cfg)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, strg.MarkDeleted(ctx, []string{"go-dev"}, []string{"user1"}), context.Canceled)
+	assert.Equal(t, 1, strg.count())
+	_, err = strg.RetFullURL(context.Background(), "go-doc")
+	assert.ErrorIs(t, err, ErrNoContent)
+	_, err = strg.RetFullURL(context.Background(), "go-dev")
+	assert.NoError(t, err)
+	strg.CloseDB()
+
+	restored := NewFileStorager(cfg, NewKeyGenerator(cfg))
+	assert.Equal(t, 1, restored.logRecords)
+	_, err = restored.RetFullURL(context.Background(), "go-dev")
+	assert.NoError(t, err)
+}
+
+func TestFileStorageMarkDeletedError(t *testing.T) {
+	cfg := &config.Config{
+		BaseURL:         "http://127.0.0.1:8080",
+		FileStoragePath: filepath.Join(t.TempDir(), "storage.json"),
+		KeyLength:       8,
+	}
+	strg := NewFileStorager(cfg, NewKeyGenerator(cfg))
+	_, err := strg.SetShortURL(context.Background(), "https://go.dev/", "user1", URLOptions{Alias: "go-dev"}, cfg)
+	require.NoError(t, err)
+	strg.CloseDB()
+
+	// несохраненное удаление не остается в памяти, чтобы повторный вызов записал его в файл
+	err = strg.MarkDeleted(context.Background(), []string{"go-dev"}, []string{"user1"})
+	assert.ErrorIs(t, err, ErrUnavailable)
+	fURL, err := strg.RetFullURL(context.Background(), "go-dev")
+	require.NoError(t, err)
+	assert.Equal(t, "https://go.dev/", fURL)
+
+	// отметка снимается только с тех удалений, которые не изменились после записи
+	deleted := strg.markDeleted([]string{"go-dev"}, []string{"user1"})
+	require.Len(t, deleted, 1)
+	r, ok := strg.record("go-dev")
+	require.True(t, ok)
+	r.deletedAt++
+	strg.urlShard("go-dev").urls["go-dev"] = r
+	strg.unmarkDeleted(deleted)
+	_, err = strg.RetFullURL(context.Background(), "go-dev")
+	assert.ErrorIs(t, err, ErrGone)
+}
+
+func TestFileStorageSetError(t *testing.T) {
+	cfg := &config.Config{
+		BaseURL:         "http://127.0.0.1:8080",
+		FileStoragePath: filepath.Join(t.TempDir(), "storage.json"),
+		KeyLength:       8,
+	}
+	strg := NewFileStorager(cfg, NewKeyGenerator(cfg))
+	_, err := strg.SetShortURL(context.Background(), "https://go.dev/", "user1", URLOptions{Alias: "go-dev"}, cfg)
+	require.NoError(t, err)
+	strg.CloseDB()
+
+	// несохраненные адреса не остаются в памяти, повторный запрос не получает конфликта
+	_, err = strg.SetShortURL(context.Background(), "https://go.dev/blog/", "user1", URLOptions{Alias: "go-blog"}, cfg)
+	assert.ErrorIs(t, err, ErrUnavailable)
+	_, err = strg.RetFullURL(context.Background(), "go-blog")
+	assert.ErrorIs(t, err, ErrNoContent)
+	_, err = strg.WriteMultiURL(context.Background(), []MultiURL{
+		{CorrID: "1", OriginURL: "https://go.dev/"},
+		{CorrID: "2", OriginURL: "https://go.dev/doc/"},
+	}, "user2", cfg)
+	assert.ErrorIs(t, err, ErrUnavailable)
+	_, err = strg.SetShortURL(context.Background(), "https://go.dev/blog/", "user1", URLOptions{Alias: "go-blog"}, cfg)
+	assert.ErrorIs(t, err, ErrUnavailable)
+	keys, err := strg.ReturnUserKeys(context.Background(), "user2", []string{"https://go.dev/", "https://go.dev/doc/"})
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+	fURL, err := strg.RetFullURL(context.Background(), "go-dev")
+	require.NoError(t, err)
+	assert.Equal(t, "https://go.dev/", fURL)
+
+	// удаляются только адреса, которые не изменились после добавления
+	rec, err := strg.addURL(context.Background(), "https://go.dev/edited", "user1", URLOptions{Alias: "edited"})
+	require.NoError(t, err)
+	r, ok := strg.record("edited")
+	require.True(t, ok)
+	r.updated++
+	strg.urlShard("edited").urls["edited"] = r
+	strg.dropAdded([]storageStruct{rec})
+	_, ok = strg.record("edited")
+	assert.True(t, ok)
+}
+
+func TestFileStorageCounterSeed(t *testing.T) {
+	cfg := &config.Config{
+		BaseURL:         "http://127.0.0.1:8080",
+		FileStoragePath: filepath.Join(t.TempDir(), "storage.json"),
+		KeyGenerator:    config.KeyCounter,
+	}
+	ctx := context.Background()
+	strg := NewFileStorager(cfg, NewKeyGenerator(cfg))
+	keys := make([]string, 0, 20)
+	for i := 0; i < 20; i++ {
+		shortURL, err := strg.SetShortURL(ctx, "https://go.dev/"+strconv.Itoa(i), "user1", URLOptions{}, cfg)
+		require.NoError(t, err)
+		keys = append(keys, shortURL[len(cfg.BaseURL)+1:])
+	}
+	_, err := strg.SetShortURL(ctx, "https://go.dev/alias", "user1", URLOptions{Alias: "go-dev"}, cfg)
+	require.NoError(t, err)
+	ids := make([]string, 15)
+	for i := range ids {
+		ids[i] = "user1"
+	}
+	require.NoError(t, strg.MarkDeleted(ctx, keys[:15], ids))
+	_, err = strg.PurgeDeleted(ctx, time.Now())
+	require.NoError(t, err)
+	strg.CloseDB()
+
+	// после окончательного удаления части адресов счетчик продолжается с наибольшего выданного ключа
+	restored := NewFileStorager(cfg, NewKeyGenerator(cfg))
+	shortURL, err := restored.SetShortURL(ctx, "https://go.dev/new", "user1", URLOptions{}, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, cfg.BaseURL+"/"+base62(21), shortURL)
+}
+
+func TestFileStorageRecovery(t *testing.T) {
+	for _, format := range []string{config.FormatJSON, config.FormatBinary} {
+		t.Run(format, func(t *testing.T) {
+			cfg := &config.Config{
+				BaseURL:         "http://127.0.0.1:8080",
+				FileStoragePath: filepath.Join(t.TempDir(), "storage.json"),
+				KeyLength:       8,
+				FileFormat:      format,
+			}
+			strg := NewFileStorager(cfg, NewKeyGenerator(cfg))
+			for i := 0; i < 10; i++ {
+				_, err := strg.SetShortURL(context.Background(), "https://go.dev/"+strconv.Itoa(i), "user1", URLOptions{Alias: "url-" + strconv.Itoa(i)}, cfg)
+				require.NoError(t, err)
+			}
+			strg.CloseDB()
+			data, err := os.ReadFile(cfg.FileStoragePath)
+			require.NoError(t, err)
+			assert.Equal(t, format == config.FormatBinary, bytes.HasPrefix(data, []byte(binaryMagic)))
+
+			// недописанная последняя запись отбрасывается, остальные восстанавливаются
+			require.NoError(t, os.WriteFile(cfg.FileStoragePath, data[:len(data)-5], 0600))
+			restored := NewFileStorager(cfg, NewKeyGenerator(cfg))
+			assert.Equal(t, 9, restored.count())
+			assert.Equal(t, 1, restored.lostRecords)
+			_, err = restored.SetShortURL(context.Background(), "https://go.dev/after", "user1", URLOptions{Alias: "after"}, cfg)
+			require.NoError(t, err)
+			restored.CloseDB()
+			restored = NewFileStorager(cfg, NewKeyGenerator(cfg))
+			assert.Equal(t, 10, restored.count())
+			assert.Zero(t, restored.lostRecords)
+			_, ok := restored.record("after")
+			assert.True(t, ok)
+			restored.CloseDB()
+
+			// поврежденная запись в середине пропускается, следующие за ней записи восстанавливаются
+			data, err = os.ReadFile(cfg.FileStoragePath)
+			require.NoError(t, err)
+			pos := bytes.Index(data, []byte("url-5"))
+			require.Positive(t, pos)
+			data[pos] = '#'
+			if format == config.FormatJSON {
+				data[pos-1] = '\x00'
+			}
+			require.NoError(t, os.WriteFile(cfg.FileStoragePath, data, 0600))
+			restored = NewFileStorager(cfg, NewKeyGenerator(cfg))
+			assert.Equal(t, 9, restored.count())
+			assert.Equal(t, 1, restored.lostRecords)
+			_, ok = restored.record("url-5")
+			assert.False(t, ok)
+			_, ok = restored.record("after")
+			assert.True(t, ok)
+			stat, err := os.Stat(cfg.FileStoragePath)
+			require.NoError(t, err)
+			assert.Equal(t, restored.logSize, stat.Size())
+			_, err = restored.SetShortURL(context.Background(), "https://go.dev/last", "user1", URLOptions{Alias: "last"}, cfg)
+			require.NoError(t, err)
+			restored.CloseDB()
+			restored = NewFileStorager(cfg, NewKeyGenerator(cfg))
+			assert.Equal(t, 10, restored.count())
+			restored.CloseDB()
+
+			// поврежденный конец без целых записей отрезается
+			data, err = os.ReadFile(cfg.FileStoragePath)
+			require.NoError(t, err)
+			pos = bytes.Index(data, []byte(`"last"`)) + 1
+			require.Positive(t, pos)
+			data[pos] = '#'
+			if format == config.FormatJSON {
+				data[pos-1] = '\x00'
+			}
+			size := len(data)
+			data = append(data, []byte("garbage\n")...)
+			require.NoError(t, os.WriteFile(cfg.FileStoragePath, data, 0600))
+			restored = NewFileStorager(cfg, NewKeyGenerator(cfg))
+			assert.Equal(t, 9, restored.count())
+			// пропущенная ранее запись остается в файле до сжатия и учитывается снова
+			assert.Equal(t, 3, restored.lostRecords)
+			stat, err = os.Stat(cfg.FileStoragePath)
+			require.NoError(t, err)
+			assert.Equal(t, restored.logSize, stat.Size())
+			assert.Less(t, stat.Size(), int64(size))
+			_, ok = restored.record("last")
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestFileStorageConvert(t *testing.T) {
+	cfg := &config.Config{FileStoragePath: filepath.Join(t.TempDir(), "storage.json"), KeyLength: 8}
+	data := `{"ID":"user1","key":"a1","value":"https://go.dev/","deleted":false}
+{"ID":"user1","key":"a2","value":"https://pkg.go.dev/","deleted":false}
+{"op":"delete","ID":"user1","key":"a1"}
+`
+	require.NoError(t, os.WriteFile(cfg.FileStoragePath, []byte(data), 0600))
+
+	cfg.FileFormat = config.FormatBinary
+	strg := NewFileStorager(cfg, NewKeyGenerator(cfg))
+	strg.CloseDB()
+	converted, err := os.ReadFile(cfg.FileStoragePath)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(converted, []byte(binaryMagic)))
+	backup, err := os.ReadFile(cfg.FileStoragePath + ".bak")
+	require.NoError(t, err)
+	assert.Equal(t, data, string(backup))
+
+	strg = NewFileStorager(cfg, NewKeyGenerator(cfg))
+	assert.Equal(t, 2, strg.logRecords)
+	_, err = strg.RetFullURL(context.Background(), "a1")
+	assert.ErrorIs(t, err, ErrGone)
+	fURL, err := strg.RetFullURL(context.Background(), "a2")
+	require.NoError(t, err)
+	assert.Equal(t, "https://pkg.go.dev/", fURL)
+}
@@ -0,0 +1,207 @@
+package storage
+
+import (
+	"bufio"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"shortURL/internal/config"
+)
+
+// maxWriteBatch - максимальное количество запросов, записываемых в файл за один сброс буфера.
+const maxWriteBatch = 256
+
+// writeRequest - запрос к обработчику записи в файл хранилища.
+// reopen означает, что файл был заменен и его нужно открыть заново.
+type writeRequest struct {
+	data   []byte
+	reopen bool
+	done   chan error
+}
+
+// fileWriter - обработчик, последовательно дописывающий записи в файл хранилища.
+// Запросы, поступившие одновременно, записываются одним сбросом буфера и, при необходимости, одним fsync.
+type fileWriter struct {
+	path         string
+	syncPolicy   string
+	syncInterval time.Duration
+	requests     chan writeRequest
+	finished     chan struct{}
+	file         *os.File
+	buf          *bufio.Writer
+	dirty        bool // в файле есть записи, не сохраненные на диск
+}
+
+// newFileWriter функция создает и запускает обработчик записи в файл хранилища.
+func newFileWriter(cfg *config.Config) *fileWriter {
+	w := fileWriter{
+		path:         cfg.FileStoragePath,
+		syncPolicy:   cfg.FileSync,
+		syncInterval: cfg.FileSyncInterval,
+		requests:     make(chan writeRequest, maxWriteBatch),
+		finished:     make(chan struct{}),
+	}
+	if err := w.open(); err != nil {
+		log.Error().Err(err).Msg("fileWriter open err")
+	}
+	go w.run()
+	return &w
+}
+
+// write метод ставит данные в очередь на запись и возвращает канал с результатом.
+// Порядок записей в файле совпадает с порядком вызовов.
+func (w *fileWriter) write(data []byte) chan error {
+	done := make(chan error, 1)
+	w.requests <- writeRequest{data: data, done: done}
+	return done
+}
+
+// reopen метод ставит в очередь повторное открытие файла после его замены.
+func (w *fileWriter) reopen() chan error {
+	done := make(chan error, 1)
+	w.requests <- writeRequest{reopen: true, done: done}
+	return done
+}
+
+// close метод дописывает оставшиеся записи, сохраняет их на диск и закрывает файл.
+func (w *fileWriter) close() {
+	close(w.requests)
+	<-w.finished
+}
+
+func (w *fileWriter) run() {
+	var tick <-chan time.Time
+	if w.syncPolicy == config.SyncInterval {
+		ticker := time.NewTicker(w.syncInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	batch := make([]writeRequest, 0, maxWriteBatch)
+	for {
+		select {
+		case req, ok := <-w.requests:
+			if !ok {
+				w.shutdown()
+				return
+			}
+			batch = append(batch[:0], req)
+		collect:
+			for len(batch) < maxWriteBatch {
+				select {
+				case req, ok := <-w.requests:
+					if !ok {
+						break collect
+					}
+					batch = append(batch, req)
+				default:
+					break collect
+				}
+			}
+			w.commit(batch)
+		case <-tick:
+			if err := w.sync(); err != nil {
+				log.Error().Err(err).Msg("fileWriter sync err")
+			}
+		}
+	}
+}
+
+// commit метод записывает пакет запросов и сообщает результат каждому из них.
+func (w *fileWriter) commit(batch []writeRequest) {
+	start := 0
+	for i, req := range batch {
+		if !req.reopen {
+			continue
+		}
+		w.flush(batch[start:i])
+		err := w.closeFile()
+		if err == nil {
+			err = w.open()
+		}
+		req.done <- err
+		start = i + 1
+	}
+	w.flush(batch[start:])
+}
+
+// flush метод записывает данные запросов одним сбросом буфера.
+func (w *fileWriter) flush(batch []writeRequest) {
+	if len(batch) == 0 {
+		return
+	}
+	err := w.open()
+	for _, req := range batch {
+		if err != nil {
+			break
+		}
+		_, err = w.buf.Write(req.data)
+	}
+	if err == nil {
+		err = w.buf.Flush()
+	}
+	if err == nil {
+		w.dirty = true
+		if w.syncPolicy == config.SyncAlways {
+			err = w.sync()
+		}
+	}
+	if err != nil {
+		// после ошибки файл открывается заново, чтобы не дописывать данные после частично записанного буфера
+		log.Error().Err(err).Msg("fileWriter write err")
+		w.closeFile()
+	}
+	for _, req := range batch {
+		req.done <- err
+	}
+}
+
+// open метод открывает файл, если он еще не открыт.
+func (w *fileWriter) open() error {
+	if w.file != nil {
+		return nil
+	}
+	file, err := os.OpenFile(w.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0777)
+	if err != nil {
+		return err
+	}
+	w.file = file
+	w.buf = bufio.NewWriterSize(file, 64<<10)
+	return nil
+}
+
+// sync метод сохраняет на диск записанные данные.
+func (w *fileWriter) sync() error {
+	if !w.dirty || w.file == nil || w.syncPolicy == config.SyncNever {
+		return nil
+	}
+	if err := w.file.Sync(); err != nil {
+		return err
+	}
+	w.dirty = false
+	return nil
+}
+
+// closeFile метод сохраняет на диск записанные данные и закрывает файл.
+func (w *fileWriter) closeFile() error {
+	if w.file == nil {
+		return nil
+	}
+	err := w.sync()
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	w.file = nil
+	w.buf = nil
+	w.dirty = false
+	return err
+}
+
+func (w *fileWriter) shutdown() {
+	if err := w.closeFile(); err != nil {
+		log.Error().Err(err).Msg("fileWriter close err")
+	}
+	close(w.finished)
+	log.Debug().Msg("fileWriter finished")
+}
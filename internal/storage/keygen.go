@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"shortURL/internal/config"
+)
+
+// maxKeyAttempts - количество попыток подобрать свободный ключ при коллизиях.
+const maxKeyAttempts = 10
+
+const base62Alphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// KeyGenerator - интерфейс генератора ключей коротких ссылок.
+// Хранилище проверяет полученный ключ на занятость и при коллизии запрашивает новый с увеличенным номером попытки.
+// Контекст запроса ограничивает время получения ключа, например выделения блока счетчика в базе данных.
+type KeyGenerator interface {
+	NewKey(ctx context.Context, fURL string, attempt int) (string, error)
+}
+
+// NewKeyGenerator функция создает генератор ключей в соответствии с конфигурацией сервиса.
+func NewKeyGenerator(cfg *config.Config) KeyGenerator {
+	switch cfg.KeyGenerator {
+	case config.KeyCounter:
+		return &CounterGenerator{}
+	case config.KeyHash:
+		return &HashGenerator{Length: cfg.KeyLength}
+	default:
+		return &RandomGenerator{Length: cfg.KeyLength}
+	}
+}
+
+// generateKey функция запрашивает у генератора ключ, пропуская зарезервированные слова:
+// короткая ссылка с таким ключом была бы недоступна, т.к. ее путь занят маршрутом сервиса.
+func generateKey(ctx context.Context, gen KeyGenerator, fURL string, attempt int) (string, error) {
+	for {
+		key, err := gen.NewKey(ctx, fURL, attempt)
+		if err != nil || !isReserved(key) {
+			return key, err
+		}
+		attempt++
+	}
+}
+
+// CounterGenerator выдает ключи по возрастающему счетчику в кодировке base62.
+// Подходит только для хранилищ, работающих в одном экземпляре сервиса.
+type CounterGenerator struct {
+	counter uint64
+}
+
+// NewKey метод возвращает следующее значение счетчика.
+func (g *CounterGenerator) NewKey(ctx context.Context, fURL string, attempt int) (string, error) {
+	return base62(atomic.AddUint64(&g.counter, 1)), nil
+}
+
+// Seed метод задает начальное значение счетчика, например после восстановления хранилища из файла.
+func (g *CounterGenerator) Seed(n uint64) {
+	atomic.StoreUint64(&g.counter, n)
+}
+
+// maxCounterKeyLen - наибольшая длина ключа, который учитывается при восстановлении счетчика.
+// Более длинные ключи счетчик не выдает за обозримое время, такие ключи - псевдонимы.
+const maxCounterKeyLen = 10
+
+// counterValue функция возвращает значение счетчика, которому соответствует ключ.
+// Для ключей не в кодировке base62 и слишком длинных ключей возвращается false.
+func counterValue(key string) (uint64, bool) {
+	if key == "" || len(key) > maxCounterKeyLen {
+		return 0, false
+	}
+	var n uint64
+	for i := 0; i < len(key); i++ {
+		d := strings.IndexByte(base62Alphabet, key[i])
+		if d < 0 {
+			return 0, false
+		}
+		n = n*62 + uint64(d)
+	}
+	return n, true
+}
+
+// RandomGenerator выдает случайные ключи заданной длины.
+type RandomGenerator struct {
+	Length int
+}
+
+// NewKey метод возвращает случайный ключ из символов base62.
+func (g *RandomGenerator) NewKey(ctx context.Context, fURL string, attempt int) (string, error) {
+	key := make([]byte, 0, g.Length)
+	buf := make([]byte, g.Length)
+	for len(key) < g.Length {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			// отбрасываем значения, которые дали бы неравномерное распределение символов
+			if b >= 248 || len(key) == g.Length {
+				continue
+			}
+			key = append(key, base62Alphabet[b%62])
+		}
+	}
+	return string(key), nil
+}
+
+// HashGenerator выдает ключ, полученный усечением хэша исходного адреса.
+// При коллизии к адресу добавляется номер попытки.
+type HashGenerator struct {
+	Length int
+}
+
+// NewKey метод возвращает усеченный хэш адреса в символах base62.
+func (g *HashGenerator) NewKey(ctx context.Context, fURL string, attempt int) (string, error) {
+	if attempt > 0 {
+		fURL += "\x00" + strconv.Itoa(attempt)
+	}
+	sum := sha256.Sum256([]byte(fURL))
+	key := make([]byte, g.Length)
+	for i := range key {
+		key[i] = base62Alphabet[sum[i%len(sum)]%62]
+	}
+	return string(key), nil
+}
+
+// base62 функция кодирует число в строку символов base62.
+func base62(n uint64) string {
+	if n == 0 {
+		return string(base62Alphabet[0])
+	}
+	var buf [11]byte
+	i := len(buf)
+	for n > 0 {
+		i--
+		buf[i] = base62Alphabet[n%62]
+		n /= 62
+	}
+	return string(buf[i:])
+}
@@ -0,0 +1,233 @@
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"shortURL/internal/config"
+)
+
+// Порядок адресов в списке пользователя по времени создания.
+const (
+	OrderNewest = "desc"
+	OrderOldest = "asc"
+)
+
+// MaxListLimit - наибольший размер страницы списка адресов пользователя.
+const MaxListLimit = 1000
+
+// ListOptions структура с параметрами списка адресов пользователя. Адреса упорядочены по времени создания,
+// при равном времени - по ключу. Адреса с неизвестным временем создания считаются созданными в начале эпохи Unix.
+// Нулевой Limit означает список без разбиения на страницы, Contains отбирает адреса, исходный адрес которых
+// содержит подстроку с учетом регистра. Удаленные адреса попадают в список только при IncludeDeleted.
+type ListOptions struct {
+	Cursor         string
+	Limit          int
+	Order          string
+	Contains       string
+	IncludeDeleted bool
+}
+
+// URLPage структура со страницей адресов пользователя. NextCursor пуст на последней странице.
+type URLPage struct {
+	URLs       []urls
+	NextCursor string
+}
+
+// CheckListOptions функция проверяет параметры списка адресов, пустой порядок означает сначала новые адреса.
+func CheckListOptions(opts ListOptions) (ListOptions, error) {
+	switch opts.Order {
+	case "":
+		opts.Order = OrderNewest
+	case OrderNewest, OrderOldest:
+	default:
+		return opts, fmt.Errorf("%w: order must be %q or %q", ErrBadRequest, OrderNewest, OrderOldest)
+	}
+	if opts.Limit < 0 || opts.Limit > MaxListLimit {
+		return opts, fmt.Errorf("%w: limit must be from 1 to %d", ErrBadRequest, MaxListLimit)
+	}
+	if _, err := decodeListCursor(opts.Cursor); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+// listCursor структура с позицией последнего адреса страницы: временем создания в наносекундах и ключом.
+type listCursor struct {
+	created int64
+	key     string
+}
+
+// encode метод возвращает курсор страницы, следующей за позицией.
+func (c listCursor) encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(c.created, 10) + ":" + c.key))
+}
+
+// decodeListCursor функция разбирает курсор страницы, пустой курсор означает первую страницу.
+func decodeListCursor(cursor string) (*listCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	bz, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: wrong cursor", ErrBadRequest)
+	}
+	created, key, ok := strings.Cut(string(bz), ":")
+	n, err := strconv.ParseInt(created, 10, 64)
+	if !ok || err != nil || key == "" {
+		return nil, fmt.Errorf("%w: wrong cursor", ErrBadRequest)
+	}
+	return &listCursor{created: n, key: key}, nil
+}
+
+// listItem структура с адресом списка пользователя и временем его создания для упорядочивания,
+// нулевым для адресов с неизвестным временем создания.
+type listItem struct {
+	storageStruct
+	created int64
+}
+
+// compare метод сравнивает адрес с позицией в порядке возрастания времени создания и ключа.
+func (i listItem) compare(c listCursor) int {
+	switch {
+	case i.created < c.created:
+		return -1
+	case i.created > c.created:
+		return 1
+	}
+	return strings.Compare(i.Key, c.key)
+}
+
+// cursor метод возвращает позицию адреса в списке.
+func (i listItem) cursor() listCursor {
+	return listCursor{created: i.created, key: i.Key}
+}
+
+// listPage функция формирует страницу из адресов, следующих за курсором. Для адресов хранилища в памяти
+// items содержит все подходящие адреса пользователя, для базы данных - уже упорядоченную выборку на адрес больше страницы.
+func listPage(items []listItem, opts ListOptions, cfg *config.Config) (*URLPage, error) {
+	cursor, err := decodeListCursor(opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	// для порядка по убыванию знак сравнения меняется
+	sign := 1
+	if opts.Order != OrderOldest {
+		sign = -1
+	}
+	sort.Slice(items, func(i, j int) bool { return sign*items[i].compare(items[j].cursor()) < 0 })
+	if cursor != nil {
+		start := sort.Search(len(items), func(i int) bool { return sign*items[i].compare(*cursor) > 0 })
+		items = items[start:]
+	}
+	var page URLPage
+	if opts.Limit > 0 && len(items) > opts.Limit {
+		items = items[:opts.Limit]
+		page.NextCursor = items[len(items)-1].cursor().encode()
+	}
+	if len(items) == 0 {
+		return nil, ErrNoContent
+	}
+	page.URLs = make([]urls, 0, len(items))
+	for _, i := range items {
+		page.URLs = append(page.URLs, urls{
+			ShortURL:    cfg.BaseURL + "/" + i.Key,
+			OriginalURL: i.Value,
+			Deleted:     i.Deleted,
+			CreatedAt:   i.CreatedAt,
+			UpdatedAt:   i.UpdatedAt,
+			DeletedAt:   i.DeletedAt,
+		})
+	}
+	return &page, nil
+}
+
+// match метод сообщает, попадает ли адрес в список с заданными параметрами.
+func (opts ListOptions) match(value string, deleted bool) bool {
+	return (opts.IncludeDeleted || !deleted) && strings.Contains(value, opts.Contains)
+}
+
+// ReturnAllURLs метод возвращает страницу адресов пользователя.
+// Адреса берутся из индекса пользователя, поэтому время не зависит от общего количества адресов.
+func (s *MemoryStorage) ReturnAllURLs(ctx context.Context, userID string, opts ListOptions, cfg *config.Config) (*URLPage, error) {
+	us := s.userShard(userID)
+	us.RLock()
+	owner, ok := us.users[userID]
+	if !ok {
+		us.RUnlock()
+		return nil, ErrNoContent
+	}
+	items := make([]listItem, 0, len(owner.keys))
+	for _, key := range owner.keys {
+		sh := s.urlShard(key)
+		sh.RLock()
+		r, ok := sh.urls[key]
+		sh.RUnlock()
+		if ok && opts.match(r.value, r.deleted) {
+			items = append(items, listItem{storageStruct: r.toStorageStruct(key), created: r.created})
+		}
+	}
+	us.RUnlock()
+	return listPage(items, opts, cfg)
+}
+
+// listOrder - сравнение с курсором и направление сортировки запроса списка адресов для каждого порядка.
+var listOrder = map[string][2]string{
+	OrderOldest: {">", "ASC"},
+	OrderNewest: {"<", "DESC"},
+}
+
+// ReturnAllURLs метод возвращает страницу адресов пользователя. Страница выбирается по индексу
+// пользователя и времени создания с позиции курсора, ключи сравниваются побайтно, как и в остальных хранилищах.
+func (s *SQLStorage) ReturnAllURLs(ctx context.Context, userID string, opts ListOptions, cfg *config.Config) (*URLPage, error) {
+	cursor, err := decodeListCursor(opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	order, ok := listOrder[opts.Order]
+	if !ok {
+		order = listOrder[OrderNewest]
+	}
+	var after *time.Time
+	var afterKey string
+	if cursor != nil {
+		t := time.Unix(0, cursor.created).UTC()
+		after, afterKey = &t, cursor.key
+	}
+	// на адрес больше страницы, чтобы узнать, есть ли следующая
+	var limit *int
+	if opts.Limit > 0 {
+		n := opts.Limit + 1
+		limit = &n
+	}
+	rows, err := s.Pool.Query(ctx, `SELECT key, value, deleted, coalesce(created_at, 'epoch'::timestamptz) AS created,
+			created_at, updated_at, deleted_at FROM Short_URLs WHERE user_id = $1 AND ($2 OR NOT deleted) AND strpos(value, $3) > 0
+		AND ($4::timestamptz IS NULL OR (coalesce(created_at, 'epoch'::timestamptz), key COLLATE "C") `+order[0]+` ($4, $5))
+		ORDER BY created `+order[1]+`, key COLLATE "C" `+order[1]+` LIMIT $6`,
+		userID, opts.IncludeDeleted, opts.Contains, after, afterKey, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]listItem, 0)
+	for rows.Next() {
+		var i listItem
+		var created time.Time
+		if err = rows.Scan(&i.Key, &i.Value, &i.Deleted, &created, &i.CreatedAt, &i.UpdatedAt, &i.DeletedAt); err != nil {
+			return nil, err
+		}
+		i.created = created.UnixNano()
+		items = append(items, i)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	// выборка уже начинается после курсора
+	opts.Cursor = ""
+	return listPage(items, opts, cfg)
+}
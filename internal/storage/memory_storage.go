@@ -1,103 +1,364 @@
 package storage
 
 import (
+	"context"
 	"errors"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/rs/zerolog/log"
 
 	"shortURL/internal/config"
 )
 
+// memoryShards - количество независимо блокируемых частей хранилища в памяти.
+const memoryShards = 64
+
 // MemoryStorage структура для хранения данных в оперативной памяти.
+// Адреса распределены по частям по ключу короткой ссылки, индексы пользователей - по идентификатору пользователя,
+// каждая часть защищена своей блокировкой. При изменении сначала блокируется индекс пользователя, затем часть с адресом.
 type MemoryStorage struct {
-	baseURL    map[string]string
-	userURL    map[string]string
-	deletedURL map[string]bool
+	urls   [memoryShards]urlShard
+	users  [memoryShards]userShard
+	keyGen KeyGenerator
+}
+
+// urlShard - часть адресов хранилища с переходами по ним и историей изменения адресов.
+type urlShard struct {
+	urls     map[string]urlRecord
+	clicks   map[string][]Click
+	versions map[string][]URLVersion
 	sync.RWMutex
 }
 
+// userShard - часть индексов адресов пользователей.
+type userShard struct {
+	users map[string]*userIndex
+	sync.RWMutex
+}
+
+// urlRecord - состояние короткой ссылки. Срок действия, время удаления, создания и последнего изменения хранятся
+// в наносекундах Unix, нулевой срок действия означает бессрочную ссылку, нулевое время создания - ссылку,
+// созданную до его появления. Изменением считаются создание, замена адреса, удаление и восстановление ссылки.
+type urlRecord struct {
+	value     string
+	owner     *userIndex
+	expires   int64
+	deleted   bool
+	deletedAt int64
+	created   int64
+	updated   int64
+}
+
+// userIndex - индекс адресов пользователя. Идентификатор пользователя хранится в одном экземпляре,
+// записи адресов ссылаются на индекс владельца.
+type userIndex struct {
+	id   string
+	keys map[string]string // ключ короткой ссылки по исходному адресу
+}
+
 // NewMemoryStorager метод генерирует хранилище данных.
-func NewMemoryStorager() *MemoryStorage {
-	return &MemoryStorage{
-		baseURL:    make(map[string]string),
-		userURL:    make(map[string]string),
-		deletedURL: make(map[string]bool),
+func NewMemoryStorager(keyGen KeyGenerator) *MemoryStorage {
+	s := MemoryStorage{keyGen: keyGen}
+	for i := range s.urls {
+		s.urls[i].urls = make(map[string]urlRecord)
+		s.urls[i].clicks = make(map[string][]Click)
+		s.urls[i].versions = make(map[string][]URLVersion)
+		s.users[i].users = make(map[string]*userIndex)
 	}
+	return &s
+}
+
+// shardOf функция возвращает номер части хранилища для строки по хэшу FNV-1a.
+func shardOf(s string) uint32 {
+	h := uint32(2166136261)
+	for i := 0; i < len(s); i++ {
+		h ^= uint32(s[i])
+		h *= 16777619
+	}
+	return h % memoryShards
+}
+
+func (s *MemoryStorage) urlShard(key string) *urlShard {
+	return &s.urls[shardOf(key)]
+}
+
+func (s *MemoryStorage) userShard(userID string) *userShard {
+	return &s.users[shardOf(userID)]
+}
+
+// toStorageStruct метод возвращает запись в общем для хранилищ виде.
+func (r urlRecord) toStorageStruct(key string) storageStruct {
+	rec := storageStruct{UserID: r.owner.id, Key: key, Value: r.value, Deleted: r.deleted}
+	if r.expires != 0 {
+		expiresAt := time.Unix(0, r.expires).UTC()
+		rec.ExpiresAt = &expiresAt
+	}
+	if r.deleted {
+		deletedAt := time.Unix(0, r.deletedAt).UTC()
+		rec.DeletedAt = &deletedAt
+	}
+	if r.created != 0 {
+		createdAt := time.Unix(0, r.created).UTC()
+		rec.CreatedAt = &createdAt
+	}
+	if r.updated != 0 {
+		updatedAt := time.Unix(0, r.updated).UTC()
+		rec.UpdatedAt = &updatedAt
+	}
+	return rec
+}
+
+// unixNano функция переводит срок действия или время создания ссылки в наносекунды Unix, отсутствующее время - в 0.
+func unixNano(t *time.Time) int64 {
+	if t == nil || t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// newURLRecord функция формирует состояние короткой ссылки из записи.
+func newURLRecord(rec storageStruct, owner *userIndex) urlRecord {
+	r := urlRecord{value: rec.Value, owner: owner, expires: unixNano(rec.ExpiresAt), deleted: rec.Deleted, created: unixNano(rec.CreatedAt)}
+	var deletedAt *time.Time
+	if rec.Deleted {
+		deletedAt = deletedTime(rec.DeletedAt)
+		r.deletedAt = deletedAt.UnixNano()
+	}
+	r.updated = unixNano(updatedTime(rec.UpdatedAt, rec.CreatedAt, deletedAt))
+	return r
 }
 
 // SetShortURL метод генерирует ключ для короткой ссылки, проверяет его наличие и сохраняет данные.
 // Данные передаются и возвращаются текстом в теле запроса.
-func (s *MemoryStorage) SetShortURL(fURL string, userID string, cfg *config.Config) (string, error) {
-	key := hashStr(fURL)
-	s.RLock()
-	id := s.userURL[key]
-	s.RUnlock()
-	if id == userID {
-		return cfg.BaseURL + "/" + key, ErrConflict
-	}
-	s.Lock()
-	s.baseURL[key] = fURL
-	s.userURL[key] = userID
-	s.deletedURL[key] = false
-	s.Unlock()
-	return cfg.BaseURL + "/" + key, nil
+func (s *MemoryStorage) SetShortURL(ctx context.Context, fURL string, userID string, opts URLOptions, cfg *config.Config) (string, error) {
+	rec, err := s.addURL(ctx, fURL, userID, opts)
+	if errors.Is(err, ErrConflict) {
+		return cfg.BaseURL + "/" + rec.Key, err
+	}
+	if err != nil {
+		return "", err
+	}
+	return cfg.BaseURL + "/" + rec.Key, nil
 }
 
-// RetFullURL метод возвращает полный адрес по ключу от короткой ссылки.
-func (s *MemoryStorage) RetFullURL(key string) (string, error) {
-	s.RLock()
-	del := s.deletedURL[key]
-	s.RUnlock()
-	if del {
-		return "", ErrGone
+// addURL метод проверяет наличие адреса у пользователя и занятость псевдонима, сохраняет и возвращает запись.
+// При конфликте возвращается запись с ранее выданным ключом.
+func (s *MemoryStorage) addURL(ctx context.Context, fURL, userID string, opts URLOptions) (storageStruct, error) {
+	us := s.userShard(userID)
+	us.Lock()
+	defer us.Unlock()
+	owner := us.users[userID]
+	if owner != nil {
+		if key, ok := owner.keys[fURL]; ok {
+			return storageStruct{Key: key}, ErrConflict
+		}
+	} else {
+		owner = &userIndex{id: userID, keys: make(map[string]string, 1)}
+	}
+	now := time.Now().UnixNano()
+	r := urlRecord{value: fURL, owner: owner, expires: unixNano(&opts.ExpiresAt), created: now, updated: now}
+	key := opts.Alias
+	if key != "" {
+		if !s.store(key, r) {
+			return storageStruct{}, ErrAliasTaken
+		}
+	} else {
+		var err error
+		key, err = s.newKey(ctx, fURL, r)
+		if err != nil {
+			return storageStruct{}, err
+		}
+	}
+	us.users[userID] = owner
+	owner.keys[fURL] = key
+	return r.toStorageStruct(key), nil
+}
+
+// newKey метод подбирает свободный ключ для адреса и сохраняет с ним запись.
+func (s *MemoryStorage) newKey(ctx context.Context, fURL string, r urlRecord) (string, error) {
+	for attempt := 0; attempt < maxKeyAttempts; attempt++ {
+		key, err := generateKey(ctx, s.keyGen, fURL, attempt)
+		if err != nil {
+			return "", err
+		}
+		if s.store(key, r) {
+			return key, nil
+		}
 	}
-	s.RLock()
-	fURL, ok := s.baseURL[key]
-	s.RUnlock()
+	return "", ErrKeyCollision
+}
+
+// store метод сохраняет запись, если ключ свободен.
+func (s *MemoryStorage) store(key string, r urlRecord) bool {
+	sh := s.urlShard(key)
+	sh.Lock()
+	defer sh.Unlock()
+	if _, ok := sh.urls[key]; ok {
+		return false
+	}
+	sh.urls[key] = r
+	return true
+}
+
+// put метод сохраняет запись без проверок, например при восстановлении из файла.
+func (s *MemoryStorage) put(rec storageStruct) {
+	us := s.userShard(rec.UserID)
+	us.Lock()
+	defer us.Unlock()
+	owner := us.owner(rec.UserID)
+	sh := s.urlShard(rec.Key)
+	sh.Lock()
+	sh.urls[rec.Key] = newURLRecord(rec, owner)
+	sh.Unlock()
+	owner.keys[rec.Value] = rec.Key
+}
+
+// insert метод сохраняет запись, если ключ свободен и адрес еще не сокращен пользователем.
+func (s *MemoryStorage) insert(rec storageStruct) bool {
+	us := s.userShard(rec.UserID)
+	us.Lock()
+	defer us.Unlock()
+	owner, ok := us.users[rec.UserID]
+	if ok {
+		if _, ok = owner.keys[rec.Value]; ok {
+			return false
+		}
+	} else {
+		owner = &userIndex{id: rec.UserID, keys: make(map[string]string, 1)}
+	}
+	if !s.store(rec.Key, newURLRecord(rec, owner)) {
+		return false
+	}
+	us.users[rec.UserID] = owner
+	owner.keys[rec.Value] = rec.Key
+	return true
+}
+
+// update метод заменяет исходный адрес, признак удаления и срок действия сохраненной записи.
+func (s *MemoryStorage) update(rec storageStruct) bool {
+	sh := s.urlShard(rec.Key)
+	sh.RLock()
+	r, ok := sh.urls[rec.Key]
+	sh.RUnlock()
+	if !ok {
+		return false
+	}
+	us := s.userShard(r.owner.id)
+	us.Lock()
+	defer us.Unlock()
+	sh.Lock()
+	defer sh.Unlock()
+	r, ok = sh.urls[rec.Key]
 	if !ok {
-		return key, ErrNoContent
+		return false
+	}
+	if r.owner.keys[r.value] == rec.Key {
+		delete(r.owner.keys, r.value)
+	}
+	updated := newURLRecord(rec, r.owner)
+	if r.deleted && rec.Deleted && rec.DeletedAt == nil {
+		updated.deletedAt = r.deletedAt
+	}
+	if rec.CreatedAt == nil {
+		updated.created = r.created
+	}
+	if rec.UpdatedAt == nil && r.updated > updated.updated {
+		updated.updated = r.updated
 	}
-	return fURL, nil
+	r = updated
+	sh.urls[rec.Key] = r
+	r.owner.keys[rec.Value] = rec.Key
+	return true
 }
 
-// ReturnAllURLs метод возвращает список сокращенных адресов по ID пользователя.
-func (s *MemoryStorage) ReturnAllURLs(userID string, cfg *config.Config) ([]urls, error) {
-	if len(s.baseURL) == 0 {
-		return nil, ErrNoContent
+// owner метод возвращает индекс пользователя, создавая его при отсутствии. Вызывается под блокировкой.
+func (us *userShard) owner(userID string) *userIndex {
+	owner, ok := us.users[userID]
+	if !ok {
+		owner = &userIndex{id: userID, keys: make(map[string]string, 1)}
+		us.users[userID] = owner
+	}
+	return owner
+}
+
+// record метод возвращает запись по ключу.
+func (s *MemoryStorage) record(key string) (urlRecord, bool) {
+	sh := s.urlShard(key)
+	sh.RLock()
+	defer sh.RUnlock()
+	r, ok := sh.urls[key]
+	return r, ok
+}
+
+// count метод возвращает количество сохраненных адресов.
+func (s *MemoryStorage) count() int {
+	n := 0
+	for i := range s.urls {
+		s.urls[i].RLock()
+		n += len(s.urls[i].urls)
+		s.urls[i].RUnlock()
+	}
+	return n
+}
+
+// RetFullURL метод возвращает полный адрес по ключу от короткой ссылки.
+func (s *MemoryStorage) RetFullURL(ctx context.Context, key string) (string, error) {
+	r, ok := s.record(key)
+	if !ok {
+		return "", ErrNoContent
+	}
+	if r.expires != 0 && r.expires <= time.Now().UnixNano() {
+		return "", ErrExpired
 	}
-	var allURLs = make([]urls, 0)
-	for key, value := range s.baseURL {
-		s.RLock()
-		id := s.userURL[key]
-		s.RUnlock()
-		if id == userID {
-			allURLs = append(allURLs, urls{cfg.BaseURL + "/" + key, value})
+	if r.deleted {
+		return "", ErrGone
+	}
+	return r.value, nil
+}
+
+// userKeys метод возвращает ключи адресов пользователя больше after в порядке возрастания.
+func (s *MemoryStorage) userKeys(userID, after string, limit int) []string {
+	us := s.userShard(userID)
+	us.RLock()
+	keys := make([]string, 0)
+	if owner, ok := us.users[userID]; ok {
+		for _, key := range owner.keys {
+			if key > after {
+				keys = append(keys, key)
+			}
 		}
 	}
-	if len(allURLs) == 0 {
-		return nil, ErrNoContent
+	us.RUnlock()
+	sort.Strings(keys)
+	if len(keys) > limit {
+		keys = keys[:limit]
 	}
-	return allURLs, nil
+	return keys
 }
 
 // CheckPing метод возвращает статус подключения к базе данных.
-func (s *MemoryStorage) CheckPing(cfg *config.Config) error {
+func (s *MemoryStorage) CheckPing(ctx context.Context, cfg *config.Config) error {
 	return errors.New("wrong DB used: memory storage")
 }
 
 // WriteMultiURL метод обрабатывает, сохраняет и возвращает batch список сокращенных адресов.
-func (s *MemoryStorage) WriteMultiURL(m []MultiURL, userID string, cfg *config.Config) ([]MultiURL, error) {
-	r := make([]MultiURL, len(m))
+func (s *MemoryStorage) WriteMultiURL(ctx context.Context, m []MultiURL, userID string, cfg *config.Config) ([]MultiURL, error) {
+	r := newBatchResult(m)
 	for i, v := range m {
-		key := hashStr(v.OriginURL)
-		s.Lock()
-		s.baseURL[key] = v.OriginURL
-		s.userURL[key] = userID
-		s.deletedURL[key] = false
-		s.Unlock()
-		r[i].CorrID = v.CorrID
-		r[i].ShortURL = string(cfg.BaseURL + "/" + key)
+		if r[i].Status == BatchInvalid {
+			continue
+		}
+		rec, err := s.addURL(ctx, v.OriginURL, userID, batchOptions(v))
+		r[i].Status = BatchCreated
+		if errors.Is(err, ErrConflict) {
+			r[i].Status = BatchExists
+		} else if err != nil {
+			return nil, err
+		}
+		r[i].ShortURL = string(cfg.BaseURL + "/" + rec.Key)
+		r[i].ExpiresAt = rec.ExpiresAt
 	}
 	return r, nil
 }
@@ -108,27 +369,129 @@ func (s *MemoryStorage) CloseDB() {
 }
 
 // MarkDeleted метод помечает на удаление адреса пользователя в хранилище.
-func (s *MemoryStorage) MarkDeleted(keys []string, ids []string) {
-	s.Lock()
+func (s *MemoryStorage) MarkDeleted(ctx context.Context, keys []string, ids []string) error {
+	s.markDeleted(keys, ids)
+	return nil
+}
+
+// markDeleted метод помечает удаленными адреса, принадлежащие пользователям, и возвращает их.
+// UpdatedAt возвращенных адресов - время изменения до удаления, которое возвращается при отмене удаления.
+func (s *MemoryStorage) markDeleted(keys []string, ids []string) []storageStruct {
+	deleted := make([]storageStruct, 0, len(keys))
+	now := time.Now().UTC()
 	for i, key := range keys {
-		if s.userURL[key] == ids[i] {
-			s.deletedURL[key] = true
+		sh := s.urlShard(key)
+		sh.Lock()
+		if r, ok := sh.urls[key]; ok && r.owner.id == ids[i] && !r.deleted {
+			updatedAt := time.Unix(0, r.updated).UTC()
+			r.deleted, r.deletedAt, r.updated = true, now.UnixNano(), now.UnixNano()
+			sh.urls[key] = r
+			deleted = append(deleted, storageStruct{UserID: ids[i], Key: key, DeletedAt: &now, UpdatedAt: &updatedAt})
+		}
+		sh.Unlock()
+	}
+	return deleted
+}
+
+// unmarkDeleted метод снимает отметку об удалении с адресов, которые с тех пор не восстанавливались и не удалялись снова.
+func (s *MemoryStorage) unmarkDeleted(recs []storageStruct) {
+	for _, rec := range recs {
+		sh := s.urlShard(rec.Key)
+		sh.Lock()
+		if r, ok := sh.urls[rec.Key]; ok && r.deleted && r.deletedAt == unixNano(rec.DeletedAt) {
+			r.deleted, r.deletedAt, r.updated = false, 0, unixNano(rec.UpdatedAt)
+			sh.urls[rec.Key] = r
+		}
+		sh.Unlock()
+	}
+}
+
+// dropAdded метод удаляет добавленные адреса, которые с тех пор не изменялись, вместе с переходами по ним.
+// Индекс пользователя, у которого не осталось адресов, удаляется.
+func (s *MemoryStorage) dropAdded(recs []storageStruct) {
+	for _, rec := range recs {
+		us := s.userShard(rec.UserID)
+		us.Lock()
+		sh := s.urlShard(rec.Key)
+		sh.Lock()
+		if r, ok := sh.urls[rec.Key]; ok && r.owner.id == rec.UserID && r.value == rec.Value && !r.deleted &&
+			r.created == unixNano(rec.CreatedAt) && r.updated == unixNano(rec.UpdatedAt) {
+			delete(sh.urls, rec.Key)
+			delete(sh.clicks, rec.Key)
+			delete(sh.versions, rec.Key)
+			if r.owner.keys[r.value] == rec.Key {
+				delete(r.owner.keys, r.value)
+			}
+			if len(r.owner.keys) == 0 && us.users[r.owner.id] == r.owner {
+				delete(us.users, r.owner.id)
+			}
+		}
+		sh.Unlock()
+		us.Unlock()
+	}
+}
+
+// ExpireURLs метод помечает удаленными адреса с истекшим сроком действия и возвращает их количество.
+func (s *MemoryStorage) ExpireURLs(ctx context.Context, now time.Time) (int, error) {
+	return len(s.expireURLs(now)), nil
+}
+
+// expireURLs метод помечает удаленными просроченные адреса и возвращает их.
+func (s *MemoryStorage) expireURLs(now time.Time) []storageStruct {
+	expiredURLs := make([]storageStruct, 0)
+	now = now.UTC()
+	deadline := now.UnixNano()
+	for i := range s.urls {
+		sh := &s.urls[i]
+		sh.Lock()
+		for key, r := range sh.urls {
+			if r.expires != 0 && r.expires <= deadline && !r.deleted {
+				r.deleted, r.deletedAt, r.updated = true, deadline, deadline
+				sh.urls[key] = r
+				expiredURLs = append(expiredURLs, storageStruct{UserID: r.owner.id, Key: key, DeletedAt: &now})
+			}
 		}
+		sh.Unlock()
 	}
-	s.Unlock()
+	return expiredURLs
 }
 
-// ReturnStats метод возвращает статистику по количеству сохраненных сокращенных URL и пользователей.
-func (s *MemoryStorage) ReturnStats() (*stats, error) {
-	temp := make(map[string]bool)
-	for _, v := range s.userURL {
-		if !temp[v] {
-			temp[v] = true
+// SaveClicks метод сохраняет пакет переходов по коротким ссылкам.
+// Переходы по отсутствующим в хранилище адресам, например удаленным до сохранения пакета, отбрасываются.
+func (s *MemoryStorage) SaveClicks(ctx context.Context, clicks []Click) error {
+	for _, c := range clicks {
+		sh := s.urlShard(c.Key)
+		sh.Lock()
+		if _, ok := sh.urls[c.Key]; ok {
+			sh.clicks[c.Key] = append(sh.clicks[c.Key], c)
 		}
+		sh.Unlock()
+	}
+	return nil
+}
+
+// existingClicks метод возвращает переходы по адресам, имеющимся в хранилище.
+func (s *MemoryStorage) existingClicks(clicks []Click) []Click {
+	existing := make([]Click, 0, len(clicks))
+	for _, c := range clicks {
+		if _, ok := s.record(c.Key); ok {
+			existing = append(existing, c)
+		}
+	}
+	return existing
+}
+
+// ReturnClickStats метод возвращает статистику переходов по короткой ссылке ее владельцу.
+func (s *MemoryStorage) ReturnClickStats(ctx context.Context, key, userID, interval string) (*ClickStats, error) {
+	sh := s.urlShard(key)
+	sh.RLock()
+	defer sh.RUnlock()
+	r, ok := sh.urls[key]
+	if !ok {
+		return nil, ErrNoContent
 	}
-	stats := stats{
-		URLs:  len(s.baseURL),
-		Users: len(temp),
+	if r.owner.id != userID {
+		return nil, ErrForbidden
 	}
-	return &stats, nil
+	return clickStats(key, sh.clicks[key], interval), nil
 }
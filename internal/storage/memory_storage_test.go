@@ -0,0 +1,153 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"shortURL/internal/config"
+)
+
+func TestMemoryStorageConcurrent(t *testing.T) {
+	cfg := &config.Config{BaseURL: "http://127.0.0.1:8080", KeyGenerator: config.KeyHash, KeyLength: 8}
+	strg := NewMemoryStorager(NewKeyGenerator(cfg))
+	const (
+		users   = 8
+		perUser = 200
+	)
+	var wg sync.WaitGroup
+	// одинаковые адреса одного пользователя сохраняются параллельно, ключ должен быть выдан один раз
+	for u := 0; u < users; u++ {
+		for w := 0; w < 2; w++ {
+			wg.Add(1)
+			go func(u int) {
+				defer wg.Done()
+				userID := "user" + strconv.Itoa(u)
+				for i := 0; i < perUser; i++ {
+					shortURL, err := strg.SetShortURL(context.Background(), "https://go.dev/"+strconv.Itoa(i), userID, URLOptions{ExpiresAt: time.Now().Add(time.Hour)}, cfg)
+					if err != nil && !errors.Is(err, ErrConflict) {
+						t.Error(err)
+						return
+					}
+					if i%10 == 0 {
+						key := shortURL[len(cfg.BaseURL)+1:]
+						assert.NoError(t, strg.MarkDeleted(context.Background(), []string{key}, []string{userID}))
+					}
+				}
+			}(u)
+		}
+	}
+	// чтение и обслуживание идут параллельно с записью
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			_, err := strg.ReturnStats(context.Background(), time.Time{})
+			assert.NoError(t, err)
+			strg.ReturnAllURLs(context.Background(), "user0", ListOptions{}, cfg)
+			strg.ExpireURLs(context.Background(), time.Now())
+			strg.SaveClicks(context.Background(), []Click{{Key: "missing", Time: time.Now()}})
+			strg.ExportUserURLs(context.Background(), "user1", "", 50)
+		}
+	}()
+	time.Sleep(10 * time.Millisecond)
+	for u := 0; u < users; u++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			gen := NewKeyGenerator(cfg)
+			for i := 0; i < perUser; i++ {
+				key, _ := gen.NewKey(context.Background(), "https://go.dev/"+strconv.Itoa(i), 0)
+				strg.RetFullURL(context.Background(), key)
+			}
+		}()
+	}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(stop)
+	}()
+	wg.Wait()
+
+	stats, err := strg.ReturnStats(context.Background(), time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, users*perUser, stats.URLs)
+	assert.Equal(t, users, stats.Users)
+	for u := 0; u < users; u++ {
+		page, err := strg.ReturnAllURLs(context.Background(), "user"+strconv.Itoa(u), ListOptions{IncludeDeleted: true}, cfg)
+		require.NoError(t, err)
+		assert.Len(t, page.URLs, perUser)
+		deleted := 0
+		for _, v := range page.URLs {
+			_, err := strg.RetFullURL(context.Background(), v.ShortURL[len(cfg.BaseURL)+1:])
+			if errors.Is(err, ErrGone) {
+				deleted++
+			}
+		}
+		assert.Equal(t, perUser/10, deleted)
+	}
+}
+
+func TestMemoryStorageUpdate(t *testing.T) {
+	cfg := &config.Config{BaseURL: "http://127.0.0.1:8080", KeyLength: 8}
+	strg := NewMemoryStorager(NewKeyGenerator(cfg))
+	_, err := strg.SetShortURL(context.Background(), "https://go.dev/", "user1", URLOptions{Alias: "go-dev"}, cfg)
+	require.NoError(t, err)
+	expiresAt := time.Now().Add(time.Hour).UTC()
+	require.True(t, strg.update(storageStruct{Key: "go-dev", Value: "https://go.dev/doc/", ExpiresAt: &expiresAt}))
+	assert.False(t, strg.update(storageStruct{Key: "missing"}))
+
+	// после замены адреса прежний адрес снова можно сократить, новый считается уже сокращенным
+	_, err = strg.SetShortURL(context.Background(), "https://go.dev/doc/", "user1", URLOptions{}, cfg)
+	assert.ErrorIs(t, err, ErrConflict)
+	_, err = strg.SetShortURL(context.Background(), "https://go.dev/", "user1", URLOptions{}, cfg)
+	assert.NoError(t, err)
+	recs := strg.records([]string{"go-dev"})
+	require.Len(t, recs, 1)
+	assert.Equal(t, "user1", recs[0].UserID)
+	assert.True(t, expiresAt.Equal(*recs[0].ExpiresAt))
+}
+
+func TestGenerateKeyReserved(t *testing.T) {
+	cfg := &config.Config{BaseURL: "http://127.0.0.1:8080", KeyGenerator: config.KeyCounter}
+	gen := NewKeyGenerator(cfg)
+	strg := NewMemoryStorager(gen)
+	// следующее значение счетчика кодируется как "api"
+	gen.(*CounterGenerator).Seed(10*62*62 + 25*62 + 18 - 1)
+	shortURL, err := strg.SetShortURL(context.Background(), "https://go.dev/", "user1", URLOptions{}, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, cfg.BaseURL+"/apj", shortURL)
+}
+
+// ctxGenerator выдает ключ, только пока не отменен контекст запроса.
+type ctxGenerator struct{}
+
+func (ctxGenerator) NewKey(ctx context.Context, fURL string, attempt int) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return "key" + strconv.Itoa(attempt), nil
+}
+
+func TestGenerateKeyContext(t *testing.T) {
+	cfg := &config.Config{BaseURL: "http://127.0.0.1:8080"}
+	strg := NewMemoryStorager(ctxGenerator{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := strg.SetShortURL(ctx, "https://go.dev/", "user1", URLOptions{}, cfg)
+	assert.ErrorIs(t, err, context.Canceled)
+	shortURL, err := strg.SetShortURL(context.Background(), "https://go.dev/", "user1", URLOptions{}, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, cfg.BaseURL+"/key0", shortURL)
+}
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS Short_URLs;
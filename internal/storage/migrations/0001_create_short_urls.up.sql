@@ -0,0 +1,8 @@
+-- Исходная таблица адресов. IF NOT EXISTS позволяет применить миграцию к базе, созданной до появления миграций.
+CREATE TABLE IF NOT EXISTS Short_URLs(
+    key text,
+    user_id text,
+    value text,
+    deleted boolean,
+    CONSTRAINT unique_query UNIQUE (user_id, value)
+);
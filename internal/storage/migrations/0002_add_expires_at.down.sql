@@ -0,0 +1 @@
+ALTER TABLE Short_URLs DROP COLUMN IF EXISTS expires_at;
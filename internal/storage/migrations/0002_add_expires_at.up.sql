@@ -0,0 +1 @@
+ALTER TABLE Short_URLs ADD COLUMN IF NOT EXISTS expires_at timestamptz;
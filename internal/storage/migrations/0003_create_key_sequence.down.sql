@@ -0,0 +1 @@
+DROP SEQUENCE IF EXISTS short_urls_key_seq;
@@ -0,0 +1,2 @@
+-- Последовательность, из которой генератор counter получает блоки значений ключей.
+CREATE SEQUENCE IF NOT EXISTS short_urls_key_seq;
@@ -0,0 +1,2 @@
+CREATE TABLE IF NOT EXISTS Clicks(key text, clicked_at timestamptz, referrer text, user_agent text, ip text);
+CREATE INDEX IF NOT EXISTS clicks_key_idx ON Clicks (key, clicked_at);
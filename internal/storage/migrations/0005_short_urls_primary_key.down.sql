@@ -0,0 +1,5 @@
+-- Ключи, переименованные при применении миграции, не восстанавливаются.
+ALTER TABLE Short_URLs
+    DROP CONSTRAINT IF EXISTS short_urls_pkey,
+    ALTER COLUMN deleted DROP NOT NULL,
+    ALTER COLUMN deleted DROP DEFAULT;
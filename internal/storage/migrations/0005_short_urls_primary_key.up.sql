@@ -0,0 +1,20 @@
+-- Ранние версии сервиса выдавали разным пользователям одинаковый ключ для одного адреса.
+-- Первая строка с ключом сохраняет его, остальным выдается ключ с суффиксом ~номер дубликата.
+-- Дубликаты указывали на тот же адрес, что и сохраненный ключ, поэтому ранее выданные ссылки продолжают работать.
+-- Символ ~ не используется генераторами ключей и псевдонимами, поэтому новые ключи не совпадут с существующими.
+DELETE FROM Short_URLs WHERE key IS NULL;
+
+UPDATE Short_URLs s
+SET key = s.key || '~' || d.n
+FROM (
+    SELECT ctid, row_number() OVER (PARTITION BY key ORDER BY ctid) - 1 AS n
+    FROM Short_URLs
+) d
+WHERE s.ctid = d.ctid AND d.n > 0;
+
+UPDATE Short_URLs SET deleted = false WHERE deleted IS NULL;
+
+ALTER TABLE Short_URLs
+    ALTER COLUMN deleted SET DEFAULT false,
+    ALTER COLUMN deleted SET NOT NULL,
+    ADD CONSTRAINT short_urls_pkey PRIMARY KEY (key);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS short_urls_expires_at_idx;
@@ -0,0 +1,2 @@
+-- Индекс для периодической пометки просроченных адресов.
+CREATE INDEX IF NOT EXISTS short_urls_expires_at_idx ON Short_URLs (expires_at) WHERE NOT deleted AND expires_at IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS short_urls_deleted_at_idx;
+ALTER TABLE Short_URLs DROP COLUMN IF EXISTS deleted_at;
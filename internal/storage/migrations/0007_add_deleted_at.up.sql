@@ -0,0 +1,5 @@
+ALTER TABLE Short_URLs ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+-- Срок хранения адресов, удаленных до появления столбца, отсчитывается с момента миграции.
+UPDATE Short_URLs SET deleted_at = now() WHERE deleted AND deleted_at IS NULL;
+-- Индекс для периодического окончательного удаления адресов.
+CREATE INDEX IF NOT EXISTS short_urls_deleted_at_idx ON Short_URLs (deleted_at) WHERE deleted;
@@ -0,0 +1,3 @@
+-- История изменения исходных адресов коротких ссылок. Ссылка без изменений не имеет записей,
+-- при первом изменении сохраняется и прежний адрес как версия 1 без времени создания.
+CREATE TABLE IF NOT EXISTS URL_Versions(key text, version integer, value text, created_at timestamptz, PRIMARY KEY (key, version));
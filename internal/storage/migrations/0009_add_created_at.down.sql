@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS short_urls_created_at_idx;
+ALTER TABLE Short_URLs DROP COLUMN IF EXISTS created_at;
@@ -0,0 +1,3 @@
+ALTER TABLE Short_URLs ADD COLUMN IF NOT EXISTS created_at timestamptz;
+-- Время создания адресов, сохраненных до появления столбца, неизвестно и остается пустым.
+CREATE INDEX IF NOT EXISTS short_urls_created_at_idx ON Short_URLs (created_at);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS short_urls_user_created_idx;
@@ -0,0 +1,2 @@
+-- Индекс страниц списка адресов пользователя, адреса с неизвестным временем создания идут первыми.
+CREATE INDEX IF NOT EXISTS short_urls_user_created_idx ON Short_URLs (user_id, (coalesce(created_at, 'epoch'::timestamptz)), key COLLATE "C");
@@ -0,0 +1,3 @@
+ALTER TABLE Short_URLs ADD COLUMN IF NOT EXISTS updated_at timestamptz;
+-- Временем изменения сохраненных ранее адресов считается последнее из известных времени создания и удаления.
+UPDATE Short_URLs SET updated_at = GREATEST(created_at, deleted_at) WHERE updated_at IS NULL;
@@ -0,0 +1,249 @@
+// Модуль применяет и откатывает миграции схемы базы данных PostgreSQL.
+// Миграции хранятся в файлах NNNN_описание.up.sql и NNNN_описание.down.sql, встроенных в исполняемый файл,
+// и применяются по возрастанию номера. Примененные миграции записываются в таблицу schema_migrations.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// lockID - ключ рекомендательной блокировки, под которой экземпляры сервиса применяют миграции по очереди.
+const lockID int64 = 0x73686f72745f75
+
+// ErrNoMigrations - ошибка отката при отсутствии примененных миграций.
+var ErrNoMigrations = errors.New("no applied migrations")
+
+// Migration структура с номером, названием и текстом миграции.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// State структура с миграцией и временем ее применения, nil означает, что миграция не применена.
+type State struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Load функция читает встроенные миграции и возвращает их по возрастанию номера.
+func Load() ([]Migration, error) {
+	names, err := fs.Glob(files, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[int]*Migration)
+	for _, fileName := range names {
+		base, direction, ok := cutDirection(fileName)
+		if !ok {
+			return nil, fmt.Errorf("migration %s: expected .up.sql or .down.sql suffix", fileName)
+		}
+		number, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s: expected NNNN_name prefix", fileName)
+		}
+		version, err := strconv.Atoi(number)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", fileName, err)
+		}
+		text, err := files.ReadFile(fileName)
+		if err != nil {
+			return nil, err
+		}
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if m.Name != name {
+			return nil, fmt.Errorf("migration %d has different names %q and %q", version, m.Name, name)
+		}
+		if direction == "up" {
+			m.Up = string(text)
+		} else {
+			m.Down = string(text)
+		}
+	}
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+func cutDirection(fileName string) (string, string, bool) {
+	for _, direction := range []string{"up", "down"} {
+		suffix := "." + direction + ".sql"
+		if strings.HasSuffix(fileName, suffix) {
+			return strings.TrimSuffix(fileName, suffix), direction, true
+		}
+	}
+	return "", "", false
+}
+
+// Up функция применяет все еще не примененные миграции и возвращает их список.
+func Up(ctx context.Context, db *sql.DB) ([]Migration, error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	applied := make([]Migration, 0)
+	err = withLock(ctx, db, func(conn *sql.Conn) error {
+		done, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, m := range migrations {
+			if _, ok := done[m.Version]; ok {
+				continue
+			}
+			err = inTx(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, m.Up); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations(version, name) VALUES($1, $2)", m.Version, m.Name)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %d_%s up: %w", m.Version, m.Name, err)
+			}
+			applied = append(applied, m)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down функция откатывает последнюю примененную миграцию и возвращает ее.
+func Down(ctx context.Context, db *sql.DB) (Migration, error) {
+	migrations, err := Load()
+	if err != nil {
+		return Migration{}, err
+	}
+	var reverted Migration
+	err = withLock(ctx, db, func(conn *sql.Conn) error {
+		var version int
+		err := conn.QueryRowContext(ctx, "SELECT version FROM schema_migrations ORDER BY version DESC LIMIT 1").Scan(&version)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNoMigrations
+		}
+		if err != nil {
+			return err
+		}
+		i := sort.Search(len(migrations), func(i int) bool { return migrations[i].Version >= version })
+		if i == len(migrations) || migrations[i].Version != version {
+			return fmt.Errorf("migration %d is applied but unknown to this build", version)
+		}
+		m := migrations[i]
+		if m.Down == "" {
+			return fmt.Errorf("migration %d_%s has no down file", m.Version, m.Name)
+		}
+		err = inTx(ctx, conn, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, m.Down); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = $1", m.Version)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d_%s down: %w", m.Version, m.Name, err)
+		}
+		reverted = m
+		return nil
+	})
+	return reverted, err
+}
+
+// Status функция возвращает список миграций с временем их применения.
+func Status(ctx context.Context, db *sql.DB) ([]State, error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	states := make([]State, 0, len(migrations))
+	err = withLock(ctx, db, func(conn *sql.Conn) error {
+		done, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, m := range migrations {
+			state := State{Migration: m}
+			if appliedAt, ok := done[m.Version]; ok {
+				state.AppliedAt = &appliedAt
+			}
+			states = append(states, state)
+		}
+		return nil
+	})
+	return states, err
+}
+
+// withLock функция выполняет действие на одном соединении под рекомендательной блокировкой.
+func withLock(ctx context.Context, db *sql.DB, fn func(conn *sql.Conn) error) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if _, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockID); err != nil {
+		return err
+	}
+	// блокировка снимается без контекста запроса, чтобы не оставить ее при отмене контекста
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockID)
+	_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations(
+		version integer PRIMARY KEY,
+		name text NOT NULL,
+		applied_at timestamptz NOT NULL DEFAULT now())`)
+	if err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// appliedVersions функция возвращает номера примененных миграций и время их применения.
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[int]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	done := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err = rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		done[version] = appliedAt
+	}
+	return done, rows.Err()
+}
+
+func inTx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err = fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,262 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"shortURL/internal/config"
+)
+
+// Usage структура с использованием ограничений пользователя: количеством действующих адресов,
+// количеством адресов, созданных не ранее начала окна, и временем создания самого раннего из них.
+type Usage struct {
+	Active       int
+	Created      int
+	FirstCreated *time.Time
+}
+
+// Quota структура с ограничениями пользователя и их использованием. Нулевое ограничение означает его отсутствие.
+// ResetsAt - время, когда самый ранний адрес окна перестанет учитываться и пользователь сможет создать новый адрес.
+type Quota struct {
+	ActiveURLs    int        `json:"active_urls"`
+	MaxActiveURLs int        `json:"max_active_urls"`
+	Created       int        `json:"created"`
+	MaxCreated    int        `json:"max_created"`
+	WindowSeconds int64      `json:"window_seconds"`
+	ResetsAt      *time.Time `json:"resets_at,omitempty"`
+}
+
+// UserQuota функция возвращает ограничения пользователя из конфигурации сервиса и их текущее использование.
+func UserQuota(ctx context.Context, strg Storager, userID string, cfg *config.Config) (*Quota, error) {
+	now := time.Now().UTC()
+	usage, err := strg.ReturnUsage(ctx, userID, now.Add(-cfg.QuotaWindow))
+	if err != nil {
+		return nil, err
+	}
+	return newQuota(usage, cfg.QuotaMaxURLs, cfg.QuotaMaxCreated, cfg.QuotaWindow), nil
+}
+
+func newQuota(usage *Usage, maxURLs, maxCreated int, window time.Duration) *Quota {
+	q := Quota{
+		ActiveURLs:    usage.Active,
+		MaxActiveURLs: maxURLs,
+		Created:       usage.Created,
+		MaxCreated:    maxCreated,
+		WindowSeconds: int64(window / time.Second),
+	}
+	if usage.FirstCreated != nil {
+		resetsAt := usage.FirstCreated.Add(window).UTC()
+		q.ResetsAt = &resetsAt
+	}
+	return &q
+}
+
+// check метод проверяет, можно ли добавить active действующих адресов и создать created новых.
+func (q *Quota) check(active, created int) error {
+	if q.MaxActiveURLs > 0 && active > 0 && q.ActiveURLs+active > q.MaxActiveURLs {
+		return fmt.Errorf("%w: %d of %d active URLs used", ErrQuotaExceeded, q.ActiveURLs, q.MaxActiveURLs)
+	}
+	if q.MaxCreated > 0 && created > 0 && q.Created+created > q.MaxCreated {
+		return fmt.Errorf("%w: %d of %d URLs created in %s", ErrQuotaExceeded, q.Created, q.MaxCreated, time.Duration(q.WindowSeconds)*time.Second)
+	}
+	return nil
+}
+
+// quotaStorage ограничивает количество действующих адресов пользователя и количество адресов,
+// созданных им за скользящее окно. Проверка и создание адресов пользователя выполняются под блокировкой,
+// поэтому одновременные запросы одного пользователя в одном экземпляре сервиса не превышают ограничений.
+type quotaStorage struct {
+	Storager
+	maxURLs    int
+	maxCreated int
+	window     time.Duration
+	locks      [memoryShards]chan struct{}
+}
+
+// WithQuotas функция возвращает хранилище, отказывающее в создании и восстановлении адресов сверх ограничений
+// пользователя ошибкой ErrQuotaExceeded. Нулевое ограничение означает его отсутствие.
+func WithQuotas(strg Storager, maxURLs, maxCreated int, window time.Duration) Storager {
+	s := quotaStorage{Storager: strg, maxURLs: maxURLs, maxCreated: maxCreated, window: window}
+	for i := range s.locks {
+		s.locks[i] = make(chan struct{}, 1)
+	}
+	return &s
+}
+
+// reserve метод блокирует создание адресов пользователя и проверяет ограничения.
+// При успешной проверке возвращается функция снятия блокировки.
+func (s *quotaStorage) reserve(ctx context.Context, userID string, active, created int) (func(), error) {
+	unlock, err := s.lock(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.check(ctx, userID, active, created); err != nil {
+		unlock()
+		return nil, err
+	}
+	return unlock, nil
+}
+
+// reserveURLs метод блокирует создание адресов пользователя и проверяет ограничения для адресов fURLs.
+// Адреса, ранее сокращенные пользователем, не считаются новыми: для них хранилище вернет существующую ссылку.
+func (s *quotaStorage) reserveURLs(ctx context.Context, userID string, fURLs map[string]bool) (func(), error) {
+	unlock, err := s.lock(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]string, 0, len(fURLs))
+	for fURL := range fURLs {
+		values = append(values, fURL)
+	}
+	existing, err := s.Storager.ReturnUserKeys(ctx, userID, values)
+	if err == nil {
+		n := len(fURLs) - len(existing)
+		err = s.check(ctx, userID, n, n)
+	}
+	if err != nil {
+		unlock()
+		return nil, err
+	}
+	return unlock, nil
+}
+
+// lock метод блокирует создание адресов пользователя и возвращает функцию снятия блокировки.
+func (s *quotaStorage) lock(ctx context.Context, userID string) (func(), error) {
+	lock := s.locks[shardOf(userID)]
+	select {
+	case lock <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return func() { <-lock }, nil
+}
+
+// check метод проверяет, можно ли пользователю добавить active действующих адресов и создать created новых.
+func (s *quotaStorage) check(ctx context.Context, userID string, active, created int) error {
+	if active == 0 && created == 0 {
+		return nil
+	}
+	usage, err := s.Storager.ReturnUsage(ctx, userID, time.Now().Add(-s.window))
+	if err != nil {
+		return err
+	}
+	return newQuota(usage, s.maxURLs, s.maxCreated, s.window).check(active, created)
+}
+
+// SetShortURL метод сохраняет адрес, если пользователь не превысил ограничения.
+// Повторное сокращение адреса не расходует ограничений и возвращает ErrConflict с прежней ссылкой.
+func (s *quotaStorage) SetShortURL(ctx context.Context, fURL string, userID string, opts URLOptions, cfg *config.Config) (string, error) {
+	unlock, err := s.reserveURLs(ctx, userID, map[string]bool{fURL: true})
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	return s.Storager.SetShortURL(ctx, fURL, userID, opts, cfg)
+}
+
+// WriteMultiURL метод сохраняет пакет адресов, если пользователь не превысил ограничения.
+// Новым считается каждый различный адрес пакета, кроме отклоненных при проверке и ранее сокращенных пользователем,
+// пакет сверх ограничений отклоняется целиком.
+func (s *quotaStorage) WriteMultiURL(ctx context.Context, m []MultiURL, userID string, cfg *config.Config) ([]MultiURL, error) {
+	fURLs := make(map[string]bool, len(m))
+	for _, v := range m {
+		if v.Status != BatchInvalid {
+			fURLs[v.OriginURL] = true
+		}
+	}
+	unlock, err := s.reserveURLs(ctx, userID, fURLs)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return s.Storager.WriteMultiURL(ctx, m, userID, cfg)
+}
+
+// RestoreURLs метод восстанавливает адреса, если их количество не превысит ограничение действующих адресов.
+func (s *quotaStorage) RestoreURLs(ctx context.Context, keys []string, userID string, since time.Time) ([]RestoreResult, error) {
+	unlock, err := s.reserve(ctx, userID, len(keys), 0)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return s.Storager.RestoreURLs(ctx, keys, userID, since)
+}
+
+// ReturnUsage метод возвращает использование ограничений пользователя по адресам, сохраненным в памяти.
+func (s *MemoryStorage) ReturnUsage(ctx context.Context, userID string, since time.Time) (*Usage, error) {
+	var usage Usage
+	now, from := time.Now().UnixNano(), since.UnixNano()
+	first := int64(0)
+	us := s.userShard(userID)
+	us.RLock()
+	defer us.RUnlock()
+	owner, ok := us.users[userID]
+	if !ok {
+		return &usage, nil
+	}
+	for _, key := range owner.keys {
+		r, ok := s.record(key)
+		if !ok {
+			continue
+		}
+		if !r.deleted && (r.expires == 0 || r.expires > now) {
+			usage.Active++
+		}
+		if r.created != 0 && r.created >= from {
+			usage.Created++
+			if first == 0 || r.created < first {
+				first = r.created
+			}
+		}
+	}
+	if first != 0 {
+		firstCreated := time.Unix(0, first).UTC()
+		usage.FirstCreated = &firstCreated
+	}
+	return &usage, nil
+}
+
+// ReturnUserKeys метод возвращает ключи ссылок, ранее выданных пользователю для адресов fURLs, по адресу.
+// Адреса, которые пользователь не сокращал, в результат не попадают.
+func (s *MemoryStorage) ReturnUserKeys(ctx context.Context, userID string, fURLs []string) (map[string]string, error) {
+	keys := make(map[string]string)
+	us := s.userShard(userID)
+	us.RLock()
+	defer us.RUnlock()
+	owner, ok := us.users[userID]
+	if !ok {
+		return keys, nil
+	}
+	for _, fURL := range fURLs {
+		if key, ok := owner.keys[fURL]; ok {
+			keys[fURL] = key
+		}
+	}
+	return keys, nil
+}
+
+// ReturnUserKeys метод возвращает ключи ссылок, ранее выданных пользователю для адресов fURLs, одним запросом.
+func (s *SQLStorage) ReturnUserKeys(ctx context.Context, userID string, fURLs []string) (map[string]string, error) {
+	keys := make(map[string]string)
+	err := scanBatch(ctx, s.Pool, func(key, fURL string, _ *time.Time) { keys[fURL] = key },
+		"SELECT key, value, expires_at FROM Short_URLs WHERE user_id = $1 AND value = ANY($2)", userID, fURLs)
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// ReturnUsage метод возвращает использование ограничений пользователя одним запросом по индексу адресов пользователя.
+func (s *SQLStorage) ReturnUsage(ctx context.Context, userID string, since time.Time) (*Usage, error) {
+	var usage Usage
+	err := s.Pool.QueryRow(ctx, `SELECT count(*) FILTER (WHERE NOT deleted AND (expires_at IS NULL OR expires_at > $3)),
+			count(*) FILTER (WHERE coalesce(created_at, 'epoch'::timestamptz) >= $2),
+			min(created_at) FILTER (WHERE coalesce(created_at, 'epoch'::timestamptz) >= $2)
+		FROM Short_URLs WHERE user_id = $1`, userID, since, time.Now()).
+		Scan(&usage.Active, &usage.Created, &usage.FirstCreated)
+	if err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
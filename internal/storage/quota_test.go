@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"shortURL/internal/config"
+)
+
+func TestQuotaActiveURLs(t *testing.T) {
+	cfg := &config.Config{BaseURL: "http://127.0.0.1:8080", KeyLength: 8}
+	strg := WithQuotas(NewMemoryStorager(NewKeyGenerator(cfg)), 2, 0, time.Hour)
+	ctx := context.Background()
+	for _, alias := range []string{"go-dev", "go-blog"} {
+		_, err := strg.SetShortURL(ctx, "https://go.dev/"+alias, "user1", URLOptions{Alias: alias}, cfg)
+		require.NoError(t, err)
+	}
+	_, err := strg.SetShortURL(ctx, "https://go.dev/doc/", "user1", URLOptions{}, cfg)
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+	_, err = strg.SetShortURL(ctx, "https://go.dev/doc/", "user2", URLOptions{}, cfg)
+	assert.NoError(t, err)
+
+	// пакет сверх ограничения отклоняется целиком, повторяющиеся и отклоненные адреса не считаются
+	_, err = strg.WriteMultiURL(ctx, []MultiURL{{CorrID: "1", OriginURL: "https://go.dev/a"}, {CorrID: "2", OriginURL: "https://go.dev/b"}}, "user2", cfg)
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+	batch, err := strg.WriteMultiURL(ctx, []MultiURL{
+		{CorrID: "1", OriginURL: "https://go.dev/a"},
+		{CorrID: "2", OriginURL: "https://go.dev/a"},
+		{CorrID: "3", OriginURL: "https://go.dev/b", Status: BatchInvalid},
+	}, "user2", cfg)
+	require.NoError(t, err)
+	assert.Equal(t, BatchCreated, batch[0].Status)
+
+	// удаленный адрес освобождает место, но восстановить его сверх ограничения нельзя
+	require.NoError(t, strg.MarkDeleted(ctx, []string{"go-dev"}, []string{"user1"}))
+	_, err = strg.SetShortURL(ctx, "https://go.dev/doc/", "user1", URLOptions{}, cfg)
+	require.NoError(t, err)
+	_, err = strg.RestoreURLs(ctx, []string{"go-dev"}, "user1", time.Now().Add(-time.Hour))
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+	_, err = strg.RetFullURL(ctx, "go-dev")
+	assert.ErrorIs(t, err, ErrGone)
+}
+
+func TestQuotaExistingURLs(t *testing.T) {
+	cfg := &config.Config{BaseURL: "http://127.0.0.1:8080", KeyLength: 8}
+	strg := WithQuotas(NewMemoryStorager(NewKeyGenerator(cfg)), 2, 2, time.Hour)
+	ctx := context.Background()
+	for _, alias := range []string{"go-dev", "go-blog"} {
+		_, err := strg.SetShortURL(ctx, "https://go.dev/"+alias, "user1", URLOptions{Alias: alias}, cfg)
+		require.NoError(t, err)
+	}
+
+	// повторное сокращение адреса на пределе ограничений возвращает прежнюю ссылку
+	shortURL, err := strg.SetShortURL(ctx, "https://go.dev/go-dev", "user1", URLOptions{}, cfg)
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.Equal(t, cfg.BaseURL+"/go-dev", shortURL)
+	batch, err := strg.WriteMultiURL(ctx, []MultiURL{
+		{CorrID: "1", OriginURL: "https://go.dev/go-dev"},
+		{CorrID: "2", OriginURL: "https://go.dev/go-blog"},
+	}, "user1", cfg)
+	require.NoError(t, err)
+	assert.Equal(t, BatchExists, batch[0].Status)
+	assert.Equal(t, cfg.BaseURL+"/go-blog", batch[1].ShortURL)
+
+	// пакет с новым адресом сверх ограничения по-прежнему отклоняется
+	_, err = strg.WriteMultiURL(ctx, []MultiURL{
+		{CorrID: "1", OriginURL: "https://go.dev/go-dev"},
+		{CorrID: "2", OriginURL: "https://go.dev/doc/"},
+	}, "user1", cfg)
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+}
+
+func TestQuotaCreatedWindow(t *testing.T) {
+	cfg := &config.Config{BaseURL: "http://127.0.0.1:8080", KeyLength: 8}
+	strg := WithQuotas(NewMemoryStorager(NewKeyGenerator(cfg)), 0, 2, 200*time.Millisecond)
+	ctx := context.Background()
+	keys := make([]string, 0, 2)
+	for i := 0; i < 2; i++ {
+		shortURL, err := strg.SetShortURL(ctx, "https://go.dev/"+strconv.Itoa(i), "user1", URLOptions{}, cfg)
+		require.NoError(t, err)
+		keys = append(keys, shortURL[len(cfg.BaseURL)+1:])
+	}
+	// удаление не возвращает созданных адресов, а восстановление их не расходует
+	require.NoError(t, strg.MarkDeleted(ctx, keys, []string{"user1", "user1"}))
+	_, err := strg.SetShortURL(ctx, "https://go.dev/2", "user1", URLOptions{}, cfg)
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+	results, err := strg.RestoreURLs(ctx, keys, "user1", time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, RestoreRestored, results[1].Status)
+
+	quota, err := UserQuota(ctx, strg, "user1", &config.Config{QuotaMaxCreated: 2, QuotaWindow: 200 * time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, 2, quota.ActiveURLs)
+	assert.Equal(t, 2, quota.Created)
+	assert.Equal(t, int64(0), quota.WindowSeconds)
+	require.NotNil(t, quota.ResetsAt)
+
+	// по окончании окна пользователь снова может создавать адреса
+	time.Sleep(time.Until(*quota.ResetsAt) + 10*time.Millisecond)
+	_, err = strg.SetShortURL(ctx, "https://go.dev/2", "user1", URLOptions{}, cfg)
+	assert.NoError(t, err)
+}
+
+func TestQuotaConcurrent(t *testing.T) {
+	cfg := &config.Config{BaseURL: "http://127.0.0.1:8080", KeyLength: 8}
+	strg := WithQuotas(NewMemoryStorager(NewKeyGenerator(cfg)), 5, 0, time.Hour)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	created := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := strg.SetShortURL(context.Background(), "https://go.dev/"+strconv.Itoa(i), "user1", URLOptions{}, cfg)
+			if err == nil {
+				mu.Lock()
+				created++
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 5, created)
+}
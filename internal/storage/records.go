@@ -0,0 +1,171 @@
+package storage
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Record структура с полным состоянием короткой ссылки для переноса между хранилищами.
+type Record struct {
+	Key       string     `json:"key"`
+	UserID    string     `json:"user_id"`
+	Value     string     `json:"value"`
+	Deleted   bool       `json:"deleted"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// RecordStorager - интерфейс хранилища, записи которого можно перенести в другое хранилище.
+// ExportRecords возвращает до limit записей с ключами больше after в порядке возрастания ключа,
+// поэтому перенос можно продолжить с последнего перенесенного ключа.
+// ImportRecords сохраняет записи с их ключами и возвращает количество сохраненных. Записи, ключ которых занят
+// или адрес которых уже сокращен тем же пользователем, пропускаются, поэтому повторный перенос безопасен.
+type RecordStorager interface {
+	Storager
+	ExportRecords(ctx context.Context, after string, limit int) ([]Record, error)
+	LookupRecords(ctx context.Context, keys []string) ([]Record, error)
+	ImportRecords(ctx context.Context, recs []Record) (int, error)
+}
+
+func (r Record) storageStruct() storageStruct {
+	return storageStruct{UserID: r.UserID, Key: r.Key, Value: r.Value, Deleted: r.Deleted, DeletedAt: r.DeletedAt, ExpiresAt: r.ExpiresAt, CreatedAt: r.CreatedAt, UpdatedAt: r.UpdatedAt}
+}
+
+// ExportRecords метод возвращает страницу записей хранилища в порядке возрастания ключа.
+func (s *MemoryStorage) ExportRecords(ctx context.Context, after string, limit int) ([]Record, error) {
+	return s.records(s.pageKeys(after, limit)), nil
+}
+
+// LookupRecords метод возвращает записи с заданными ключами, отсутствующие ключи пропускаются.
+func (s *MemoryStorage) LookupRecords(ctx context.Context, keys []string) ([]Record, error) {
+	return s.records(keys), nil
+}
+
+// ImportRecords метод сохраняет записи с их ключами и возвращает количество сохраненных.
+func (s *MemoryStorage) ImportRecords(ctx context.Context, recs []Record) (int, error) {
+	return len(s.importRecords(recs)), nil
+}
+
+// pageKeys метод возвращает до limit ключей больше after в порядке возрастания.
+func (s *MemoryStorage) pageKeys(after string, limit int) []string {
+	keys := make([]string, 0)
+	for i := range s.urls {
+		sh := &s.urls[i]
+		sh.RLock()
+		for key := range sh.urls {
+			if key > after {
+				keys = append(keys, key)
+			}
+		}
+		sh.RUnlock()
+	}
+	sort.Strings(keys)
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+	return keys
+}
+
+// records метод возвращает записи по ключам, отсутствующие ключи пропускаются.
+func (s *MemoryStorage) records(keys []string) []Record {
+	recs := make([]Record, 0, len(keys))
+	for _, key := range keys {
+		r, ok := s.record(key)
+		if !ok {
+			continue
+		}
+		rec := r.toStorageStruct(key)
+		recs = append(recs, Record{Key: key, UserID: rec.UserID, Value: rec.Value, Deleted: rec.Deleted, DeletedAt: rec.DeletedAt, ExpiresAt: rec.ExpiresAt, CreatedAt: rec.CreatedAt, UpdatedAt: rec.UpdatedAt})
+	}
+	return recs
+}
+
+// importRecords метод сохраняет записи со свободными ключами и возвращает сохраненные.
+func (s *MemoryStorage) importRecords(recs []Record) []storageStruct {
+	imported := make([]storageStruct, 0, len(recs))
+	for _, rec := range recs {
+		if s.insert(rec.storageStruct()) {
+			imported = append(imported, rec.storageStruct())
+		}
+	}
+	return imported
+}
+
+// ImportRecords метод сохраняет записи с их ключами, дописывает их в файл и возвращает количество сохраненных.
+func (s *FileStorage) ImportRecords(ctx context.Context, recs []Record) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	s.Lock()
+	imported := s.importRecords(recs)
+	logRecs := make([]logRecord, 0, len(imported))
+	for _, rec := range imported {
+		logRecs = append(logRecs, setRecord(rec))
+	}
+	done := s.appendLog(logRecs)
+	s.Unlock()
+	if err := waitDone(done); err != nil {
+		return 0, err
+	}
+	return len(imported), nil
+}
+
+// ExportRecords метод возвращает страницу записей таблицы в порядке возрастания ключа.
+func (s *SQLStorage) ExportRecords(ctx context.Context, after string, limit int) ([]Record, error) {
+	return s.queryRecords(ctx, "SELECT key, user_id, value, deleted, deleted_at, expires_at, created_at, updated_at FROM Short_URLs WHERE key > $1 ORDER BY key LIMIT $2", after, limit)
+}
+
+// LookupRecords метод возвращает записи с заданными ключами, отсутствующие ключи пропускаются.
+func (s *SQLStorage) LookupRecords(ctx context.Context, keys []string) ([]Record, error) {
+	return s.queryRecords(ctx, "SELECT key, user_id, value, deleted, deleted_at, expires_at, created_at, updated_at FROM Short_URLs WHERE key = ANY($1) ORDER BY key", keys)
+}
+
+// ImportRecords метод сохраняет записи одним запросом и возвращает количество сохраненных.
+func (s *SQLStorage) ImportRecords(ctx context.Context, recs []Record) (int, error) {
+	keys := make([]string, len(recs))
+	ids := make([]string, len(recs))
+	values := make([]string, len(recs))
+	deleted := make([]bool, len(recs))
+	deletedAt := make([]*time.Time, len(recs))
+	expires := make([]*time.Time, len(recs))
+	created := make([]*time.Time, len(recs))
+	updated := make([]*time.Time, len(recs))
+	for i, rec := range recs {
+		keys[i], ids[i], values[i], deleted[i], expires[i], created[i] = rec.Key, rec.UserID, rec.Value, rec.Deleted, rec.ExpiresAt, rec.CreatedAt
+		if rec.Deleted {
+			deletedAt[i] = deletedTime(rec.DeletedAt)
+		}
+		updated[i] = updatedTime(rec.UpdatedAt, created[i], deletedAt[i])
+	}
+	result, err := s.Pool.Exec(ctx, `INSERT INTO Short_URLs(key, user_id, value, deleted, deleted_at, expires_at, created_at, updated_at)
+		SELECT * FROM unnest($1::text[], $2::text[], $3::text[], $4::bool[], $5::timestamptz[], $6::timestamptz[], $7::timestamptz[], $8::timestamptz[])
+		ON CONFLICT DO NOTHING`, keys, ids, values, deleted, deletedAt, expires, created, updated)
+	if err != nil {
+		return 0, err
+	}
+	return int(result.RowsAffected()), nil
+}
+
+// queryRecords метод выполняет запрос, возвращающий записи таблицы коротких ссылок.
+func (s *SQLStorage) queryRecords(ctx context.Context, query string, args ...any) ([]Record, error) {
+	rows, err := s.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (Record, error) {
+		var rec Record
+		err := row.Scan(&rec.Key, &rec.UserID, &rec.Value, &rec.Deleted, &rec.DeletedAt, &rec.ExpiresAt, &rec.CreatedAt, &rec.UpdatedAt)
+		return rec, err
+	})
+}
+
+var (
+	_ RecordStorager = (*MemoryStorage)(nil)
+	_ RecordStorager = (*FileStorage)(nil)
+	_ RecordStorager = (*SQLStorage)(nil)
+)
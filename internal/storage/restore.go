@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Результаты восстановления удаленного адреса.
+const (
+	RestoreRestored     = "restored"
+	RestoreNotDeleted   = "not_deleted"
+	RestoreNotFound     = "not_found"
+	RestoreExpired      = "expired"
+	RestoreWindowPassed = "window_passed"
+)
+
+// RestoreResult структура с результатом восстановления адреса по ключу короткой ссылки.
+type RestoreResult struct {
+	Key    string `json:"key"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// RestoreUserURLs функция восстанавливает адреса пользователя, удаленные не ранее чем window назад,
+// и возвращает результат для каждого ключа. Чужие и отсутствующие адреса получают статус not_found,
+// адреса с истекшим сроком действия не восстанавливаются. Повторы ключей не учитываются.
+// Удаление выполняется асинхронно, поэтому еще не обработанный адрес получает статус not_deleted.
+func RestoreUserURLs(ctx context.Context, strg Storager, keys []string, userID string, window time.Duration) ([]RestoreResult, error) {
+	unique := make([]string, 0, len(keys))
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, key)
+		}
+	}
+	results, err := strg.RestoreURLs(ctx, unique, userID, time.Now().Add(-window))
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		switch results[i].Status {
+		case RestoreNotFound:
+			results[i].Error = "short URL not found"
+		case RestoreExpired:
+			results[i].Error = "URL expired"
+		case RestoreWindowPassed:
+			results[i].Error = fmt.Errorf("%w: URL was deleted more than %s ago", ErrRestoreWindow, window).Error()
+		}
+	}
+	return results, nil
+}
+
+// restoreStatus функция определяет результат восстановления по состоянию адреса до восстановления.
+// Восстанавливается удаленный адрес пользователя, удаленный не ранее since, срок действия которого не истек.
+func restoreStatus(found, deleted bool, deletedAt, expiresAt *time.Time, since, now time.Time) string {
+	switch {
+	case !found:
+		return RestoreNotFound
+	case !deleted:
+		return RestoreNotDeleted
+	case expiresAt != nil && expired(*expiresAt, now):
+		return RestoreExpired
+	case deletedAt == nil || deletedAt.Before(since):
+		return RestoreWindowPassed
+	}
+	return RestoreRestored
+}
+
+// RestoreURLs метод снимает отметку об удалении с адресов пользователя, удаленных не ранее since.
+func (s *MemoryStorage) RestoreURLs(ctx context.Context, keys []string, userID string, since time.Time) ([]RestoreResult, error) {
+	results, _ := s.restore(keys, userID, since)
+	return results, nil
+}
+
+// restore метод снимает отметку об удалении с адресов пользователя и возвращает результаты
+// вместе с восстановленными адресами, временем их удаления и временем восстановления в UpdatedAt.
+func (s *MemoryStorage) restore(keys []string, userID string, since time.Time) ([]RestoreResult, []storageStruct) {
+	results := make([]RestoreResult, 0, len(keys))
+	restored := make([]storageStruct, 0, len(keys))
+	now := time.Now()
+	for _, key := range keys {
+		sh := s.urlShard(key)
+		sh.Lock()
+		r, ok := sh.urls[key]
+		found := ok && r.owner.id == userID
+		var rec storageStruct
+		if found {
+			rec = r.toStorageStruct(key)
+		}
+		status := restoreStatus(found, rec.Deleted, rec.DeletedAt, rec.ExpiresAt, since, now)
+		if status == RestoreRestored {
+			r.deleted, r.deletedAt, r.updated = false, 0, now.UnixNano()
+			sh.urls[key] = r
+			updatedAt := now.UTC()
+			rec.UpdatedAt = &updatedAt
+			restored = append(restored, rec)
+		}
+		sh.Unlock()
+		results = append(results, RestoreResult{Key: key, Status: status})
+	}
+	return results, restored
+}
+
+// redelete метод возвращает отметку об удалении восстановленным адресам с прежним временем удаления.
+// Адреса, удаленные снова после восстановления, не меняются.
+func (s *MemoryStorage) redelete(recs []storageStruct) {
+	for _, rec := range recs {
+		s.apply(deleteRecord(rec))
+	}
+}
+
+// RestoreURLs метод снимает отметку об удалении с адресов пользователя и дописывает в файл записи о восстановлении.
+// Если записи не удалось сохранить, адреса снова помечаются удаленными под блокировкой хранилища.
+func (s *FileStorage) RestoreURLs(ctx context.Context, keys []string, userID string, since time.Time) ([]RestoreResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.Lock()
+	results, restored := s.restore(keys, userID, since)
+	recs := make([]logRecord, 0, len(restored))
+	for _, rec := range restored {
+		recs = append(recs, restoreRecord(rec))
+	}
+	done := s.appendLog(recs)
+	s.Unlock()
+	if err := waitDone(done); err != nil {
+		s.Lock()
+		s.redelete(restored)
+		s.Unlock()
+		return nil, err
+	}
+	return results, nil
+}
+
+// RestoreURLs метод снимает отметку об удалении с адресов пользователя одним запросом.
+// Результат определяется по состоянию адресов до обновления, которое видит запрос на чтение.
+func (s *SQLStorage) RestoreURLs(ctx context.Context, keys []string, userID string, since time.Time) ([]RestoreResult, error) {
+	now := time.Now()
+	rows, err := s.Pool.Query(ctx, `WITH restored AS (
+			UPDATE Short_URLs SET deleted = false, deleted_at = NULL, updated_at = $4
+			WHERE key = ANY($1) AND user_id = $2 AND deleted AND deleted_at >= $3 AND (expires_at IS NULL OR expires_at > $4)
+			RETURNING key)
+		SELECT key, deleted, deleted_at, expires_at FROM Short_URLs WHERE key = ANY($1) AND user_id = $2`, keys, userID, since, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	statuses := make(map[string]string, len(keys))
+	for rows.Next() {
+		var key string
+		var deleted bool
+		var deletedAt, expiresAt *time.Time
+		if err = rows.Scan(&key, &deleted, &deletedAt, &expiresAt); err != nil {
+			return nil, err
+		}
+		statuses[key] = restoreStatus(true, deleted, deletedAt, expiresAt, since, now)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	results := make([]RestoreResult, 0, len(keys))
+	for _, key := range keys {
+		status, ok := statuses[key]
+		if !ok {
+			status = RestoreNotFound
+		}
+		results = append(results, RestoreResult{Key: key, Status: status})
+	}
+	return results, nil
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"shortURL/internal/config"
+)
+
+func TestFileStorageRestore(t *testing.T) {
+	cfg := &config.Config{BaseURL: "http://127.0.0.1:8080", FileStoragePath: filepath.Join(t.TempDir(), "storage.json"), KeyLength: 8}
+	strg := NewFileStorager(cfg, NewKeyGenerator(cfg))
+	for _, alias := range []string{"go-dev", "go-blog"} {
+		_, err := strg.SetShortURL(context.Background(), "https://go.dev/"+alias, "user1", URLOptions{Alias: alias}, cfg)
+		require.NoError(t, err)
+	}
+	require.NoError(t, strg.MarkDeleted(context.Background(), []string{"go-dev", "go-blog"}, []string{"user1", "user1"}))
+	deleted, ok := strg.record("go-blog")
+	require.True(t, ok)
+	results, err := strg.RestoreURLs(context.Background(), []string{"go-dev"}, "user1", time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []RestoreResult{{Key: "go-dev", Status: RestoreRestored}}, results)
+	strg.CloseDB()
+
+	// несохраненное восстановление отменяется с прежним временем удаления
+	_, err = strg.RestoreURLs(context.Background(), []string{"go-blog"}, "user1", time.Now().Add(-time.Hour))
+	assert.ErrorIs(t, err, ErrUnavailable)
+	r, ok := strg.record("go-blog")
+	require.True(t, ok)
+	assert.True(t, r.deleted)
+	assert.Equal(t, deleted.deletedAt, r.deletedAt)
+
+	restored := NewFileStorager(cfg, NewKeyGenerator(cfg))
+	fURL, err := restored.RetFullURL(context.Background(), "go-dev")
+	require.NoError(t, err)
+	assert.Equal(t, "https://go.dev/go-dev", fURL)
+	_, err = restored.RetFullURL(context.Background(), "go-blog")
+	assert.ErrorIs(t, err, ErrGone)
+}
+
+func TestRestoreUserURLs(t *testing.T) {
+	cfg := &config.Config{BaseURL: "http://127.0.0.1:8080", KeyLength: 8}
+	strg := NewMemoryStorager(NewKeyGenerator(cfg))
+	_, err := strg.SetShortURL(context.Background(), "https://go.dev/", "user1", URLOptions{Alias: "go-dev"}, cfg)
+	require.NoError(t, err)
+	require.NoError(t, strg.MarkDeleted(context.Background(), []string{"go-dev"}, []string{"user1"}))
+
+	time.Sleep(10 * time.Millisecond)
+	results, err := RestoreUserURLs(context.Background(), strg, []string{"go-dev", "go-dev", "missing"}, "user1", time.Millisecond)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, RestoreWindowPassed, results[0].Status)
+	assert.Contains(t, results[0].Error, ErrRestoreWindow.Error())
+	assert.Equal(t, RestoreResult{Key: "missing", Status: RestoreNotFound, Error: "short URL not found"}, results[1])
+
+	results, err = RestoreUserURLs(context.Background(), strg, []string{"go-dev"}, "user1", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []RestoreResult{{Key: "go-dev", Status: RestoreRestored}}, results)
+}
@@ -1,83 +1,152 @@
 package storage
 
 import (
-	"database/sql"
+	"context"
 	"errors"
+	"sync"
+	"time"
 
-	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/rs/zerolog/log"
 
 	"shortURL/internal/config"
+	"shortURL/internal/storage/migrations"
 )
 
 // SQLStorage структура для создания хранилища базы данных.
 type SQLStorage struct {
-	DB *sql.DB
+	Pool   *pgxpool.Pool
+	keyGen KeyGenerator
 }
 
+// PoolStats структура со статистикой пула соединений с базой данных.
+type PoolStats struct {
+	MaxConns          int32         `json:"max_conns"`
+	TotalConns        int32         `json:"total_conns"`
+	IdleConns         int32         `json:"idle_conns"`
+	AcquiredConns     int32         `json:"acquired_conns"`
+	AcquireCount      int64         `json:"acquire_count"`
+	AcquireDuration   time.Duration `json:"acquire_duration_ns"`
+	EmptyAcquireCount int64         `json:"empty_acquire_count"`
+	CanceledAcquires  int64         `json:"canceled_acquire_count"`
+	NewConnsCount     int64         `json:"new_conns_count"`
+	LifetimeDestroyed int64         `json:"max_lifetime_destroy_count"`
+	IdleTimeDestroyed int64         `json:"max_idle_destroy_count"`
+}
+
+// keyBlockSize - количество значений счетчика ключей, резервируемых экземпляром сервиса за одно обращение к базе.
+const keyBlockSize = 100
+
+// insertURLQuery сохраняет адрес, если ключ свободен и пользователь еще не сокращал этот адрес.
+// Занятость ключа проверяется первичным ключом таблицы, повтор адреса - ограничением unique_query.
+const insertURLQuery = "INSERT INTO Short_URLs(key, user_id, value, deleted, expires_at, created_at, updated_at) VALUES($1, $2, $3, false, $4, $5, $5) ON CONFLICT DO NOTHING"
+
 // NewSQLStorager метод генерирует хранилище данных.
+// Запросы выполняются через пул соединений pgx, подготовленные выражения кэшируются каждым соединением пула.
 func NewSQLStorager(cfg *config.Config) *SQLStorage {
-	db, err := sql.Open("pgx", cfg.DatabaseDSN)
+	poolCfg, err := poolConfig(cfg)
 	if err != nil {
-		log.Fatal().Err(err).Msg("OpenDB open sql error")
+		log.Fatal().Err(err).Msg("NewSQLStorager parse DSN error")
+	}
+	// миграции применяются через database/sql отдельным соединением до открытия пула
+	db := stdlib.OpenDB(*poolCfg.ConnConfig)
+	applied, err := migrations.Up(context.Background(), db)
+	db.Close()
+	if err != nil {
+		log.Fatal().Err(err).Msg("NewSQLStorager migrations error")
+	}
+	for _, m := range applied {
+		log.Info().Msgf("migration %04d_%s applied", m.Version, m.Name)
 	}
-	err = createDB(db)
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolCfg)
 	if err != nil {
-		log.Fatal().Err(err).Msg("CreateDB create table error")
+		log.Fatal().Err(err).Msg("NewSQLStorager open pool error")
+	}
+	keyGen := NewKeyGenerator(cfg)
+	if cfg.KeyGenerator == config.KeyCounter {
+		keyGen = &sqlCounterGenerator{pool: pool}
 	}
 	return &SQLStorage{
-		DB: db,
+		Pool:   pool,
+		keyGen: keyGen,
 	}
 }
 
-// SetShortURL метод генерирует ключ для короткой ссылки, проверяет его наличие и сохраняет данные.
-// Данные передаются и возвращаются текстом в теле запроса.
-func (s *SQLStorage) SetShortURL(fURL, userID string, cfg *config.Config) (string, error) {
-	key := hashStr(fURL)
-
-	result, err := s.DB.Exec("INSERT INTO Short_URLs(key, user_id, value, deleted) VALUES($1, $2, $3, false) ON CONFLICT ON CONSTRAINT unique_query DO NOTHING", key, userID, fURL)
+// poolConfig функция формирует параметры пула соединений из конфигурации сервиса.
+func poolConfig(cfg *config.Config) (*pgxpool.Config, error) {
+	poolCfg, err := pgxpool.ParseConfig(cfg.DatabaseDSN)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	if cfg.DBMaxConns > 0 {
+		poolCfg.MaxConns = cfg.DBMaxConns
 	}
-	changes, _ := result.RowsAffected()
-	if changes == 0 {
-		var oldkey string
-		row, err := s.DB.Query("SELECT key FROM Short_URLs WHERE user_id = $1 AND value = $2", userID, fURL)
+	if cfg.DBMinConns > 0 {
+		poolCfg.MinConns = cfg.DBMinConns
+	}
+	if cfg.DBMaxConnLifetime > 0 {
+		poolCfg.MaxConnLifetime = cfg.DBMaxConnLifetime
+	}
+	if cfg.DBHealthCheckPeriod > 0 {
+		poolCfg.HealthCheckPeriod = cfg.DBHealthCheckPeriod
+	}
+	poolCfg.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeCacheStatement
+	if cfg.DBStatementCacheSize > 0 {
+		poolCfg.ConnConfig.StatementCacheCapacity = cfg.DBStatementCacheSize
+	}
+	return poolCfg, nil
+}
+
+// SetShortURL метод генерирует ключ для короткой ссылки, проверяет его наличие и сохраняет данные.
+// Данные передаются и возвращаются текстом в теле запроса.
+func (s *SQLStorage) SetShortURL(ctx context.Context, fURL, userID string, opts URLOptions, cfg *config.Config) (string, error) {
+	for attempt := 0; attempt < maxKeyAttempts; attempt++ {
+		key := opts.Alias
+		if key == "" {
+			var err error
+			key, err = generateKey(ctx, s.keyGen, fURL, attempt)
+			if err != nil {
+				return "", err
+			}
+		}
+		result, err := s.Pool.Exec(ctx, insertURLQuery, key, userID, fURL, TimePtr(opts.ExpiresAt), time.Now().UTC())
 		if err != nil {
 			return "", err
 		}
-		if err := row.Err(); err != nil {
+		if result.RowsAffected() != 0 {
+			return cfg.BaseURL + "/" + key, nil
+		}
+		oldkey, err := userKey(ctx, s.Pool.QueryRow, userID, fURL)
+		if err != nil {
 			return "", err
 		}
-		defer row.Close()
-		for row.Next() {
-			err = row.Scan(&oldkey)
-			if err != nil {
-				return "", err
-			}
-			if oldkey != "" {
-				return cfg.BaseURL + "/" + oldkey, ErrConflict
-			}
+		if oldkey != "" {
+			return cfg.BaseURL + "/" + oldkey, ErrConflict
+		}
+		if opts.Alias != "" {
+			return "", ErrAliasTaken
 		}
 	}
-	return cfg.BaseURL + "/" + key, nil
+	return "", ErrKeyCollision
 }
 
 // RetFullURL метод возвращает полный адрес по ключу от короткой ссылки.
-func (s *SQLStorage) RetFullURL(key string) (string, error) {
+func (s *SQLStorage) RetFullURL(ctx context.Context, key string) (string, error) {
 	var value string
 	var deleted bool
-	row := s.DB.QueryRow("SELECT value, deleted FROM Short_URLs WHERE key = $1", key)
-	if errors.Is(row.Err(), sql.ErrNoRows) {
+	var expiresAt *time.Time
+	err := s.Pool.QueryRow(ctx, "SELECT value, deleted, expires_at FROM Short_URLs WHERE key = $1", key).Scan(&value, &deleted, &expiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
 		return "", ErrNoContent
 	}
-	if row.Err() != nil {
-		return "", row.Err()
-	}
-	err := row.Scan(&value, &deleted)
 	if err != nil {
 		return "", err
 	}
+	if expiresAt != nil && expired(*expiresAt, time.Now()) {
+		return "", ErrExpired
+	}
 	if deleted {
 		return "", ErrGone
 	}
@@ -85,134 +154,226 @@ func (s *SQLStorage) RetFullURL(key string) (string, error) {
 	return value, nil
 }
 
-// ReturnAllURLs метод возвращает список сокращенных адресов по ID пользователя.
-func (s *SQLStorage) ReturnAllURLs(userID string, cfg *config.Config) ([]urls, error) {
+// CheckPing метод возвращает статус подключения к базе данных.
+func (s *SQLStorage) CheckPing(ctx context.Context, cfg *config.Config) error {
+	return s.Pool.Ping(ctx)
+}
 
-	var allURLs = make([]urls, 0)
-	rows, err := s.DB.Query("SELECT key, value FROM Short_URLs WHERE user_id = $1", userID)
-	if err != nil {
-		return nil, err
+// WriteMultiURL метод обрабатывает, сохраняет и возвращает batch список сокращенных адресов.
+// Адреса сохраняются одним многострочным запросом. Адреса, ранее сокращенные пользователем, возвращаются
+// с существующей короткой ссылкой, для адресов с занятым ключом вставка повторяется с новыми ключами.
+func (s *SQLStorage) WriteMultiURL(ctx context.Context, m []MultiURL, userID string, cfg *config.Config) ([]MultiURL, error) {
+	r := newBatchResult(m)
+	// индексы элементов пакета по адресу, повтор адреса в пакете получает ту же короткую ссылку
+	pending := make(map[string][]int)
+	for i, v := range m {
+		if r[i].Status != BatchInvalid {
+			pending[v.OriginURL] = append(pending[v.OriginURL], i)
+		}
 	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+	setResult := func(status string) func(key, fURL string, expiresAt *time.Time) {
+		return func(key, fURL string, expiresAt *time.Time) {
+			for n, i := range pending[fURL] {
+				r[i].Status = status
+				if n > 0 {
+					r[i].Status = BatchExists
+				}
+				r[i].ShortURL = cfg.BaseURL + "/" + key
+				r[i].ExpiresAt = expiresAt
+			}
+			delete(pending, fURL)
+		}
 	}
-	defer rows.Close()
-	for rows.Next() {
-		var nextURL urls
-		var sURL string
-
-		err = rows.Scan(&sURL, &nextURL.OriginalURL)
+	for attempt := 0; attempt < maxKeyAttempts && len(pending) > 0; attempt++ {
+		keys := make([]string, 0, len(pending))
+		values := make([]string, 0, len(pending))
+		expires := make([]*time.Time, 0, len(pending))
+		for fURL, items := range pending {
+			key, err := generateKey(ctx, s.keyGen, fURL, attempt)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+			values = append(values, fURL)
+			expires = append(expires, m[items[0]].ExpiresAt)
+		}
+		err := scanBatch(ctx, s.Pool, setResult(BatchCreated), insertBatchQuery, userID, keys, values, expires, time.Now().UTC())
+		if err != nil {
+			return nil, err
+		}
+		if len(pending) == 0 {
+			break
+		}
+		// пропущенные вставкой адреса могли быть сокращены пользователем ранее
+		values = values[:0]
+		for fURL := range pending {
+			values = append(values, fURL)
+		}
+		err = scanBatch(ctx, s.Pool, setResult(BatchExists), "SELECT key, value, expires_at FROM Short_URLs WHERE user_id = $1 AND value = ANY($2)", userID, values)
 		if err != nil {
 			return nil, err
 		}
-		nextURL.ShortURL = string(cfg.BaseURL + "/" + sURL)
-		allURLs = append(allURLs, nextURL)
 	}
-	if len(allURLs) == 0 {
-		return nil, ErrNoContent
+	if len(pending) > 0 {
+		return nil, ErrKeyCollision
 	}
-	return allURLs, nil
+	return r, nil
 }
 
-// CheckPing метод возвращает статус подключения к базе данных.
-func (s *SQLStorage) CheckPing(cfg *config.Config) error {
-	return s.DB.Ping()
-}
+// insertBatchQuery сохраняет пакет адресов пользователя и возвращает сохраненные строки.
+// Строки с занятым ключом или ранее сокращенным пользователем адресом пропускаются.
+const insertBatchQuery = `INSERT INTO Short_URLs(key, user_id, value, deleted, expires_at, created_at, updated_at)
+	SELECT key, $1, value, false, expires_at, $5, $5 FROM unnest($2::text[], $3::text[], $4::timestamptz[]) AS t(key, value, expires_at)
+	ON CONFLICT DO NOTHING
+	RETURNING key, value, expires_at`
 
-// Метод обрабатывает, сохраняет и возвращает batch список сокращенных адресов.
-func (s *SQLStorage) WriteMultiURL(m []MultiURL, userID string, cfg *config.Config) ([]MultiURL, error) {
-	r := make([]MultiURL, len(m))
-	tx, err := s.DB.Begin()
-	if err != nil {
-		return nil, err
-	}
-	stmt, err := tx.Prepare("INSERT INTO Short_URLs(key, user_id, value, deleted) VALUES($1, $2, $3, false)")
+// scanBatch функция выполняет запрос, возвращающий ключи, адреса и сроки действия ссылок, и передает каждую строку в fn.
+func scanBatch(ctx context.Context, pool *pgxpool.Pool, fn func(key, fURL string, expiresAt *time.Time), query string, args ...any) error {
+	rows, err := pool.Query(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer stmt.Close()
-	for i, v := range m {
-		Key := hashStr(v.OriginURL)
-		if _, err = stmt.Exec(Key, userID, v.OriginURL); err != nil {
-			if err = tx.Rollback(); err != nil {
-				log.Fatal().Msgf("update drivers: unable to rollback: %v", err)
-			}
-			return nil, err
+	defer rows.Close()
+	for rows.Next() {
+		var key, fURL string
+		var expiresAt *time.Time
+		if err = rows.Scan(&key, &fURL, &expiresAt); err != nil {
+			return err
 		}
-		r[i].CorrID = v.CorrID
-		r[i].ShortURL = string(cfg.BaseURL + "/" + Key)
-	}
-	if err := tx.Commit(); err != nil {
-		log.Fatal().Msgf("update drivers: unable to commit: %v", err)
-		return nil, err
+		fn(key, fURL, expiresAt)
 	}
-	return r, nil
+	return rows.Err()
 }
 
-// CloseDB метод закрывает соединение с хранилищем данных.
+// CloseDB метод закрывает соединения пула с хранилищем данных.
 func (s *SQLStorage) CloseDB() {
-	err := s.DB.Close()
-	if err != nil {
-		log.Error().Err(err).Msg("CloseDB DB closing err")
-	}
+	s.Pool.Close()
 	log.Info().Msg("db closed")
 }
 
 // MarkDeleted метод помечает на удаление адреса пользователя в хранилище.
-func (s *SQLStorage) MarkDeleted(keys []string, ids []string) {
-	stmt, err := s.DB.Prepare("UPDATE Short_URLs SET deleted=true WHERE key=$1 AND user_id=$2")
+// Адреса и их владельцы передаются массивами и обновляются одним запросом.
+func (s *SQLStorage) MarkDeleted(ctx context.Context, keys []string, ids []string) error {
+	_, err := s.Pool.Exec(ctx, `UPDATE Short_URLs SET deleted = true, deleted_at = now(), updated_at = now()
+		FROM unnest($1::text[], $2::text[]) AS t(key, user_id)
+		WHERE Short_URLs.key = t.key AND Short_URLs.user_id = t.user_id AND NOT Short_URLs.deleted`, keys, ids)
+	return err
+}
+
+// ExpireURLs метод помечает удаленными адреса с истекшим сроком действия и возвращает их количество.
+func (s *SQLStorage) ExpireURLs(ctx context.Context, now time.Time) (int, error) {
+	result, err := s.Pool.Exec(ctx, "UPDATE Short_URLs SET deleted = true, deleted_at = $1, updated_at = $1 WHERE NOT deleted AND expires_at <= $1", now)
 	if err != nil {
-		log.Error().Err(err)
-		return
-	}
-	defer stmt.Close()
-	for i, key := range keys {
-		if _, err = stmt.Exec(key, ids[i]); err != nil {
-			log.Error().Err(err).Msg("MarkDeleted DB update err")
-			return
-		}
+		return 0, err
 	}
+	return int(result.RowsAffected()), nil
 }
 
-// ReturnStats метод возвращает статистику по количеству сохраненных сокращенных URL и пользователей.
-func (s *SQLStorage) ReturnStats() (*stats, error) {
-	var urls, users int
+// SaveClicks метод сохраняет пакет переходов по коротким ссылкам.
+// Переходы по отсутствующим в таблице адресам, например удаленным до сохранения пакета, отбрасываются.
+func (s *SQLStorage) SaveClicks(ctx context.Context, clicks []Click) error {
+	keys := make([]string, len(clicks))
+	times := make([]time.Time, len(clicks))
+	referrers := make([]string, len(clicks))
+	agents := make([]string, len(clicks))
+	ips := make([]string, len(clicks))
+	for i, c := range clicks {
+		keys[i], times[i], referrers[i], agents[i], ips[i] = c.Key, c.Time, c.Referrer, c.UserAgent, c.IP
+	}
+	_, err := s.Pool.Exec(ctx, `INSERT INTO Clicks(key, clicked_at, referrer, user_agent, ip)
+		SELECT * FROM unnest($1::text[], $2::timestamptz[], $3::text[], $4::text[], $5::text[]) AS c(key, clicked_at, referrer, user_agent, ip)
+		WHERE EXISTS (SELECT 1 FROM Short_URLs WHERE Short_URLs.key = c.key)`, keys, times, referrers, agents, ips)
+	return err
+}
 
-	row := s.DB.QueryRow("SELECT count(*) FROM Short_URLs")
-	if errors.Is(row.Err(), sql.ErrNoRows) {
+// ReturnClickStats метод возвращает статистику переходов по короткой ссылке ее владельцу.
+func (s *SQLStorage) ReturnClickStats(ctx context.Context, key, userID, interval string) (*ClickStats, error) {
+	var owner string
+	err := s.Pool.QueryRow(ctx, "SELECT user_id FROM Short_URLs WHERE key = $1", key).Scan(&owner)
+	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, ErrNoContent
 	}
-	if row.Err() != nil {
-		return nil, row.Err()
-	}
-	err := row.Scan(&urls)
 	if err != nil {
 		return nil, err
 	}
-
-	row = s.DB.QueryRow("SELECT count(*) FROM Short_URLs GROUP BY user_id")
-	if errors.Is(row.Err(), sql.ErrNoRows) {
-		return nil, ErrNoContent
+	if owner != userID {
+		return nil, ErrForbidden
 	}
-	if row.Err() != nil {
-		return nil, row.Err()
+
+	stats := ClickStats{Key: key, Interval: interval, Series: make([]ClickPoint, 0)}
+	err = s.Pool.QueryRow(ctx, "SELECT count(*), count(DISTINCT ip) FROM Clicks WHERE key = $1", key).Scan(&stats.Total, &stats.Visitors)
+	if err != nil {
+		return nil, err
 	}
-	err = row.Scan(&users)
+	rows, err := s.Pool.Query(ctx, "SELECT date_trunc($2, clicked_at AT TIME ZONE 'UTC'), count(*) FROM Clicks WHERE key = $1 GROUP BY 1 ORDER BY 1", key, interval)
 	if err != nil {
 		return nil, err
 	}
-
-	stats := stats{
-		URLs:  urls,
-		Users: users,
+	defer rows.Close()
+	for rows.Next() {
+		var point ClickPoint
+		if err = rows.Scan(&point.Time, &point.Count); err != nil {
+			return nil, err
+		}
+		point.Time = point.Time.UTC()
+		stats.Series = append(stats.Series, point)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
 	}
 	return &stats, nil
 }
 
-func createDB(db *sql.DB) error {
-	_, err := db.Exec("CREATE TABLE IF NOT EXISTS Short_URLs(key text, user_id text, value text, deleted boolean, CONSTRAINT unique_query UNIQUE (user_id, value));")
-	if err != nil {
-		return err
+// poolStats метод возвращает текущую статистику пула соединений.
+func (s *SQLStorage) poolStats() *PoolStats {
+	st := s.Pool.Stat()
+	return &PoolStats{
+		MaxConns:          st.MaxConns(),
+		TotalConns:        st.TotalConns(),
+		IdleConns:         st.IdleConns(),
+		AcquiredConns:     st.AcquiredConns(),
+		AcquireCount:      st.AcquireCount(),
+		AcquireDuration:   st.AcquireDuration(),
+		EmptyAcquireCount: st.EmptyAcquireCount(),
+		CanceledAcquires:  st.CanceledAcquireCount(),
+		NewConnsCount:     st.NewConnsCount(),
+		LifetimeDestroyed: st.MaxLifetimeDestroyCount(),
+		IdleTimeDestroyed: st.MaxIdleDestroyCount(),
+	}
+}
+
+// userKey функция возвращает ключ, ранее выданный пользователю для адреса, или пустую строку.
+func userKey(ctx context.Context, queryRow func(ctx context.Context, query string, args ...any) pgx.Row, userID, fURL string) (string, error) {
+	var key string
+	err := queryRow(ctx, "SELECT key FROM Short_URLs WHERE user_id = $1 AND value = $2", userID, fURL).Scan(&key)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	return key, err
+}
+
+// sqlCounterGenerator выдает ключи по счетчику, блоки значений которого выделяет последовательность базы данных.
+// Каждый экземпляр сервиса получает собственный блок, поэтому ключи разных экземпляров не пересекаются.
+type sqlCounterGenerator struct {
+	pool *pgxpool.Pool
+	next uint64
+	last uint64
+	sync.Mutex
+}
+
+// NewKey метод возвращает следующее значение счетчика, при исчерпании блока резервирует новый.
+func (g *sqlCounterGenerator) NewKey(ctx context.Context, fURL string, attempt int) (string, error) {
+	g.Lock()
+	defer g.Unlock()
+	if g.next == g.last {
+		var block int64
+		err := g.pool.QueryRow(ctx, "SELECT nextval('short_urls_key_seq')").Scan(&block)
+		if err != nil {
+			return "", err
+		}
+		g.next = uint64(block-1) * keyBlockSize
+		g.last = uint64(block) * keyBlockSize
 	}
-	return nil
+	g.next++
+	return base62(g.next), nil
 }
@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// topOwnersLimit - количество пользователей с наибольшим числом ссылок в статистике сервиса.
+const topOwnersLimit = 10
+
+// statsDayLayout - формат дня в статистике созданных ссылок, дни считаются по UTC.
+const statsDayLayout = "2006-01-02"
+
+// DailyCount структура с количеством ссылок, созданных за день.
+type DailyCount struct {
+	Day   string `json:"day"`
+	Count int    `json:"count"`
+}
+
+// OwnerCount структура с количеством ссылок пользователя.
+type OwnerCount struct {
+	UserID string `json:"user_id"`
+	URLs   int    `json:"urls"`
+}
+
+// ParseSince функция разбирает начало окна статистики: время в формате RFC 3339
+// или длительность до текущего момента, например 168h. Пустое значение означает статистику за все время.
+func ParseSince(since string, now time.Time) (time.Time, error) {
+	if since == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, since); err == nil {
+		return t.UTC(), nil
+	}
+	d, err := time.ParseDuration(since)
+	if err != nil || d <= 0 {
+		return time.Time{}, fmt.Errorf("%w: since must be RFC 3339 time or positive duration", ErrBadRequest)
+	}
+	return now.Add(-d).UTC(), nil
+}
+
+// statsCollector собирает статистику сервиса по адресам хранилища в памяти
+// по тем же правилам, по которым ее считают запросы к базе данных.
+type statsCollector struct {
+	since  int64
+	now    int64
+	st     stats
+	owners map[string]int
+	days   map[string]int
+}
+
+func newStatsCollector(since, now time.Time) *statsCollector {
+	return &statsCollector{
+		since:  sinceNano(since),
+		now:    now.UnixNano(),
+		st:     stats{Since: TimePtr(since)},
+		owners: make(map[string]int),
+		days:   make(map[string]int),
+	}
+}
+
+// sinceNano функция переводит начало окна статистики в наносекунды. Время за пределами диапазона
+// UnixNano ограничивается его границами, чтобы окно в далеком будущем или прошлом не переполнялось.
+func sinceNano(since time.Time) int64 {
+	switch {
+	case since.IsZero():
+		return 0
+	case since.After(time.Unix(0, math.MaxInt64)):
+		return math.MaxInt64
+	case since.Before(time.Unix(0, 1)):
+		return 1
+	}
+	return since.UnixNano()
+}
+
+// add метод учитывает адрес. При заданном окне адреса с неизвестным временем создания не учитываются.
+func (c *statsCollector) add(key string, r urlRecord) {
+	if c.since != 0 && r.created < c.since {
+		return
+	}
+	c.st.URLs++
+	switch {
+	case r.deleted:
+		c.st.Deleted++
+	case r.expires != 0 && r.expires <= c.now:
+		c.st.Expired++
+	default:
+		c.st.Active++
+	}
+	c.st.DataSize += int64(len(key) + len(r.owner.id) + len(r.value))
+	c.owners[r.owner.id]++
+	if r.created != 0 {
+		c.days[time.Unix(0, r.created).UTC().Format(statsDayLayout)]++
+	}
+}
+
+// stats метод возвращает собранную статистику. Дни упорядочены по возрастанию,
+// пользователи - по убыванию числа ссылок, при равенстве - по идентификатору.
+func (c *statsCollector) stats() *stats {
+	st := c.st
+	st.Users = len(c.owners)
+	st.CreatedPerDay = make([]DailyCount, 0, len(c.days))
+	for day, n := range c.days {
+		st.CreatedPerDay = append(st.CreatedPerDay, DailyCount{Day: day, Count: n})
+	}
+	sort.Slice(st.CreatedPerDay, func(i, j int) bool { return st.CreatedPerDay[i].Day < st.CreatedPerDay[j].Day })
+	st.TopOwners = make([]OwnerCount, 0, len(c.owners))
+	for userID, n := range c.owners {
+		st.TopOwners = append(st.TopOwners, OwnerCount{UserID: userID, URLs: n})
+	}
+	sort.Slice(st.TopOwners, func(i, j int) bool {
+		if st.TopOwners[i].URLs != st.TopOwners[j].URLs {
+			return st.TopOwners[i].URLs > st.TopOwners[j].URLs
+		}
+		return st.TopOwners[i].UserID < st.TopOwners[j].UserID
+	})
+	if len(st.TopOwners) > topOwnersLimit {
+		st.TopOwners = st.TopOwners[:topOwnersLimit]
+	}
+	return &st
+}
+
+// ReturnStats метод возвращает статистику по адресам, созданным не ранее since, нулевое since - по всем адресам.
+func (s *MemoryStorage) ReturnStats(ctx context.Context, since time.Time) (*stats, error) {
+	c := newStatsCollector(since, time.Now())
+	for i := range s.urls {
+		sh := &s.urls[i]
+		sh.RLock()
+		for key, r := range sh.urls {
+			c.add(key, r)
+		}
+		sh.RUnlock()
+	}
+	return c.stats(), nil
+}
+
+// statsWindow - условие отбора адресов, созданных не ранее начала окна статистики, NULL означает все адреса.
+const statsWindow = "($1::timestamptz IS NULL OR created_at >= $1)"
+
+// ReturnStats метод возвращает статистику по адресам, созданным не ранее since, нулевое since - по всем адресам,
+// а также статистику пула соединений с базой данных. Пользователи с равным числом ссылок упорядочиваются
+// побайтно, как и в остальных хранилищах.
+func (s *SQLStorage) ReturnStats(ctx context.Context, since time.Time) (*stats, error) {
+	window := TimePtr(since)
+	st := stats{Since: window, Pool: s.poolStats()}
+	err := s.Pool.QueryRow(ctx, `SELECT count(*), count(DISTINCT user_id),
+			count(*) FILTER (WHERE NOT deleted AND (expires_at IS NULL OR expires_at > $2)),
+			count(*) FILTER (WHERE NOT deleted AND expires_at <= $2),
+			count(*) FILTER (WHERE deleted),
+			coalesce(sum(octet_length(key) + octet_length(user_id) + octet_length(value)), 0)
+		FROM Short_URLs WHERE `+statsWindow, window, time.Now()).
+		Scan(&st.URLs, &st.Users, &st.Active, &st.Expired, &st.Deleted, &st.DataSize)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Pool.Query(ctx, `SELECT to_char(created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS day, count(*)
+		FROM Short_URLs WHERE created_at IS NOT NULL AND `+statsWindow+` GROUP BY day ORDER BY day`, window)
+	if err != nil {
+		return nil, err
+	}
+	st.CreatedPerDay, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (DailyCount, error) {
+		var d DailyCount
+		err := row.Scan(&d.Day, &d.Count)
+		return d, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err = s.Pool.Query(ctx, `SELECT user_id, count(*) AS n FROM Short_URLs WHERE `+statsWindow+`
+		GROUP BY user_id ORDER BY n DESC, user_id COLLATE "C" LIMIT $2`, window, topOwnersLimit)
+	if err != nil {
+		return nil, err
+	}
+	st.TopOwners, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (OwnerCount, error) {
+		var o OwnerCount
+		err := row.Scan(&o.UserID, &o.URLs)
+		return o, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"shortURL/internal/config"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		since string
+		want  time.Time
+		err   bool
+	}{
+		{since: "", want: time.Time{}},
+		{since: "24h", want: now.Add(-24 * time.Hour)},
+		{since: "2024-05-01T03:00:00+03:00", want: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{since: "0s", err: true},
+		{since: "-1h", err: true},
+		{since: "yesterday", err: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSince(tt.since, now)
+		if tt.err {
+			assert.True(t, errors.Is(err, ErrBadRequest), tt.since)
+			continue
+		}
+		require.NoError(t, err, tt.since)
+		assert.True(t, tt.want.Equal(got), tt.since)
+	}
+}
+
+func TestFileStorageStatsCreatedAt(t *testing.T) {
+	cfg := &config.Config{BaseURL: "http://127.0.0.1:8080", FileStoragePath: filepath.Join(t.TempDir(), "storage.json"), KeyLength: 8}
+	strg := NewFileStorager(cfg, NewKeyGenerator(cfg))
+	since := time.Now()
+	for _, fURL := range []string{"https://go.dev/", "https://go.dev/doc/"} {
+		_, err := strg.SetShortURL(context.Background(), fURL, "user1", URLOptions{}, cfg)
+		require.NoError(t, err)
+	}
+	want, err := strg.ReturnStats(context.Background(), since)
+	require.NoError(t, err)
+	require.Equal(t, 2, want.URLs)
+	require.Len(t, want.CreatedPerDay, 1)
+	strg.CloseDB()
+
+	// время создания восстанавливается из файла и сохраняется при сжатии
+	restored := NewFileStorager(cfg, NewKeyGenerator(cfg))
+	got, err := restored.ReturnStats(context.Background(), since)
+	require.NoError(t, err)
+	assert.Equal(t, want.URLs, got.URLs)
+	assert.Equal(t, want.CreatedPerDay, got.CreatedPerDay)
+	require.NoError(t, restored.compact())
+	restored.CloseDB()
+	restored = NewFileStorager(cfg, NewKeyGenerator(cfg))
+	got, err = restored.ReturnStats(context.Background(), since)
+	require.NoError(t, err)
+	assert.Equal(t, want.URLs, got.URLs)
+	assert.Equal(t, want.CreatedPerDay, got.CreatedPerDay)
+	assert.Equal(t, []OwnerCount{{UserID: "user1", URLs: 2}}, got.TopOwners)
+}
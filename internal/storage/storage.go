@@ -1,60 +1,118 @@
 package storage
 
 import (
-	"crypto/md5"
-	"fmt"
+	"context"
+	"time"
 
 	"shortURL/internal/config"
 )
 
 // Storager - интерфейс для работы с хранилищем.
 type Storager interface {
-	SetShortURL(fURL string, userID string, cfg *config.Config) (string, error)
-	WriteMultiURL(bytes []MultiURL, UserID string, P *config.Config) ([]MultiURL, error)
-	RetFullURL(key string) (string, error)
-	ReturnAllURLs(UserID string, P *config.Config) ([]urls, error)
-	ReturnStats() (*stats, error)
-	CheckPing(P *config.Config) error
+	SetShortURL(ctx context.Context, fURL string, userID string, opts URLOptions, cfg *config.Config) (string, error)
+	WriteMultiURL(ctx context.Context, bytes []MultiURL, UserID string, P *config.Config) ([]MultiURL, error)
+	RetFullURL(ctx context.Context, key string) (string, error)
+	ReturnAllURLs(ctx context.Context, UserID string, opts ListOptions, P *config.Config) (*URLPage, error)
+	ReturnStats(ctx context.Context, since time.Time) (*stats, error)
+	CheckPing(ctx context.Context, P *config.Config) error
 	CloseDB()
-	MarkDeleted([]string, []string)
+	MarkDeleted(ctx context.Context, keys []string, ids []string) error
+	ExpireURLs(ctx context.Context, now time.Time) (int, error)
+	SaveClicks(ctx context.Context, clicks []Click) error
+	ReturnClickStats(ctx context.Context, key, userID, interval string) (*ClickStats, error)
+	ExportUserURLs(ctx context.Context, userID string, after string, limit int) ([]Record, error)
+	PurgeDeleted(ctx context.Context, before time.Time) (int, error)
+	EraseUser(ctx context.Context, userID string) ([]string, error)
+	RestoreURLs(ctx context.Context, keys []string, userID string, since time.Time) ([]RestoreResult, error)
+	UpdateURL(ctx context.Context, key, userID, fURL string) (*URLVersion, error)
+	URLHistory(ctx context.Context, key, userID string) ([]URLVersion, error)
+	ReturnUsage(ctx context.Context, userID string, since time.Time) (*Usage, error)
+	ReturnUserKeys(ctx context.Context, userID string, fURLs []string) (map[string]string, error)
 }
 
 // NewStorage метод "Фабрика" для создания хранилища в соответствии с конфигурацией сервиса.
 func NewStorage(cfg *config.Config) Storager {
+	var strg RecordStorager
 	switch cfg.SavePlace {
 	case config.SaveFile:
-		return NewFileStorager(cfg)
+		strg = NewFileStorager(cfg, NewKeyGenerator(cfg))
 	case config.SaveSQL:
-		return NewSQLStorager(cfg)
+		strg = NewSQLStorager(cfg)
 	default:
-		return NewMemoryStorager()
+		strg = NewMemoryStorager(NewKeyGenerator(cfg))
 	}
+	var wrapped Storager = strg
+	if cfg.CacheSize > 0 {
+		wrapped = WithCache(strg, cfg.CacheSize, cfg.CacheTTL, cfg.CacheNegativeTTL, cfg.StorageReadTimeout)
+	}
+	if cfg.QuotaMaxURLs > 0 || cfg.QuotaMaxCreated > 0 {
+		wrapped = WithQuotas(wrapped, cfg.QuotaMaxURLs, cfg.QuotaMaxCreated, cfg.QuotaWindow)
+	}
+	return WithTimeouts(wrapped, cfg.StorageReadTimeout, cfg.StorageWriteTimeout)
 }
 
-type storageStruct struct {
-	UserID  string `json:"ID"`
-	Key     string `json:"key"`
-	Value   string `json:"value"`
-	Deleted bool   `json:"deleted"`
+// URLOptions структура с необязательными параметрами создаваемой короткой ссылки.
+// Нулевое значение ExpiresAt означает бессрочную ссылку.
+type URLOptions struct {
+	Alias     string
+	ExpiresAt time.Time
 }
 
-func hashStr(s string) string {
-	return fmt.Sprintf("%x", md5.Sum([]byte(s)))
+type storageStruct struct {
+	UserID    string     `json:"ID"`
+	Key       string     `json:"key"`
+	Value     string     `json:"value"`
+	Deleted   bool       `json:"deleted"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
 }
 
 type urls struct {
-	ShortURL    string `json:"short_url"`
-	OriginalURL string `json:"original_url"`
+	ShortURL    string     `json:"short_url"`
+	OriginalURL string     `json:"original_url"`
+	Deleted     bool       `json:"deleted,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
 }
 
 // MultiURL структура для обработки batch запросов в формате JSON.
+// Срок действия адреса задается полем expires_at или временем жизни ttl в секундах.
+// В ответе каждый элемент получает статус: created, exists с ранее выданной короткой ссылкой или invalid с описанием ошибки.
 type MultiURL struct {
-	CorrID    string `json:"correlation_id"`
-	OriginURL string `json:"original_url,omitempty"`
-	ShortURL  string `json:"short_url,omitempty"`
+	CorrID    string     `json:"correlation_id"`
+	OriginURL string     `json:"original_url,omitempty"`
+	ShortURL  string     `json:"short_url,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	TTL       int64      `json:"ttl,omitempty"`
+	Status    string     `json:"status,omitempty"`
+	Error     string     `json:"error,omitempty"`
 }
 
+// stats структура со статистикой сервиса. Expired - просроченные ссылки, еще не помеченные удаленными,
+// DataSize - суммарная длина ключей, идентификаторов пользователей и исходных адресов в байтах,
+// одинаково вычисляемая всеми хранилищами.
 type stats struct {
-	URLs  int `json:"urls"`
-	Users int `json:"users"`
+	URLs          int          `json:"urls"`
+	Users         int          `json:"users"`
+	Active        int          `json:"active_urls"`
+	Expired       int          `json:"expired_urls"`
+	Deleted       int          `json:"deleted_urls"`
+	CreatedPerDay []DailyCount `json:"created_per_day"`
+	TopOwners     []OwnerCount `json:"top_owners"`
+	DataSize      int64        `json:"storage_bytes"`
+	Since         *time.Time   `json:"since,omitempty"`
+	Pool          *PoolStats   `json:"pool,omitempty"`
+	Cache         *CacheStats  `json:"cache,omitempty"`
+}
+
+// batchOptions функция возвращает параметры создаваемой ссылки из элемента batch запроса.
+func batchOptions(m MultiURL) URLOptions {
+	var opts URLOptions
+	if m.ExpiresAt != nil {
+		opts.ExpiresAt = *m.ExpiresAt
+	}
+	return opts
 }
@@ -0,0 +1,754 @@
+// Модуль содержит общий набор проверок, которым должна соответствовать любая реализация storage.Storager.
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"shortURL/internal/config"
+	"shortURL/internal/storage"
+)
+
+// Factory - функция, создающая проверяемое хранилище. Хранилище закрывается по завершении теста.
+type Factory func(t *testing.T) storage.Storager
+
+// Run функция проверяет соответствие хранилища общему поведению хранилищ сервиса.
+// Каждая проверка получает новое хранилище, но использует уникальные адреса и пользователей,
+// поэтому набор можно запускать и на общей базе данных.
+func Run(t *testing.T, cfg *config.Config, newStorage Factory) {
+	checks := []struct {
+		name string
+		fn   func(t *testing.T, s *suite)
+	}{
+		{name: "SetShortURL", fn: testSetShortURL},
+		{name: "Alias", fn: testAlias},
+		{name: "MissingKey", fn: testMissingKey},
+		{name: "Batch", fn: testBatch},
+		{name: "Deletion", fn: testDeletion},
+		{name: "Expiry", fn: testExpiry},
+		{name: "UserURLs", fn: testUserURLs},
+		{name: "ExportUserURLs", fn: testExportUserURLs},
+		{name: "Stats", fn: testStats},
+		{name: "Clicks", fn: testClicks},
+		{name: "Restore", fn: testRestore},
+		{name: "Versions", fn: testVersions},
+		{name: "Timestamps", fn: testTimestamps},
+		{name: "Usage", fn: testUsage},
+		{name: "Purge", fn: testPurge},
+		{name: "Erase", fn: testErase},
+		{name: "Concurrency", fn: testConcurrency},
+	}
+	for _, check := range checks {
+		t.Run(check.name, func(t *testing.T) {
+			strg := newStorage(t)
+			t.Cleanup(strg.CloseDB)
+			check.fn(t, &suite{strg: strg, cfg: cfg, suffix: strconv.FormatInt(time.Now().UnixNano(), 36)})
+		})
+	}
+}
+
+// suite хранит проверяемое хранилище и суффикс, делающий адреса и пользователей проверки уникальными.
+type suite struct {
+	strg   storage.Storager
+	cfg    *config.Config
+	suffix string
+}
+
+func (s *suite) url(name string) string {
+	return "https://go.dev/" + name + "/" + s.suffix
+}
+
+func (s *suite) user(name string) string {
+	return name + "-" + s.suffix
+}
+
+func (s *suite) alias(name string) string {
+	return name + "-" + s.suffix
+}
+
+func (s *suite) key(shortURL string) string {
+	return strings.TrimPrefix(shortURL, s.cfg.BaseURL+"/")
+}
+
+func (s *suite) set(t *testing.T, fURL, userID string, opts storage.URLOptions) string {
+	shortURL, err := s.strg.SetShortURL(context.Background(), fURL, userID, opts, s.cfg)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(shortURL, s.cfg.BaseURL+"/"), shortURL)
+	return shortURL
+}
+
+func testSetShortURL(t *testing.T, s *suite) {
+	ctx := context.Background()
+	shortURL := s.set(t, s.url("set"), s.user("u1"), storage.URLOptions{})
+	fURL, err := s.strg.RetFullURL(ctx, s.key(shortURL))
+	require.NoError(t, err)
+	assert.Equal(t, s.url("set"), fURL)
+
+	// повторное сокращение адреса тем же пользователем возвращает прежнюю ссылку
+	again, err := s.strg.SetShortURL(ctx, s.url("set"), s.user("u1"), storage.URLOptions{}, s.cfg)
+	assert.ErrorIs(t, err, storage.ErrConflict)
+	assert.Equal(t, shortURL, again)
+
+	// другой пользователь получает собственную ссылку на тот же адрес
+	other := s.set(t, s.url("set"), s.user("u2"), storage.URLOptions{})
+	assert.NotEqual(t, shortURL, other)
+	fURL, err = s.strg.RetFullURL(ctx, s.key(other))
+	require.NoError(t, err)
+	assert.Equal(t, s.url("set"), fURL)
+}
+
+func testAlias(t *testing.T, s *suite) {
+	ctx := context.Background()
+	alias := s.alias("alias")
+	shortURL := s.set(t, s.url("alias"), s.user("u1"), storage.URLOptions{Alias: alias})
+	assert.Equal(t, s.cfg.BaseURL+"/"+alias, shortURL)
+
+	for _, userID := range []string{s.user("u1"), s.user("u2")} {
+		taken, err := s.strg.SetShortURL(ctx, s.url("alias-other"), userID, storage.URLOptions{Alias: alias}, s.cfg)
+		assert.ErrorIs(t, err, storage.ErrAliasTaken)
+		assert.Empty(t, taken)
+	}
+	// адрес, не сохраненный из-за занятого псевдонима, можно сократить без псевдонима
+	s.set(t, s.url("alias-other"), s.user("u1"), storage.URLOptions{})
+}
+
+func testMissingKey(t *testing.T, s *suite) {
+	fURL, err := s.strg.RetFullURL(context.Background(), s.alias("missing"))
+	assert.ErrorIs(t, err, storage.ErrNoContent)
+	assert.Empty(t, fURL)
+}
+
+func testBatch(t *testing.T, s *suite) {
+	ctx := context.Background()
+	existing := s.set(t, s.url("batch-existing"), s.user("u1"), storage.URLOptions{})
+	batch := []storage.MultiURL{
+		{CorrID: "1", OriginURL: s.url("batch-a")},
+		{CorrID: "2", OriginURL: s.url("batch-b")},
+		{CorrID: "3", OriginURL: s.url("batch-a")},
+		{CorrID: "4", OriginURL: ""},
+		{CorrID: "5", OriginURL: s.url("batch-existing")},
+	}
+	r, err := s.strg.WriteMultiURL(ctx, batch, s.user("u1"), s.cfg)
+	require.NoError(t, err)
+	require.Len(t, r, len(batch))
+	for i, status := range []string{storage.BatchCreated, storage.BatchCreated, storage.BatchExists, storage.BatchInvalid, storage.BatchExists} {
+		assert.Equal(t, batch[i].CorrID, r[i].CorrID)
+		assert.Equal(t, status, r[i].Status, r[i].CorrID)
+	}
+	assert.Equal(t, r[0].ShortURL, r[2].ShortURL)
+	assert.NotEqual(t, r[0].ShortURL, r[1].ShortURL)
+	assert.Empty(t, r[3].ShortURL)
+	assert.NotEmpty(t, r[3].Error)
+	assert.Equal(t, existing, r[4].ShortURL)
+
+	fURL, err := s.strg.RetFullURL(ctx, s.key(r[1].ShortURL))
+	require.NoError(t, err)
+	assert.Equal(t, s.url("batch-b"), fURL)
+
+	// адреса пакета считаются сокращенными пользователем
+	again, err := s.strg.SetShortURL(ctx, s.url("batch-a"), s.user("u1"), storage.URLOptions{}, s.cfg)
+	assert.ErrorIs(t, err, storage.ErrConflict)
+	assert.Equal(t, r[0].ShortURL, again)
+}
+
+func testDeletion(t *testing.T, s *suite) {
+	ctx := context.Background()
+	own := s.key(s.set(t, s.url("delete-own"), s.user("u1"), storage.URLOptions{}))
+	foreign := s.key(s.set(t, s.url("delete-foreign"), s.user("u2"), storage.URLOptions{}))
+
+	// удаление чужого и несуществующего адреса игнорируется
+	err := s.strg.MarkDeleted(ctx, []string{own, foreign, s.alias("missing")}, []string{s.user("u1"), s.user("u1"), s.user("u1")})
+	require.NoError(t, err)
+	fURL, err := s.strg.RetFullURL(ctx, own)
+	assert.ErrorIs(t, err, storage.ErrGone)
+	assert.Empty(t, fURL)
+	fURL, err = s.strg.RetFullURL(ctx, foreign)
+	require.NoError(t, err)
+	assert.Equal(t, s.url("delete-foreign"), fURL)
+
+	// повторное удаление не является ошибкой
+	require.NoError(t, s.strg.MarkDeleted(ctx, []string{own}, []string{s.user("u1")}))
+
+	// удаленный адрес не восстанавливается повторным сохранением
+	shortURL, err := s.strg.SetShortURL(ctx, s.url("delete-own"), s.user("u1"), storage.URLOptions{}, s.cfg)
+	assert.ErrorIs(t, err, storage.ErrConflict)
+	assert.Equal(t, own, s.key(shortURL))
+	_, err = s.strg.RetFullURL(ctx, own)
+	assert.ErrorIs(t, err, storage.ErrGone)
+}
+
+func testExpiry(t *testing.T, s *suite) {
+	ctx := context.Background()
+	key := s.key(s.set(t, s.url("expiry"), s.user("u1"), storage.URLOptions{ExpiresAt: time.Now().Add(200 * time.Millisecond)}))
+	permanent := s.key(s.set(t, s.url("permanent"), s.user("u1"), storage.URLOptions{}))
+	_, err := s.strg.RetFullURL(ctx, key)
+	require.NoError(t, err)
+
+	time.Sleep(250 * time.Millisecond)
+	fURL, err := s.strg.RetFullURL(ctx, key)
+	assert.ErrorIs(t, err, storage.ErrExpired)
+	assert.Empty(t, fURL)
+
+	n, err := s.strg.ExpireURLs(ctx, time.Now())
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, n, 1)
+	_, err = s.strg.RetFullURL(ctx, key)
+	assert.ErrorIs(t, err, storage.ErrExpired)
+	_, err = s.strg.RetFullURL(ctx, permanent)
+	assert.NoError(t, err)
+
+	// просроченный адрес не восстанавливается
+	results, err := s.strg.RestoreURLs(ctx, []string{key}, s.user("u1"), time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []storage.RestoreResult{{Key: key, Status: storage.RestoreExpired}}, results)
+	_, err = s.strg.RetFullURL(ctx, key)
+	assert.ErrorIs(t, err, storage.ErrExpired)
+}
+
+func testUserURLs(t *testing.T, s *suite) {
+	ctx := context.Background()
+	_, err := s.strg.ReturnAllURLs(ctx, s.user("nobody"), storage.ListOptions{}, s.cfg)
+	assert.ErrorIs(t, err, storage.ErrNoContent)
+
+	links := make([]string, 0, 5)
+	for i := 1; i <= 5; i++ {
+		links = append(links, s.set(t, s.url("list-"+strconv.Itoa(i)), s.user("u1"), storage.URLOptions{}))
+		// время создания различается и в хранилищах с точностью до микросекунд
+		time.Sleep(2 * time.Millisecond)
+	}
+	s.set(t, s.url("list-other"), s.user("u2"), storage.URLOptions{})
+	require.NoError(t, s.strg.MarkDeleted(ctx, []string{s.key(links[1])}, []string{s.user("u1")}))
+
+	list := func(opts storage.ListOptions) ([]string, string) {
+		t.Helper()
+		page, err := s.strg.ReturnAllURLs(ctx, s.user("u1"), opts, s.cfg)
+		require.NoError(t, err)
+		got := make([]string, 0, len(page.URLs))
+		for _, u := range page.URLs {
+			assert.Equal(t, u.ShortURL == links[1], u.Deleted, u.ShortURL)
+			assert.Equal(t, s.url("list-"+strconv.Itoa(indexOf(links, u.ShortURL)+1)), u.OriginalURL)
+			got = append(got, u.ShortURL)
+		}
+		return got, page.NextCursor
+	}
+
+	// по умолчанию сначала новые адреса, удаленные адреса не возвращаются
+	got, cursor := list(storage.ListOptions{Order: storage.OrderNewest})
+	assert.Equal(t, []string{links[4], links[3], links[2], links[0]}, got)
+	assert.Empty(t, cursor)
+	got, _ = list(storage.ListOptions{Order: storage.OrderOldest, IncludeDeleted: true})
+	assert.Equal(t, links, got)
+
+	// страницы следуют друг за другом без пропусков и повторов в обоих порядках
+	for _, order := range []string{storage.OrderOldest, storage.OrderNewest} {
+		all, _ := list(storage.ListOptions{Order: order, IncludeDeleted: true})
+		paged := make([]string, 0, len(all))
+		opts := storage.ListOptions{Order: order, Limit: 2, IncludeDeleted: true}
+		for {
+			got, cursor = list(opts)
+			assert.LessOrEqual(t, len(got), 2)
+			paged = append(paged, got...)
+			if cursor == "" {
+				break
+			}
+			opts.Cursor = cursor
+		}
+		assert.Equal(t, all, paged, order)
+	}
+
+	// фильтр по подстроке исходного адреса учитывает регистр
+	got, _ = list(storage.ListOptions{Order: storage.OrderNewest, Contains: "/list-3/"})
+	assert.Equal(t, []string{links[2]}, got)
+	_, err = s.strg.ReturnAllURLs(ctx, s.user("u1"), storage.ListOptions{Order: storage.OrderNewest, Contains: "/LIST-3/"}, s.cfg)
+	assert.ErrorIs(t, err, storage.ErrNoContent)
+	_, err = s.strg.ReturnAllURLs(ctx, s.user("u1"), storage.ListOptions{Order: storage.OrderNewest, Contains: "/list-2/"}, s.cfg)
+	assert.ErrorIs(t, err, storage.ErrNoContent)
+
+	_, err = s.strg.ReturnAllURLs(ctx, s.user("u1"), storage.ListOptions{Order: storage.OrderNewest, Cursor: "not a cursor"}, s.cfg)
+	assert.ErrorIs(t, err, storage.ErrBadRequest)
+}
+
+func indexOf(links []string, link string) int {
+	for i, l := range links {
+		if l == link {
+			return i
+		}
+	}
+	return -1
+}
+
+func testExportUserURLs(t *testing.T, s *suite) {
+	ctx := context.Background()
+	want := make([]string, 0, 5)
+	for i := 0; i < 5; i++ {
+		want = append(want, s.key(s.set(t, s.url("export-"+strconv.Itoa(i)), s.user("u1"), storage.URLOptions{})))
+	}
+	s.set(t, s.url("export-other"), s.user("u2"), storage.URLOptions{})
+	require.NoError(t, s.strg.MarkDeleted(ctx, want[:1], []string{s.user("u1")}))
+
+	got := make([]string, 0, 5)
+	deleted := 0
+	after := ""
+	for {
+		recs, err := s.strg.ExportUserURLs(ctx, s.user("u1"), after, 2)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(recs), 2)
+		if len(recs) == 0 {
+			break
+		}
+		for _, rec := range recs {
+			assert.Greater(t, rec.Key, after)
+			assert.Equal(t, s.user("u1"), rec.UserID)
+			if rec.Deleted {
+				deleted++
+			}
+			after = rec.Key
+			got = append(got, rec.Key)
+		}
+	}
+	assert.ElementsMatch(t, want, got)
+	assert.Equal(t, 1, deleted)
+}
+
+func testStats(t *testing.T, s *suite) {
+	ctx := context.Background()
+	before, err := s.strg.ReturnStats(ctx, time.Time{})
+	require.NoError(t, err)
+	since := time.Now()
+	created := []struct {
+		fURL, userID string
+		opts         storage.URLOptions
+	}{
+		{fURL: s.url("stats-1"), userID: s.user("u1")},
+		{fURL: s.url("stats-2"), userID: s.user("u1")},
+		{fURL: s.url("stats-3"), userID: s.user("u1")},
+		{fURL: s.url("stats-1"), userID: s.user("u2")},
+		{fURL: s.url("stats-expiring"), userID: s.user("u2"), opts: storage.URLOptions{ExpiresAt: time.Now().Add(200 * time.Millisecond)}},
+	}
+	var size int64
+	keys := make([]string, 0, len(created))
+	for _, c := range created {
+		key := s.key(s.set(t, c.fURL, c.userID, c.opts))
+		keys = append(keys, key)
+		size += int64(len(key) + len(c.userID) + len(c.fURL))
+	}
+	require.NoError(t, s.strg.MarkDeleted(ctx, keys[2:3], []string{s.user("u1")}))
+	time.Sleep(250 * time.Millisecond)
+
+	after, err := s.strg.ReturnStats(ctx, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, 5, after.URLs-before.URLs)
+	assert.Equal(t, 2, after.Users-before.Users)
+	assert.Nil(t, after.Since)
+
+	// окно статистики содержит только адреса проверки, поэтому значения одинаковы во всех хранилищах
+	st, err := s.strg.ReturnStats(ctx, since)
+	require.NoError(t, err)
+	require.NotNil(t, st.Since)
+	assert.Equal(t, 5, st.URLs)
+	assert.Equal(t, 2, st.Users)
+	assert.Equal(t, 3, st.Active)
+	assert.Equal(t, 1, st.Expired)
+	assert.Equal(t, 1, st.Deleted)
+	assert.Equal(t, size, st.DataSize)
+	assert.Equal(t, []storage.OwnerCount{{UserID: s.user("u1"), URLs: 3}, {UserID: s.user("u2"), URLs: 2}}, st.TopOwners)
+	total := 0
+	for _, d := range st.CreatedPerDay {
+		_, err = time.Parse("2006-01-02", d.Day)
+		assert.NoError(t, err)
+		total += d.Count
+	}
+	assert.Equal(t, 5, total)
+
+	st, err = s.strg.ReturnStats(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, st.URLs)
+	assert.Empty(t, st.TopOwners)
+	assert.NotNil(t, st.CreatedPerDay)
+}
+
+func testClicks(t *testing.T, s *suite) {
+	ctx := context.Background()
+	key := s.key(s.set(t, s.url("clicks"), s.user("u1"), storage.URLOptions{}))
+	now := time.Now().UTC()
+	clicks := []storage.Click{
+		{Key: key, Time: now, IP: "192.0.2.1"},
+		{Key: key, Time: now, IP: "192.0.2.1"},
+		{Key: key, Time: now, IP: "192.0.2.2"},
+	}
+	require.NoError(t, s.strg.SaveClicks(ctx, clicks))
+
+	st, err := s.strg.ReturnClickStats(ctx, key, s.user("u1"), storage.IntervalDay)
+	require.NoError(t, err)
+	assert.Equal(t, 3, st.Total)
+	assert.Equal(t, 2, st.Visitors)
+	_, err = s.strg.ReturnClickStats(ctx, key, s.user("u2"), storage.IntervalDay)
+	assert.ErrorIs(t, err, storage.ErrForbidden)
+	_, err = s.strg.ReturnClickStats(ctx, s.alias("missing"), s.user("u1"), storage.IntervalDay)
+	assert.ErrorIs(t, err, storage.ErrNoContent)
+
+	// переходы по отсутствующему адресу не сохраняются и не достаются адресу, созданному позже с тем же ключом
+	late := s.alias("late-clicks")
+	require.NoError(t, s.strg.SaveClicks(ctx, []storage.Click{{Key: late, Time: now, IP: "192.0.2.3"}}))
+	s.set(t, s.url("late-clicks"), s.user("u1"), storage.URLOptions{Alias: late})
+	st, err = s.strg.ReturnClickStats(ctx, late, s.user("u1"), storage.IntervalDay)
+	require.NoError(t, err)
+	assert.Zero(t, st.Total)
+}
+
+func testRestore(t *testing.T, s *suite) {
+	ctx := context.Background()
+	key := s.key(s.set(t, s.url("restore"), s.user("u1"), storage.URLOptions{}))
+	active := s.key(s.set(t, s.url("restore-active"), s.user("u1"), storage.URLOptions{}))
+	require.NoError(t, s.strg.MarkDeleted(ctx, []string{key}, []string{s.user("u1")}))
+	since := time.Now().Add(-time.Hour)
+
+	// чужой адрес не восстанавливается и не отличается от отсутствующего
+	results, err := s.strg.RestoreURLs(ctx, []string{key}, s.user("u2"), since)
+	require.NoError(t, err)
+	assert.Equal(t, []storage.RestoreResult{{Key: key, Status: storage.RestoreNotFound}}, results)
+	_, err = s.strg.RetFullURL(ctx, key)
+	assert.ErrorIs(t, err, storage.ErrGone)
+
+	// адрес, удаленный раньше начала окна восстановления, не восстанавливается
+	results, err = s.strg.RestoreURLs(ctx, []string{key}, s.user("u1"), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []storage.RestoreResult{{Key: key, Status: storage.RestoreWindowPassed}}, results)
+
+	missing := s.alias("missing")
+	results, err = s.strg.RestoreURLs(ctx, []string{key, active, missing}, s.user("u1"), since)
+	require.NoError(t, err)
+	assert.Equal(t, []storage.RestoreResult{
+		{Key: key, Status: storage.RestoreRestored},
+		{Key: active, Status: storage.RestoreNotDeleted},
+		{Key: missing, Status: storage.RestoreNotFound},
+	}, results)
+	fURL, err := s.strg.RetFullURL(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, s.url("restore"), fURL)
+
+	// восстановленный адрес снова попадает в список адресов пользователя и может быть удален
+	page, err := s.strg.ReturnAllURLs(ctx, s.user("u1"), storage.ListOptions{}, s.cfg)
+	require.NoError(t, err)
+	assert.Len(t, page.URLs, 2)
+	results, err = s.strg.RestoreURLs(ctx, []string{key}, s.user("u1"), since)
+	require.NoError(t, err)
+	assert.Equal(t, []storage.RestoreResult{{Key: key, Status: storage.RestoreNotDeleted}}, results)
+	require.NoError(t, s.strg.MarkDeleted(ctx, []string{key}, []string{s.user("u1")}))
+	_, err = s.strg.RetFullURL(ctx, key)
+	assert.ErrorIs(t, err, storage.ErrGone)
+}
+
+func testVersions(t *testing.T, s *suite) {
+	ctx := context.Background()
+	key := s.key(s.set(t, s.url("edit-1"), s.user("u1"), storage.URLOptions{}))
+	s.set(t, s.url("edit-taken"), s.user("u1"), storage.URLOptions{})
+	history, err := s.strg.URLHistory(ctx, key, s.user("u1"))
+	require.NoError(t, err)
+	assert.Equal(t, []storage.URLVersion{{Version: 1, URL: s.url("edit-1")}}, history)
+
+	// изменять ссылку и просматривать ее историю может только владелец
+	_, err = s.strg.UpdateURL(ctx, key, s.user("u2"), s.url("edit-2"))
+	assert.ErrorIs(t, err, storage.ErrForbidden)
+	_, err = s.strg.URLHistory(ctx, key, s.user("u2"))
+	assert.ErrorIs(t, err, storage.ErrForbidden)
+	_, err = s.strg.UpdateURL(ctx, s.alias("missing"), s.user("u1"), s.url("edit-2"))
+	assert.ErrorIs(t, err, storage.ErrNoContent)
+	_, err = s.strg.URLHistory(ctx, s.alias("missing"), s.user("u1"))
+	assert.ErrorIs(t, err, storage.ErrNoContent)
+	// у пользователя уже есть ссылка на этот адрес
+	_, err = s.strg.UpdateURL(ctx, key, s.user("u1"), s.url("edit-taken"))
+	assert.ErrorIs(t, err, storage.ErrConflict)
+
+	v, err := s.strg.UpdateURL(ctx, key, s.user("u1"), s.url("edit-2"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, v.Version)
+	assert.Equal(t, s.url("edit-2"), v.URL)
+	require.NotNil(t, v.CreatedAt)
+	fURL, err := s.strg.RetFullURL(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, s.url("edit-2"), fURL)
+
+	// замена адреса текущим не создает версию
+	v, err = s.strg.UpdateURL(ctx, key, s.user("u1"), s.url("edit-2"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, v.Version)
+	_, err = s.strg.UpdateURL(ctx, key, s.user("u1"), s.url("edit-3"))
+	require.NoError(t, err)
+
+	// откат добавляет версию с прежним адресом
+	v, err = storage.RollbackURL(ctx, s.strg, key, s.user("u1"), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 4, v.Version)
+	assert.Equal(t, s.url("edit-1"), v.URL)
+	fURL, err = s.strg.RetFullURL(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, s.url("edit-1"), fURL)
+	_, err = storage.RollbackURL(ctx, s.strg, key, s.user("u1"), 9)
+	assert.ErrorIs(t, err, storage.ErrVersionNotFound)
+
+	history, err = s.strg.URLHistory(ctx, key, s.user("u1"))
+	require.NoError(t, err)
+	require.Len(t, history, 4)
+	for i, fURL := range []string{s.url("edit-1"), s.url("edit-2"), s.url("edit-3"), s.url("edit-1")} {
+		assert.Equal(t, i+1, history[i].Version)
+		assert.Equal(t, fURL, history[i].URL)
+	}
+	assert.Nil(t, history[0].CreatedAt)
+	assert.NotNil(t, history[3].CreatedAt)
+
+	// прежний адрес больше не занят ссылкой пользователя
+	other := s.key(s.set(t, s.url("edit-2"), s.user("u1"), storage.URLOptions{}))
+	assert.NotEqual(t, key, other)
+
+	require.NoError(t, s.strg.MarkDeleted(ctx, []string{key}, []string{s.user("u1")}))
+	_, err = s.strg.UpdateURL(ctx, key, s.user("u1"), s.url("edit-4"))
+	assert.ErrorIs(t, err, storage.ErrGone)
+}
+
+func testTimestamps(t *testing.T, s *suite) {
+	ctx := context.Background()
+	record := func(key string) storage.Record {
+		t.Helper()
+		recs, err := s.strg.ExportUserURLs(ctx, s.user("u1"), "", 10)
+		require.NoError(t, err)
+		for _, rec := range recs {
+			if rec.Key == key {
+				return rec
+			}
+		}
+		require.Fail(t, "record not found", key)
+		return storage.Record{}
+	}
+	// хранилища с точностью времени до микросекунд могут округлять его
+	notBefore := func(t *testing.T, want time.Time, got *time.Time) {
+		t.Helper()
+		require.NotNil(t, got)
+		assert.False(t, got.Before(want.Truncate(time.Microsecond)), "%v before %v", got, want)
+	}
+
+	start := time.Now()
+	key := s.key(s.set(t, s.url("timestamps"), s.user("u1"), storage.URLOptions{}))
+	created := record(key)
+	notBefore(t, start, created.CreatedAt)
+	require.NotNil(t, created.UpdatedAt)
+	assert.True(t, created.CreatedAt.Equal(*created.UpdatedAt))
+	assert.Nil(t, created.DeletedAt)
+
+	// изменение адреса меняет только время изменения
+	time.Sleep(2 * time.Millisecond)
+	edited := time.Now()
+	_, err := s.strg.UpdateURL(ctx, key, s.user("u1"), s.url("timestamps-edited"))
+	require.NoError(t, err)
+	rec := record(key)
+	assert.True(t, created.CreatedAt.Equal(*rec.CreatedAt))
+	notBefore(t, edited, rec.UpdatedAt)
+	assert.Nil(t, rec.DeletedAt)
+
+	time.Sleep(2 * time.Millisecond)
+	deleted := time.Now()
+	require.NoError(t, s.strg.MarkDeleted(ctx, []string{key}, []string{s.user("u1")}))
+	rec = record(key)
+	assert.True(t, created.CreatedAt.Equal(*rec.CreatedAt))
+	notBefore(t, deleted, rec.DeletedAt)
+	assert.True(t, rec.DeletedAt.Equal(*rec.UpdatedAt))
+
+	// восстановление снимает время удаления и меняет время изменения
+	time.Sleep(2 * time.Millisecond)
+	restored := time.Now()
+	_, err = s.strg.RestoreURLs(ctx, []string{key}, s.user("u1"), deleted.Add(-time.Hour))
+	require.NoError(t, err)
+	rec = record(key)
+	assert.Nil(t, rec.DeletedAt)
+	notBefore(t, restored, rec.UpdatedAt)
+
+	page, err := s.strg.ReturnAllURLs(ctx, s.user("u1"), storage.ListOptions{}, s.cfg)
+	require.NoError(t, err)
+	require.Len(t, page.URLs, 1)
+	assert.True(t, rec.CreatedAt.Equal(*page.URLs[0].CreatedAt))
+	assert.True(t, rec.UpdatedAt.Equal(*page.URLs[0].UpdatedAt))
+	assert.Nil(t, page.URLs[0].DeletedAt)
+}
+
+func testUsage(t *testing.T, s *suite) {
+	ctx := context.Background()
+	usage, err := s.strg.ReturnUsage(ctx, s.user("u1"), time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, &storage.Usage{}, usage)
+
+	before := time.Now()
+	s.set(t, s.url("usage-active"), s.user("u1"), storage.URLOptions{})
+	expired := s.key(s.set(t, s.url("usage-expired"), s.user("u1"), storage.URLOptions{ExpiresAt: time.Now().Add(100 * time.Millisecond)}))
+	deleted := s.key(s.set(t, s.url("usage-deleted"), s.user("u1"), storage.URLOptions{}))
+	s.set(t, s.url("usage-other"), s.user("u2"), storage.URLOptions{})
+	require.NoError(t, s.strg.MarkDeleted(ctx, []string{deleted}, []string{s.user("u1")}))
+	time.Sleep(150 * time.Millisecond)
+
+	// удаленные и просроченные адреса не действуют, но учитываются в созданных за окно
+	usage, err = s.strg.ReturnUsage(ctx, s.user("u1"), before.Add(-time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 1, usage.Active)
+	assert.Equal(t, 3, usage.Created)
+	require.NotNil(t, usage.FirstCreated)
+	assert.WithinDuration(t, before, *usage.FirstCreated, time.Second)
+
+	// адреса, созданные раньше начала окна, не учитываются в созданных
+	usage, err = s.strg.ReturnUsage(ctx, s.user("u1"), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, &storage.Usage{Active: 1}, usage)
+
+	// восстановленный адрес снова действует
+	results, err := s.strg.RestoreURLs(ctx, []string{deleted, expired}, s.user("u1"), time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, storage.RestoreRestored, results[0].Status)
+	usage, err = s.strg.ReturnUsage(ctx, s.user("u1"), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 2, usage.Active)
+
+	// ключи возвращаются только для адресов, сокращенных самим пользователем, в том числе удаленных
+	keys, err := s.strg.ReturnUserKeys(ctx, s.user("u1"), []string{s.url("usage-deleted"), s.url("usage-other"), s.url("usage-missing")})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{s.url("usage-deleted"): deleted}, keys)
+	keys, err = s.strg.ReturnUserKeys(ctx, s.user("missing"), []string{s.url("usage-active")})
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func testPurge(t *testing.T, s *suite) {
+	ctx := context.Background()
+	key := s.key(s.set(t, s.url("purge"), s.user("u1"), storage.URLOptions{}))
+	kept := s.key(s.set(t, s.url("purge-kept"), s.user("u1"), storage.URLOptions{}))
+	require.NoError(t, s.strg.SaveClicks(ctx, []storage.Click{{Key: key, Time: time.Now().UTC(), IP: "192.0.2.1"}}))
+	require.NoError(t, s.strg.MarkDeleted(ctx, []string{key}, []string{s.user("u1")}))
+	_, err := s.strg.RetFullURL(ctx, key)
+	require.ErrorIs(t, err, storage.ErrGone)
+
+	// адрес, удаленный позже границы, сохраняется
+	_, err = s.strg.PurgeDeleted(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	_, err = s.strg.RetFullURL(ctx, key)
+	assert.ErrorIs(t, err, storage.ErrGone)
+
+	n, err := s.strg.PurgeDeleted(ctx, time.Now())
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, n, 1)
+	_, err = s.strg.RetFullURL(ctx, key)
+	assert.ErrorIs(t, err, storage.ErrNoContent)
+	_, err = s.strg.ReturnClickStats(ctx, key, s.user("u1"), storage.IntervalDay)
+	assert.ErrorIs(t, err, storage.ErrNoContent)
+	fURL, err := s.strg.RetFullURL(ctx, kept)
+	require.NoError(t, err)
+	assert.Equal(t, s.url("purge-kept"), fURL)
+
+	// окончательно удаленный адрес можно сократить снова
+	again := s.set(t, s.url("purge"), s.user("u1"), storage.URLOptions{})
+	fURL, err = s.strg.RetFullURL(ctx, s.key(again))
+	require.NoError(t, err)
+	assert.Equal(t, s.url("purge"), fURL)
+}
+
+func testErase(t *testing.T, s *suite) {
+	ctx := context.Background()
+	keys := []string{
+		s.key(s.set(t, s.url("erase-1"), s.user("u1"), storage.URLOptions{})),
+		s.key(s.set(t, s.url("erase-2"), s.user("u1"), storage.URLOptions{})),
+	}
+	other := s.key(s.set(t, s.url("erase-1"), s.user("u2"), storage.URLOptions{}))
+	require.NoError(t, s.strg.MarkDeleted(ctx, keys[1:], []string{s.user("u1")}))
+	require.NoError(t, s.strg.SaveClicks(ctx, []storage.Click{{Key: keys[0], Time: time.Now().UTC(), IP: "192.0.2.1"}}))
+	_, err := s.strg.RetFullURL(ctx, keys[0])
+	require.NoError(t, err)
+	before, err := s.strg.ReturnStats(ctx, time.Time{})
+	require.NoError(t, err)
+
+	erased, err := s.strg.EraseUser(ctx, s.user("u1"))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, keys, erased)
+	for _, key := range keys {
+		_, err = s.strg.RetFullURL(ctx, key)
+		assert.ErrorIs(t, err, storage.ErrNoContent)
+		_, err = s.strg.ReturnClickStats(ctx, key, s.user("u1"), storage.IntervalDay)
+		assert.ErrorIs(t, err, storage.ErrNoContent)
+	}
+	_, err = s.strg.ReturnAllURLs(ctx, s.user("u1"), storage.ListOptions{IncludeDeleted: true}, s.cfg)
+	assert.ErrorIs(t, err, storage.ErrNoContent)
+	recs, err := s.strg.ExportUserURLs(ctx, s.user("u1"), "", 10)
+	require.NoError(t, err)
+	assert.Empty(t, recs)
+	after, err := s.strg.ReturnStats(ctx, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, -2, after.URLs-before.URLs)
+	assert.Equal(t, -1, after.Users-before.Users)
+
+	// адреса других пользователей не затрагиваются
+	fURL, err := s.strg.RetFullURL(ctx, other)
+	require.NoError(t, err)
+	assert.Equal(t, s.url("erase-1"), fURL)
+
+	erased, err = s.strg.EraseUser(ctx, s.user("nobody"))
+	require.NoError(t, err)
+	assert.Empty(t, erased)
+}
+
+func testConcurrency(t *testing.T, s *suite) {
+	ctx := context.Background()
+	const workers = 16
+	var wg sync.WaitGroup
+	shortURLs := make([]string, workers)
+	errs := make([]error, workers)
+	// одновременное сокращение одного адреса одним пользователем выдает одну ссылку
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			shortURLs[i], errs[i] = s.strg.SetShortURL(ctx, s.url("same"), s.user("u1"), storage.URLOptions{}, s.cfg)
+		}(i)
+	}
+	wg.Wait()
+	created := 0
+	for i := range errs {
+		if errs[i] == nil {
+			created++
+			continue
+		}
+		assert.True(t, errors.Is(errs[i], storage.ErrConflict), errs[i])
+	}
+	assert.Equal(t, 1, created)
+	for i := range shortURLs {
+		assert.Equal(t, shortURLs[0], shortURLs[i])
+	}
+
+	// одновременные записи, удаления и чтения разных адресов не мешают друг другу
+	keys := make([]string, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			userID := s.user("w" + strconv.Itoa(i))
+			shortURL, err := s.strg.SetShortURL(ctx, s.url("parallel"), userID, storage.URLOptions{}, s.cfg)
+			if !assert.NoError(t, err) {
+				return
+			}
+			keys[i] = s.key(shortURL)
+			assert.NoError(t, s.strg.MarkDeleted(ctx, []string{keys[i]}, []string{userID}))
+			_, err = s.strg.RetFullURL(ctx, keys[i])
+			assert.ErrorIs(t, err, storage.ErrGone)
+			_, err = s.strg.ReturnStats(ctx, time.Time{})
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+	seen := make(map[string]bool, workers)
+	for _, key := range keys {
+		assert.False(t, seen[key], "duplicate key %s", key)
+		seen[key] = true
+	}
+}
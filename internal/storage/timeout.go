@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"shortURL/internal/config"
+)
+
+// timeoutStorage ограничивает время выполнения операций хранилища.
+// Операции чтения и записи получают контекст с разными ограничениями, заданными в конфигурации сервиса.
+type timeoutStorage struct {
+	Storager
+	read  time.Duration
+	write time.Duration
+}
+
+// WithTimeouts функция возвращает хранилище, операции которого прерываются по истечении заданного времени.
+// Нулевое значение означает отсутствие ограничения.
+func WithTimeouts(strg Storager, read, write time.Duration) Storager {
+	return &timeoutStorage{Storager: strg, read: read, write: write}
+}
+
+// withTimeout функция ограничивает время выполнения операции, если ограничение задано.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// SetShortURL метод сохраняет адрес с ограничением времени записи.
+func (s *timeoutStorage) SetShortURL(ctx context.Context, fURL string, userID string, opts URLOptions, cfg *config.Config) (string, error) {
+	ctx, cancel := withTimeout(ctx, s.write)
+	defer cancel()
+	return s.Storager.SetShortURL(ctx, fURL, userID, opts, cfg)
+}
+
+// WriteMultiURL метод сохраняет пакет адресов с ограничением времени записи.
+func (s *timeoutStorage) WriteMultiURL(ctx context.Context, m []MultiURL, userID string, cfg *config.Config) ([]MultiURL, error) {
+	ctx, cancel := withTimeout(ctx, s.write)
+	defer cancel()
+	return s.Storager.WriteMultiURL(ctx, m, userID, cfg)
+}
+
+// RetFullURL метод возвращает исходный адрес с ограничением времени чтения.
+func (s *timeoutStorage) RetFullURL(ctx context.Context, key string) (string, error) {
+	ctx, cancel := withTimeout(ctx, s.read)
+	defer cancel()
+	return s.Storager.RetFullURL(ctx, key)
+}
+
+// ReturnAllURLs метод возвращает страницу адресов пользователя с ограничением времени чтения.
+func (s *timeoutStorage) ReturnAllURLs(ctx context.Context, userID string, opts ListOptions, cfg *config.Config) (*URLPage, error) {
+	ctx, cancel := withTimeout(ctx, s.read)
+	defer cancel()
+	return s.Storager.ReturnAllURLs(ctx, userID, opts, cfg)
+}
+
+// ReturnStats метод возвращает статистику сервиса с ограничением времени чтения.
+func (s *timeoutStorage) ReturnStats(ctx context.Context, since time.Time) (*stats, error) {
+	ctx, cancel := withTimeout(ctx, s.read)
+	defer cancel()
+	return s.Storager.ReturnStats(ctx, since)
+}
+
+// CheckPing метод проверяет соединение с базой данных с ограничением времени чтения.
+func (s *timeoutStorage) CheckPing(ctx context.Context, cfg *config.Config) error {
+	ctx, cancel := withTimeout(ctx, s.read)
+	defer cancel()
+	return s.Storager.CheckPing(ctx, cfg)
+}
+
+// MarkDeleted метод помечает адреса удаленными с ограничением времени записи.
+func (s *timeoutStorage) MarkDeleted(ctx context.Context, keys []string, ids []string) error {
+	ctx, cancel := withTimeout(ctx, s.write)
+	defer cancel()
+	return s.Storager.MarkDeleted(ctx, keys, ids)
+}
+
+// ExpireURLs метод помечает просроченные адреса с ограничением времени записи.
+func (s *timeoutStorage) ExpireURLs(ctx context.Context, now time.Time) (int, error) {
+	ctx, cancel := withTimeout(ctx, s.write)
+	defer cancel()
+	return s.Storager.ExpireURLs(ctx, now)
+}
+
+// SaveClicks метод сохраняет переходы с ограничением времени записи.
+func (s *timeoutStorage) SaveClicks(ctx context.Context, clicks []Click) error {
+	ctx, cancel := withTimeout(ctx, s.write)
+	defer cancel()
+	return s.Storager.SaveClicks(ctx, clicks)
+}
+
+// ReturnClickStats метод возвращает статистику переходов с ограничением времени чтения.
+func (s *timeoutStorage) ReturnClickStats(ctx context.Context, key, userID, interval string) (*ClickStats, error) {
+	ctx, cancel := withTimeout(ctx, s.read)
+	defer cancel()
+	return s.Storager.ReturnClickStats(ctx, key, userID, interval)
+}
+
+// ExportUserURLs метод возвращает страницу адресов пользователя с ограничением времени чтения.
+func (s *timeoutStorage) ExportUserURLs(ctx context.Context, userID string, after string, limit int) ([]Record, error) {
+	ctx, cancel := withTimeout(ctx, s.read)
+	defer cancel()
+	return s.Storager.ExportUserURLs(ctx, userID, after, limit)
+}
+
+// PurgeDeleted метод окончательно удаляет адреса с ограничением времени записи.
+func (s *timeoutStorage) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	ctx, cancel := withTimeout(ctx, s.write)
+	defer cancel()
+	return s.Storager.PurgeDeleted(ctx, before)
+}
+
+// EraseUser метод удаляет адреса пользователя с ограничением времени записи.
+func (s *timeoutStorage) EraseUser(ctx context.Context, userID string) ([]string, error) {
+	ctx, cancel := withTimeout(ctx, s.write)
+	defer cancel()
+	return s.Storager.EraseUser(ctx, userID)
+}
+
+// RestoreURLs метод восстанавливает удаленные адреса с ограничением времени записи.
+func (s *timeoutStorage) RestoreURLs(ctx context.Context, keys []string, userID string, since time.Time) ([]RestoreResult, error) {
+	ctx, cancel := withTimeout(ctx, s.write)
+	defer cancel()
+	return s.Storager.RestoreURLs(ctx, keys, userID, since)
+}
+
+// UpdateURL метод заменяет исходный адрес ссылки с ограничением времени записи.
+func (s *timeoutStorage) UpdateURL(ctx context.Context, key, userID, fURL string) (*URLVersion, error) {
+	ctx, cancel := withTimeout(ctx, s.write)
+	defer cancel()
+	return s.Storager.UpdateURL(ctx, key, userID, fURL)
+}
+
+// URLHistory метод возвращает историю адресов ссылки с ограничением времени чтения.
+func (s *timeoutStorage) URLHistory(ctx context.Context, key, userID string) ([]URLVersion, error) {
+	ctx, cancel := withTimeout(ctx, s.read)
+	defer cancel()
+	return s.Storager.URLHistory(ctx, key, userID)
+}
+
+// ReturnUsage метод возвращает использование ограничений пользователя с ограничением времени чтения.
+func (s *timeoutStorage) ReturnUsage(ctx context.Context, userID string, since time.Time) (*Usage, error) {
+	ctx, cancel := withTimeout(ctx, s.read)
+	defer cancel()
+	return s.Storager.ReturnUsage(ctx, userID, since)
+}
+
+// ReturnUserKeys метод возвращает ключи ранее сокращенных пользователем адресов с ограничением времени чтения.
+func (s *timeoutStorage) ReturnUserKeys(ctx context.Context, userID string, fURLs []string) (map[string]string, error) {
+	ctx, cancel := withTimeout(ctx, s.read)
+	defer cancel()
+	return s.Storager.ReturnUserKeys(ctx, userID, fURLs)
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"shortURL/internal/config"
+)
+
+// slowStorage ожидает завершения контекста и возвращает его ошибку.
+type slowStorage struct {
+	Storager
+}
+
+func (s slowStorage) SetShortURL(ctx context.Context, fURL string, userID string, opts URLOptions, cfg *config.Config) (string, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func (s slowStorage) RetFullURL(ctx context.Context, key string) (string, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func TestWithTimeouts(t *testing.T) {
+	strg := WithTimeouts(slowStorage{}, 10*time.Millisecond, 50*time.Millisecond)
+
+	start := time.Now()
+	_, err := strg.RetFullURL(context.Background(), "key")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+
+	start = time.Now()
+	_, err = strg.SetShortURL(context.Background(), "https://go.dev/", "user1", URLOptions{}, &config.Config{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = strg.RetFullURL(ctx, "key")
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"shortURL/internal/config"
+)
+
+// ExportBatchSize - количество адресов, которое выгрузка читает из хранилища за один запрос.
+const ExportBatchSize = 500
+
+// maxExportedKeyLen - наибольшая длина ключа выгруженной ссылки: длиннее не бывают ни псевдонимы, ни сгенерированные ключи.
+const maxExportedKeyLen = 32
+
+// UserURL структура адреса пользователя для выгрузки и загрузки.
+// При загрузке поля CreatedAt, UpdatedAt и DeletedAt не учитываются: адрес сохраняется как созданный в момент загрузки
+// с псевдонимом Alias, а без него - с ключом выгруженной ссылки ShortURL, если он свободен, иначе с новым ключом.
+type UserURL struct {
+	ShortURL    string     `json:"short_url,omitempty"`
+	OriginalURL string     `json:"original_url"`
+	Alias       string     `json:"alias,omitempty"`
+	Deleted     bool       `json:"deleted"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+// ImportResult структура с результатом загрузки одного адреса.
+type ImportResult struct {
+	Row         int    `json:"row"`
+	OriginalURL string `json:"original_url"`
+	ShortURL    string `json:"short_url,omitempty"`
+	Status      string `json:"status"`
+	Error       string `json:"error,omitempty"`
+}
+
+// ExportUser функция передает fn адреса пользователя в порядке возрастания ключа.
+// Адреса читаются из хранилища страницами, поэтому выгрузка не держит в памяти весь набор адресов.
+func ExportUser(ctx context.Context, strg Storager, userID string, cfg *config.Config, fn func(UserURL) error) error {
+	after := ""
+	for {
+		recs, err := strg.ExportUserURLs(ctx, userID, after, ExportBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, rec := range recs {
+			err = fn(UserURL{
+				ShortURL:    cfg.BaseURL + "/" + rec.Key,
+				OriginalURL: rec.Value,
+				Deleted:     rec.Deleted,
+				ExpiresAt:   rec.ExpiresAt,
+				CreatedAt:   rec.CreatedAt,
+				UpdatedAt:   rec.UpdatedAt,
+				DeletedAt:   rec.DeletedAt,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if len(recs) < ExportBatchSize {
+			return nil
+		}
+		after = recs[len(recs)-1].Key
+	}
+}
+
+// ImportURL функция сохраняет адрес пользователя так же, как SetShortURL, и возвращает результат загрузки.
+// Уже сокращенный пользователем адрес отмечается как существующий, его состояние не меняется.
+// Срок действия удаленного адреса не проверяется, т.к. выгрузка содержит и просроченные адреса.
+// Ошибка возвращается, только если хранилище не смогло обработать запрос.
+func ImportURL(ctx context.Context, strg Storager, userID string, u UserURL, cfg *config.Config) (ImportResult, error) {
+	r := ImportResult{OriginalURL: u.OriginalURL, Status: BatchInvalid}
+	if err := CheckBatchURL(u.OriginalURL); err != nil {
+		r.Error = err.Error()
+		return r, nil
+	}
+	opts := URLOptions{Alias: u.Alias}
+	if u.Alias != "" {
+		if err := CheckAlias(u.Alias); err != nil {
+			r.Error = err.Error()
+			return r, nil
+		}
+	}
+	if u.Deleted {
+		if u.ExpiresAt != nil {
+			opts.ExpiresAt = u.ExpiresAt.UTC()
+		}
+	} else {
+		expiresAt, err := ExpiryTime(u.ExpiresAt, 0)
+		if err != nil {
+			r.Error = err.Error()
+			return r, nil
+		}
+		opts.ExpiresAt = expiresAt
+	}
+	// ключ выгруженной ссылки сохраняется, чтобы не менять уже опубликованные короткие ссылки
+	shortURL, err := "", ErrAliasTaken
+	if key := exportedKey(u.ShortURL); opts.Alias == "" && key != "" {
+		shortURL, err = strg.SetShortURL(ctx, u.OriginalURL, userID, URLOptions{Alias: key, ExpiresAt: opts.ExpiresAt}, cfg)
+	}
+	if errors.Is(err, ErrAliasTaken) {
+		shortURL, err = strg.SetShortURL(ctx, u.OriginalURL, userID, opts, cfg)
+	}
+	switch {
+	case errors.Is(err, ErrConflict):
+		r.ShortURL, r.Status = shortURL, BatchExists
+		return r, nil
+	case errors.Is(err, ErrAliasTaken), errors.Is(err, ErrQuotaExceeded):
+		r.Error = err.Error()
+		return r, nil
+	case err != nil:
+		return r, err
+	}
+	r.ShortURL, r.Status = shortURL, BatchCreated
+	if u.Deleted {
+		key := strings.TrimPrefix(shortURL, cfg.BaseURL+"/")
+		if err := strg.MarkDeleted(ctx, []string{key}, []string{userID}); err != nil {
+			return r, err
+		}
+	}
+	return r, nil
+}
+
+// exportedKey функция возвращает ключ выгруженной короткой ссылки - последний сегмент ее адреса,
+// если он может быть ключом: состоит из допустимых символов и не совпадает с зарезервированным словом.
+func exportedKey(shortURL string) string {
+	key := shortURL[strings.LastIndexByte(shortURL, '/')+1:]
+	if key == "" || len(key) > maxExportedKeyLen || isReserved(key) {
+		return ""
+	}
+	for _, c := range key {
+		if !isAliasChar(c) {
+			return ""
+		}
+	}
+	return key
+}
+
+// ExportUserURLs метод возвращает страницу адресов пользователя в порядке возрастания ключа.
+func (s *MemoryStorage) ExportUserURLs(ctx context.Context, userID string, after string, limit int) ([]Record, error) {
+	return s.records(s.userKeys(userID, after, limit)), nil
+}
+
+// ExportUserURLs метод возвращает страницу адресов пользователя в порядке возрастания ключа.
+func (s *SQLStorage) ExportUserURLs(ctx context.Context, userID string, after string, limit int) ([]Record, error) {
+	return s.queryRecords(ctx, "SELECT key, user_id, value, deleted, deleted_at, expires_at, created_at, updated_at FROM Short_URLs WHERE user_id = $1 AND key > $2 ORDER BY key LIMIT $3", userID, after, limit)
+}
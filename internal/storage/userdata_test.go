@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"shortURL/internal/config"
+)
+
+func TestExportUser(t *testing.T) {
+	cfg := &config.Config{BaseURL: "http://127.0.0.1:8080", KeyLength: 8}
+	strg := NewMemoryStorager(NewKeyGenerator(cfg))
+	// адресов больше, чем помещается на одну страницу выгрузки
+	for i := 0; i < ExportBatchSize+10; i++ {
+		_, err := strg.SetShortURL(context.Background(), "https://go.dev/"+strconv.Itoa(i), "user1", URLOptions{}, cfg)
+		require.NoError(t, err)
+	}
+	_, err := strg.SetShortURL(context.Background(), "https://go.dev/", "user2", URLOptions{}, cfg)
+	require.NoError(t, err)
+
+	seen := make(map[string]bool)
+	last := ""
+	err = ExportUser(context.Background(), strg, "user1", cfg, func(u UserURL) error {
+		assert.Greater(t, u.ShortURL, last)
+		last = u.ShortURL
+		seen[u.OriginalURL] = true
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, seen, ExportBatchSize+10)
+	assert.False(t, seen["https://go.dev/"])
+}
+
+func TestImportURL(t *testing.T) {
+	cfg := &config.Config{BaseURL: "http://127.0.0.1:8080", KeyLength: 8}
+	strg := NewMemoryStorager(NewKeyGenerator(cfg))
+	past := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name   string
+		url    UserURL
+		status string
+	}{
+		{name: "new", url: UserURL{OriginalURL: "https://go.dev/", Alias: "go-dev"}, status: BatchCreated},
+		{name: "duplicate", url: UserURL{OriginalURL: "https://go.dev/"}, status: BatchExists},
+		{name: "taken alias", url: UserURL{OriginalURL: "https://pkg.go.dev/", Alias: "go-dev"}, status: BatchInvalid},
+		{name: "expired", url: UserURL{OriginalURL: "https://pkg.go.dev/", ExpiresAt: &past}, status: BatchInvalid},
+		{name: "expired deleted", url: UserURL{OriginalURL: "https://pkg.go.dev/", Alias: "pkg-go-dev", Deleted: true, ExpiresAt: &past}, status: BatchCreated},
+	}
+	for _, tt := range tests {
+		r, err := ImportURL(context.Background(), strg, "user1", tt.url, cfg)
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.status, r.Status, tt.name)
+	}
+	_, err := strg.RetFullURL(context.Background(), "pkg-go-dev")
+	assert.Error(t, err)
+	r, ok := strg.record("pkg-go-dev")
+	require.True(t, ok)
+	assert.True(t, r.deleted)
+
+	// ключ выгруженной ссылки сохраняется, занятый или зарезервированный ключ заменяется новым
+	res, err := ImportURL(context.Background(), strg, "user2", UserURL{OriginalURL: "https://go.dev/doc/", ShortURL: "https://old.example/a1"}, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, BatchCreated, res.Status)
+	assert.Equal(t, cfg.BaseURL+"/a1", res.ShortURL)
+	for _, shortURL := range []string{cfg.BaseURL + "/a1", cfg.BaseURL + "/ping", cfg.BaseURL + "/bad key"} {
+		res, err = ImportURL(context.Background(), strg, "user3", UserURL{OriginalURL: "https://go.dev/" + shortURL, ShortURL: shortURL}, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, BatchCreated, res.Status)
+		assert.NotEqual(t, shortURL, res.ShortURL)
+	}
+}
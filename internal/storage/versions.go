@@ -0,0 +1,303 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// URLVersion структура с версией исходного адреса короткой ссылки.
+// История ссылки начинается с первого изменения, время первой версии не сохраняется.
+type URLVersion struct {
+	Version   int        `json:"version"`
+	URL       string     `json:"original_url"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// RollbackURL функция возвращает ссылке исходный адрес одной из прежних версий.
+// Откат добавляет в историю новую версию, поэтому история ссылки не переписывается.
+func RollbackURL(ctx context.Context, strg Storager, key, userID string, version int) (*URLVersion, error) {
+	history, err := strg.URLHistory(ctx, key, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range history {
+		if v.Version == version {
+			return strg.UpdateURL(ctx, key, userID, v.URL)
+		}
+	}
+	return nil, ErrVersionNotFound
+}
+
+// urlHistory функция возвращает копию истории ссылки. У ссылки без изменений единственная версия - текущий адрес.
+func urlHistory(value string, versions []URLVersion) []URLVersion {
+	if len(versions) == 0 {
+		return []URLVersion{{Version: 1, URL: value}}
+	}
+	history := make([]URLVersion, len(versions))
+	copy(history, versions)
+	return history
+}
+
+// urlEdit структура с изменением исходного адреса ссылки: состояния до и после изменения и добавленные версии.
+type urlEdit struct {
+	prev         storageStruct
+	prevVersions []URLVersion
+	rec          storageStruct
+	added        []URLVersion
+}
+
+// logRecords метод формирует записи журнала об изменении адреса.
+func (e urlEdit) logRecords() []logRecord {
+	if len(e.added) == 0 {
+		return nil
+	}
+	recs := make([]logRecord, 0, 1+len(e.added))
+	recs = append(recs, updateRecord(e.rec))
+	for _, v := range e.added {
+		recs = append(recs, versionRecord(e.rec.Key, v))
+	}
+	return recs
+}
+
+// UpdateURL метод заменяет исходный адрес ссылки пользователя и возвращает новую версию.
+func (s *MemoryStorage) UpdateURL(ctx context.Context, key, userID, fURL string) (*URLVersion, error) {
+	v, _, err := s.editURL(key, userID, fURL)
+	return v, err
+}
+
+// editURL метод заменяет исходный адрес ссылки пользователя и дополняет историю ссылки.
+// При первом изменении в историю добавляется и прежний адрес. Замена адреса текущим не создает новую версию.
+func (s *MemoryStorage) editURL(key, userID, fURL string) (*URLVersion, urlEdit, error) {
+	r, ok := s.record(key)
+	if !ok {
+		return nil, urlEdit{}, ErrNoContent
+	}
+	if r.owner.id != userID {
+		return nil, urlEdit{}, ErrForbidden
+	}
+	us := s.userShard(userID)
+	us.Lock()
+	defer us.Unlock()
+	sh := s.urlShard(key)
+	sh.Lock()
+	defer sh.Unlock()
+	// до блокировки индекса пользователя адрес мог быть удален окончательно
+	r, ok = sh.urls[key]
+	if !ok || r.owner.id != userID {
+		return nil, urlEdit{}, ErrNoContent
+	}
+	now := time.Now().UTC()
+	if r.expires != 0 && r.expires <= now.UnixNano() {
+		return nil, urlEdit{}, ErrExpired
+	}
+	if r.deleted {
+		return nil, urlEdit{}, ErrGone
+	}
+	versions := sh.versions[key]
+	if r.value == fURL {
+		history := urlHistory(r.value, versions)
+		return &history[len(history)-1], urlEdit{}, nil
+	}
+	if other, ok := r.owner.keys[fURL]; ok && other != key {
+		return nil, urlEdit{}, ErrConflict
+	}
+
+	edit := urlEdit{prev: r.toStorageStruct(key), prevVersions: versions}
+	if len(versions) == 0 {
+		edit.added = append(edit.added, URLVersion{Version: 1, URL: r.value})
+	}
+	v := URLVersion{Version: len(versions) + len(edit.added) + 1, URL: fURL, CreatedAt: &now}
+	edit.added = append(edit.added, v)
+	// новая история не должна разделять массив с прежней, которая сохраняется для отмены изменения
+	sh.versions[key] = append(versions[:len(versions):len(versions)], edit.added...)
+
+	if r.owner.keys[r.value] == key {
+		delete(r.owner.keys, r.value)
+	}
+	r.owner.keys[fURL] = key
+	r.value, r.updated = fURL, now.UnixNano()
+	sh.urls[key] = r
+	edit.rec = r.toStorageStruct(key)
+	return &v, edit, nil
+}
+
+// revertEdit метод отменяет несохраненное изменение адреса, если после него адрес не менялся.
+// Вызывается под блокировкой файлового хранилища, поэтому между проверкой и отменой адрес не меняется.
+func (s *MemoryStorage) revertEdit(e urlEdit) {
+	sh := s.urlShard(e.prev.Key)
+	sh.RLock()
+	r, ok := sh.urls[e.prev.Key]
+	changed := !ok || r.value != e.rec.Value || len(sh.versions[e.prev.Key]) != len(e.prevVersions)+len(e.added)
+	sh.RUnlock()
+	if changed || !s.update(e.prev) {
+		return
+	}
+	sh.Lock()
+	if len(e.prevVersions) == 0 {
+		delete(sh.versions, e.prev.Key)
+	} else {
+		sh.versions[e.prev.Key] = e.prevVersions
+	}
+	sh.Unlock()
+}
+
+// addVersion метод добавляет версию в историю ссылки.
+func (s *MemoryStorage) addVersion(key string, v URLVersion) {
+	sh := s.urlShard(key)
+	sh.Lock()
+	defer sh.Unlock()
+	if _, ok := sh.urls[key]; ok {
+		sh.versions[key] = append(sh.versions[key], v)
+	}
+}
+
+// versionCount метод возвращает количество сохраненных версий адресов.
+func (s *MemoryStorage) versionCount() int {
+	n := 0
+	for i := range s.urls {
+		s.urls[i].RLock()
+		for _, versions := range s.urls[i].versions {
+			n += len(versions)
+		}
+		s.urls[i].RUnlock()
+	}
+	return n
+}
+
+// URLHistory метод возвращает владельцу историю исходных адресов ссылки.
+func (s *MemoryStorage) URLHistory(ctx context.Context, key, userID string) ([]URLVersion, error) {
+	sh := s.urlShard(key)
+	sh.RLock()
+	defer sh.RUnlock()
+	r, ok := sh.urls[key]
+	if !ok {
+		return nil, ErrNoContent
+	}
+	if r.owner.id != userID {
+		return nil, ErrForbidden
+	}
+	return urlHistory(r.value, sh.versions[key]), nil
+}
+
+// UpdateURL метод заменяет исходный адрес ссылки пользователя и дописывает в файл записи об изменении.
+// Если записи не удалось сохранить, изменение отменяется.
+func (s *FileStorage) UpdateURL(ctx context.Context, key, userID, fURL string) (*URLVersion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.Lock()
+	v, edit, err := s.editURL(key, userID, fURL)
+	if err != nil {
+		s.Unlock()
+		return nil, err
+	}
+	done := s.appendLog(edit.logRecords())
+	s.Unlock()
+	if err = waitDone(done); err != nil {
+		if len(edit.added) > 0 {
+			s.Lock()
+			s.revertEdit(edit)
+			s.Unlock()
+		}
+		return nil, err
+	}
+	return v, nil
+}
+
+// UpdateURL метод заменяет исходный адрес ссылки пользователя в транзакции, блокирующей строку ссылки,
+// поэтому одновременные изменения одной ссылки получают последовательные номера версий.
+func (s *SQLStorage) UpdateURL(ctx context.Context, key, userID, fURL string) (*URLVersion, error) {
+	tx, err := s.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	var owner, value string
+	var deleted bool
+	var expiresAt *time.Time
+	err = tx.QueryRow(ctx, "SELECT user_id, value, deleted, expires_at FROM Short_URLs WHERE key = $1 FOR UPDATE", key).
+		Scan(&owner, &value, &deleted, &expiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNoContent
+	}
+	if err != nil {
+		return nil, err
+	}
+	if owner != userID {
+		return nil, ErrForbidden
+	}
+	now := time.Now().UTC()
+	if expiresAt != nil && expired(*expiresAt, now) {
+		return nil, ErrExpired
+	}
+	if deleted {
+		return nil, ErrGone
+	}
+	if value == fURL {
+		history, err := versionsHistory(ctx, tx, key, value)
+		if err != nil {
+			return nil, err
+		}
+		return &history[len(history)-1], nil
+	}
+	other, err := userKey(ctx, tx.QueryRow, userID, fURL)
+	if err != nil {
+		return nil, err
+	}
+	if other != "" {
+		return nil, ErrConflict
+	}
+	if _, err = tx.Exec(ctx, "UPDATE Short_URLs SET value = $2, updated_at = $3 WHERE key = $1", key, fURL, now); err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(ctx, `INSERT INTO URL_Versions(key, version, value, created_at)
+		SELECT $1, 1, $2, NULL WHERE NOT EXISTS (SELECT 1 FROM URL_Versions WHERE key = $1)`, key, value)
+	if err != nil {
+		return nil, err
+	}
+	v := URLVersion{URL: fURL, CreatedAt: &now}
+	err = tx.QueryRow(ctx, `INSERT INTO URL_Versions(key, version, value, created_at)
+		SELECT $1, max(version) + 1, $2, $3 FROM URL_Versions WHERE key = $1 RETURNING version`, key, fURL, now).Scan(&v.Version)
+	if err != nil {
+		return nil, err
+	}
+	return &v, tx.Commit(ctx)
+}
+
+// URLHistory метод возвращает владельцу историю исходных адресов ссылки.
+func (s *SQLStorage) URLHistory(ctx context.Context, key, userID string) ([]URLVersion, error) {
+	var owner, value string
+	err := s.Pool.QueryRow(ctx, "SELECT user_id, value FROM Short_URLs WHERE key = $1", key).Scan(&owner, &value)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNoContent
+	}
+	if err != nil {
+		return nil, err
+	}
+	if owner != userID {
+		return nil, ErrForbidden
+	}
+	return versionsHistory(ctx, s.Pool, key, value)
+}
+
+// versionsHistory функция читает историю ссылки по возрастанию номера версии.
+func versionsHistory(ctx context.Context, q interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}, key, value string) ([]URLVersion, error) {
+	rows, err := q.Query(ctx, "SELECT version, value, created_at FROM URL_Versions WHERE key = $1 ORDER BY version", key)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (URLVersion, error) {
+		var v URLVersion
+		err := row.Scan(&v.Version, &v.URL, &v.CreatedAt)
+		return v, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return urlHistory(value, versions), nil
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"shortURL/internal/config"
+)
+
+func TestFileStorageVersions(t *testing.T) {
+	cfg := &config.Config{BaseURL: "http://127.0.0.1:8080", FileStoragePath: filepath.Join(t.TempDir(), "storage.json"), KeyLength: 8}
+	strg := NewFileStorager(cfg, NewKeyGenerator(cfg))
+	_, err := strg.SetShortURL(context.Background(), "https://go.dev/", "user1", URLOptions{Alias: "go-dev"}, cfg)
+	require.NoError(t, err)
+	for _, fURL := range []string{"https://go.dev/doc/", "https://go.dev/blog/"} {
+		_, err = strg.UpdateURL(context.Background(), "go-dev", "user1", fURL)
+		require.NoError(t, err)
+	}
+	history, err := strg.URLHistory(context.Background(), "go-dev", "user1")
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	strg.CloseDB()
+
+	// несохраненное изменение отменяется вместе с версией
+	_, err = strg.UpdateURL(context.Background(), "go-dev", "user1", "https://go.dev/play/")
+	assert.ErrorIs(t, err, ErrUnavailable)
+	fURL, err := strg.RetFullURL(context.Background(), "go-dev")
+	require.NoError(t, err)
+	assert.Equal(t, "https://go.dev/blog/", fURL)
+	reverted, err := strg.URLHistory(context.Background(), "go-dev", "user1")
+	require.NoError(t, err)
+	assert.Equal(t, history, reverted)
+
+	// отмена не затирает изменение, сделанное после отменяемого
+	_, stale, err := strg.editURL("go-dev", "user1", "https://go.dev/play/")
+	require.NoError(t, err)
+	_, _, err = strg.editURL("go-dev", "user1", "https://go.dev/tour/")
+	require.NoError(t, err)
+	strg.revertEdit(stale)
+	fURL, err = strg.RetFullURL(context.Background(), "go-dev")
+	require.NoError(t, err)
+	assert.Equal(t, "https://go.dev/tour/", fURL)
+
+	// история восстанавливается из файла и сохраняется при сжатии
+	restored := NewFileStorager(cfg, NewKeyGenerator(cfg))
+	require.NoError(t, restored.compact())
+	restored.CloseDB()
+	restored = NewFileStorager(cfg, NewKeyGenerator(cfg))
+	assert.Equal(t, 4, restored.logRecords)
+	fURL, err = restored.RetFullURL(context.Background(), "go-dev")
+	require.NoError(t, err)
+	assert.Equal(t, "https://go.dev/blog/", fURL)
+	replayed, err := restored.URLHistory(context.Background(), "go-dev", "user1")
+	require.NoError(t, err)
+	require.Len(t, replayed, 3)
+	for i := range history {
+		assert.Equal(t, history[i].Version, replayed[i].Version)
+		assert.Equal(t, history[i].URL, replayed[i].URL)
+	}
+	require.NotNil(t, replayed[2].CreatedAt)
+	assert.True(t, history[2].CreatedAt.Equal(*replayed[2].CreatedAt))
+
+	// прежний адрес не занят ссылкой пользователя после перезапуска
+	_, err = restored.SetShortURL(context.Background(), "https://go.dev/doc/", "user1", URLOptions{}, cfg)
+	require.NoError(t, err)
+	_, err = restored.SetShortURL(context.Background(), "https://go.dev/blog/", "user1", URLOptions{}, cfg)
+	assert.ErrorIs(t, err, ErrConflict)
+}
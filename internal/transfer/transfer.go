@@ -0,0 +1,305 @@
+// Модуль переносит записи коротких ссылок из одного хранилища в другое.
+// Записи читаются страницами в порядке возрастания ключа, последний перенесенный ключ сохраняется в файл прогресса,
+// поэтому прерванный перенос продолжается с места остановки. После переноса формируется отчет сверки хранилищ.
+package transfer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"time"
+
+	"shortURL/internal/storage"
+)
+
+// Параметры переноса по умолчанию.
+const (
+	DefaultBatchSize  = 500
+	DefaultSampleSize = 100
+)
+
+// Options структура с параметрами переноса.
+// Source и Target описывают хранилища и сохраняются в файле прогресса, чтобы не продолжить перенос между другими хранилищами.
+type Options struct {
+	Source       string
+	Target       string
+	BatchSize    int
+	SampleSize   int
+	DryRun       bool
+	ProgressPath string
+}
+
+// Progress структура с состоянием переноса, сохраняемым после каждой страницы записей.
+type Progress struct {
+	Source    string    `json:"source"`
+	Target    string    `json:"target"`
+	LastKey   string    `json:"last_key"`
+	Read      int       `json:"read"`
+	Copied    int       `json:"copied"`
+	Skipped   int       `json:"skipped"`
+	Done      bool      `json:"done"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Counts структура с количеством записей хранилища.
+type Counts struct {
+	Total   int `json:"total"`
+	Deleted int `json:"deleted"`
+}
+
+// Report структура с результатом переноса и сверки хранилищ.
+// При пробном запуске Copied - количество записей, которые были бы перенесены, Skipped - записи с занятым ключом.
+type Report struct {
+	DryRun     bool     `json:"dry_run"`
+	ResumedAt  string   `json:"resumed_at,omitempty"`
+	Read       int      `json:"read"`
+	Copied     int      `json:"copied"`
+	Skipped    int      `json:"skipped"`
+	Source     Counts   `json:"source"`
+	Target     Counts   `json:"target"`
+	Sampled    int      `json:"sampled"`
+	Missing    []string `json:"missing,omitempty"`
+	Mismatched []string `json:"mismatched,omitempty"`
+}
+
+// OK метод сообщает, прошла ли сверка: все проверенные записи найдены в целевом хранилище без расхождений,
+// и целевое хранилище содержит не меньше записей, чем исходное.
+func (r *Report) OK() bool {
+	if r.DryRun {
+		return true
+	}
+	return len(r.Missing) == 0 && len(r.Mismatched) == 0 && r.Target.Total >= r.Source.Total
+}
+
+// Print метод выводит отчет в текстовом виде.
+func (r *Report) Print(w io.Writer) {
+	mode := "migration"
+	if r.DryRun {
+		mode = "dry run"
+	}
+	fmt.Fprintf(w, "%s report\n", mode)
+	if r.ResumedAt != "" {
+		fmt.Fprintf(w, "  resumed after key %q\n", r.ResumedAt)
+	}
+	fmt.Fprintf(w, "  read %d, copied %d, skipped %d\n", r.Read, r.Copied, r.Skipped)
+	fmt.Fprintf(w, "  source: %d records, %d deleted\n", r.Source.Total, r.Source.Deleted)
+	fmt.Fprintf(w, "  target: %d records, %d deleted\n", r.Target.Total, r.Target.Deleted)
+	if r.DryRun {
+		return
+	}
+	fmt.Fprintf(w, "  sampled %d keys: %d missing, %d mismatched\n", r.Sampled, len(r.Missing), len(r.Mismatched))
+	for _, key := range r.Missing {
+		fmt.Fprintf(w, "    missing %s\n", key)
+	}
+	for _, key := range r.Mismatched {
+		fmt.Fprintf(w, "    mismatched %s\n", key)
+	}
+	if r.OK() {
+		fmt.Fprintln(w, "  verification passed")
+	} else {
+		fmt.Fprintln(w, "  verification FAILED")
+	}
+}
+
+// Run функция переносит записи из src в dst и возвращает отчет сверки.
+func Run(ctx context.Context, src, dst storage.RecordStorager, opts Options) (*Report, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	if opts.SampleSize <= 0 {
+		opts.SampleSize = DefaultSampleSize
+	}
+	progress, err := loadProgress(opts)
+	if err != nil {
+		return nil, err
+	}
+	report := Report{DryRun: opts.DryRun, ResumedAt: progress.LastKey}
+	for !progress.Done {
+		recs, err := src.ExportRecords(ctx, progress.LastKey, opts.BatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("export after key %q: %w", progress.LastKey, err)
+		}
+		if len(recs) == 0 {
+			progress.Done = true
+			break
+		}
+		copied, err := copyRecords(ctx, dst, recs, opts.DryRun)
+		if err != nil {
+			return nil, fmt.Errorf("import after key %q: %w", progress.LastKey, err)
+		}
+		report.Read += len(recs)
+		report.Copied += copied
+		report.Skipped += len(recs) - copied
+		progress.LastKey = recs[len(recs)-1].Key
+		progress.Read += len(recs)
+		progress.Copied += copied
+		progress.Skipped += len(recs) - copied
+		if opts.DryRun {
+			continue
+		}
+		if err = saveProgress(opts.ProgressPath, progress); err != nil {
+			return nil, err
+		}
+	}
+	if !opts.DryRun {
+		if err = saveProgress(opts.ProgressPath, progress); err != nil {
+			return nil, err
+		}
+	}
+
+	sample, err := countRecords(ctx, src, opts.BatchSize, opts.SampleSize, &report.Source)
+	if err != nil {
+		return nil, fmt.Errorf("count source: %w", err)
+	}
+	if _, err = countRecords(ctx, dst, opts.BatchSize, 0, &report.Target); err != nil {
+		return nil, fmt.Errorf("count target: %w", err)
+	}
+	if opts.DryRun {
+		return &report, nil
+	}
+	if err = verify(ctx, dst, sample, &report); err != nil {
+		return nil, fmt.Errorf("verify: %w", err)
+	}
+	return &report, nil
+}
+
+// copyRecords функция сохраняет страницу записей в целевое хранилище и возвращает количество сохраненных.
+// При пробном запуске записи не сохраняются, а возвращается количество записей со свободными ключами.
+func copyRecords(ctx context.Context, dst storage.RecordStorager, recs []storage.Record, dryRun bool) (int, error) {
+	if !dryRun {
+		return dst.ImportRecords(ctx, recs)
+	}
+	keys := make([]string, len(recs))
+	for i, rec := range recs {
+		keys[i] = rec.Key
+	}
+	existing, err := dst.LookupRecords(ctx, keys)
+	if err != nil {
+		return 0, err
+	}
+	return len(recs) - len(existing), nil
+}
+
+// countRecords функция подсчитывает записи хранилища и возвращает случайную выборку из sampleSize записей.
+func countRecords(ctx context.Context, strg storage.RecordStorager, batchSize, sampleSize int, counts *Counts) ([]storage.Record, error) {
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	sample := make([]storage.Record, 0, sampleSize)
+	after := ""
+	for {
+		recs, err := strg.ExportRecords(ctx, after, batchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(recs) == 0 {
+			return sample, nil
+		}
+		for _, rec := range recs {
+			counts.Total++
+			if rec.Deleted {
+				counts.Deleted++
+			}
+			// выборка резервуаром: каждая запись попадает в выборку с равной вероятностью
+			if len(sample) < sampleSize {
+				sample = append(sample, rec)
+			} else if i := rnd.Intn(counts.Total); i < sampleSize {
+				sample[i] = rec
+			}
+		}
+		after = recs[len(recs)-1].Key
+	}
+}
+
+// verify функция сравнивает выборку записей исходного хранилища с записями целевого.
+func verify(ctx context.Context, dst storage.RecordStorager, sample []storage.Record, report *Report) error {
+	keys := make([]string, len(sample))
+	for i, rec := range sample {
+		keys[i] = rec.Key
+	}
+	found, err := dst.LookupRecords(ctx, keys)
+	if err != nil {
+		return err
+	}
+	byKey := make(map[string]storage.Record, len(found))
+	for _, rec := range found {
+		byKey[rec.Key] = rec
+	}
+	report.Sampled = len(sample)
+	for _, rec := range sample {
+		got, ok := byKey[rec.Key]
+		switch {
+		case !ok:
+			report.Missing = append(report.Missing, rec.Key)
+		case !sameRecord(rec, got):
+			report.Mismatched = append(report.Mismatched, rec.Key)
+		}
+	}
+	return nil
+}
+
+// sameRecord функция сравнивает записи. Время сравнивается с точностью до микросекунды, с которой его хранит PostgreSQL.
+// Время создания, изменения и удаления, отсутствующее в исходной записи, целевое хранилище заполняет при импорте,
+// поэтому оно сравнивается, только если задано в исходной записи.
+func sameRecord(a, b storage.Record) bool {
+	if a.Key != b.Key || a.UserID != b.UserID || a.Value != b.Value || a.Deleted != b.Deleted {
+		return false
+	}
+	return sameTime(a.ExpiresAt, b.ExpiresAt) && keptTime(a.CreatedAt, b.CreatedAt) &&
+		keptTime(a.UpdatedAt, b.UpdatedAt) && keptTime(a.DeletedAt, b.DeletedAt)
+}
+
+// sameTime функция сравнивает время с точностью до микросекунды.
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Truncate(time.Microsecond).Equal(b.Truncate(time.Microsecond))
+}
+
+// keptTime функция проверяет, что время исходной записи сохранено в целевой.
+func keptTime(src, dst *time.Time) bool {
+	return src == nil || sameTime(src, dst)
+}
+
+// loadProgress функция читает состояние прерванного переноса или возвращает начальное состояние.
+func loadProgress(opts Options) (Progress, error) {
+	progress := Progress{Source: opts.Source, Target: opts.Target}
+	if opts.ProgressPath == "" {
+		return progress, nil
+	}
+	data, err := os.ReadFile(opts.ProgressPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return progress, nil
+	}
+	if err != nil {
+		return progress, err
+	}
+	var saved Progress
+	if err = json.Unmarshal(data, &saved); err != nil {
+		return progress, fmt.Errorf("progress file %s: %w", opts.ProgressPath, err)
+	}
+	if saved.Source != opts.Source || saved.Target != opts.Target {
+		return progress, fmt.Errorf("progress file %s belongs to migration from %s to %s", opts.ProgressPath, saved.Source, saved.Target)
+	}
+	return saved, nil
+}
+
+// saveProgress функция атомарно сохраняет состояние переноса в файл.
+func saveProgress(path string, progress Progress) error {
+	if path == "" {
+		return nil
+	}
+	progress.UpdatedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(progress, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err = os.WriteFile(tmpPath, data, 0666); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
@@ -0,0 +1,163 @@
+package transfer
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"shortURL/internal/config"
+	"shortURL/internal/storage"
+)
+
+func newSource(t *testing.T, n int) *storage.MemoryStorage {
+	cfg := &config.Config{KeyLength: 8}
+	src := storage.NewMemoryStorager(storage.NewKeyGenerator(cfg))
+	expiresAt := time.Now().Add(time.Hour)
+	recs := make([]storage.Record, 0, n)
+	for i := 0; i < n; i++ {
+		rec := storage.Record{Key: "key-" + strconv.Itoa(i), UserID: "user" + strconv.Itoa(i%3), Value: "https://go.dev/" + strconv.Itoa(i)}
+		rec.Deleted = i%5 == 0
+		if i%7 == 0 {
+			rec.ExpiresAt = &expiresAt
+		}
+		recs = append(recs, rec)
+	}
+	imported, err := src.ImportRecords(context.Background(), recs)
+	require.NoError(t, err)
+	require.Equal(t, n, imported)
+	return src
+}
+
+func newFileTarget(t *testing.T, path string) *storage.FileStorage {
+	cfg := &config.Config{FileStoragePath: path, KeyLength: 8, FileSync: config.SyncAlways}
+	return storage.NewFileStorager(cfg, storage.NewKeyGenerator(cfg))
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	src := newSource(t, 120)
+	dir := t.TempDir()
+	dst := newFileTarget(t, filepath.Join(dir, "storage.json"))
+	opts := Options{Source: "memory", Target: "file", BatchSize: 50, SampleSize: 200, ProgressPath: filepath.Join(dir, "progress.json")}
+
+	report, err := Run(ctx, src, dst, Options{Source: "memory", Target: "file", BatchSize: 50, DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, 120, report.Copied)
+	assert.Equal(t, Counts{Total: 120, Deleted: 24}, report.Source)
+	assert.Equal(t, Counts{}, report.Target)
+
+	report, err = Run(ctx, src, dst, opts)
+	require.NoError(t, err)
+	assert.True(t, report.OK())
+	assert.Equal(t, 120, report.Copied)
+	assert.Equal(t, report.Source, report.Target)
+	assert.Equal(t, 120, report.Sampled)
+	dst.CloseDB()
+
+	// записи с владельцем, отметкой об удалении и сроком действия восстанавливаются из файла
+	restored := newFileTarget(t, filepath.Join(dir, "storage.json"))
+	want, err := src.ExportRecords(ctx, "", 1000)
+	require.NoError(t, err)
+	got, err := restored.ExportRecords(ctx, "", 1000)
+	require.NoError(t, err)
+	require.Len(t, got, len(want))
+	for i := range want {
+		assert.True(t, sameRecord(want[i], got[i]), want[i].Key)
+	}
+
+	// завершенный перенос не повторяется
+	report, err = Run(ctx, src, restored, opts)
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Read)
+	assert.True(t, report.OK())
+
+	_, err = Run(ctx, src, restored, Options{Source: "memory", Target: "other", ProgressPath: opts.ProgressPath})
+	assert.Error(t, err)
+}
+
+func TestSameRecord(t *testing.T) {
+	createdAt := time.Now()
+	rec := storage.Record{Key: "key", UserID: "user1", Value: "https://go.dev/", CreatedAt: &createdAt, UpdatedAt: &createdAt}
+	assert.True(t, sameRecord(rec, rec))
+
+	// потерянное или измененное время записи обнаруживается при сверке
+	lost := rec
+	lost.CreatedAt = nil
+	assert.False(t, sameRecord(rec, lost))
+	changed := rec
+	updatedAt := createdAt.Add(time.Second)
+	changed.UpdatedAt = &updatedAt
+	assert.False(t, sameRecord(rec, changed))
+	deleted := rec
+	deleted.Deleted, deleted.DeletedAt = true, &updatedAt
+	withoutTime := deleted
+	withoutTime.DeletedAt = nil
+	assert.False(t, sameRecord(deleted, withoutTime))
+
+	// время, отсутствующее в исходной записи, целевое хранилище может заполнить само
+	assert.True(t, sameRecord(lost, rec))
+}
+
+func TestRunResume(t *testing.T) {
+	ctx := context.Background()
+	src := newSource(t, 100)
+	dst := storage.NewMemoryStorager(storage.NewKeyGenerator(&config.Config{KeyLength: 8}))
+	progressPath := filepath.Join(t.TempDir(), "progress.json")
+
+	// прерванный перенос: первые 40 записей уже перенесены
+	first, err := src.ExportRecords(ctx, "", 40)
+	require.NoError(t, err)
+	_, err = dst.ImportRecords(ctx, first)
+	require.NoError(t, err)
+	data, err := json.Marshal(Progress{Source: "src", Target: "dst", LastKey: first[39].Key, Read: 40, Copied: 40})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(progressPath, data, 0666))
+
+	report, err := Run(ctx, src, dst, Options{Source: "src", Target: "dst", BatchSize: 25, ProgressPath: progressPath})
+	require.NoError(t, err)
+	assert.Equal(t, first[39].Key, report.ResumedAt)
+	assert.Equal(t, 60, report.Read)
+	assert.Equal(t, 60, report.Copied)
+	assert.True(t, report.OK())
+
+	data, err = os.ReadFile(progressPath)
+	require.NoError(t, err)
+	var progress Progress
+	require.NoError(t, json.Unmarshal(data, &progress))
+	assert.True(t, progress.Done)
+	assert.Equal(t, 100, progress.Copied)
+}
+
+func TestVerifyMismatch(t *testing.T) {
+	ctx := context.Background()
+	src := newSource(t, 10)
+	dst := storage.NewMemoryStorager(storage.NewKeyGenerator(&config.Config{KeyLength: 8}))
+	recs, err := src.ExportRecords(ctx, "", 10)
+	require.NoError(t, err)
+	recs[1].Value = "https://example.com/"
+	_, err = dst.ImportRecords(ctx, append(recs[:2:2], recs[3:]...))
+	require.NoError(t, err)
+
+	report, err := Run(ctx, src, dst, Options{Source: "src", Target: "dst", SampleSize: 10, DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Copied)
+
+	report = &Report{}
+	require.NoError(t, verify(ctx, dst, mustExport(t, src), report))
+	assert.Equal(t, []string{recs[2].Key}, report.Missing)
+	assert.Equal(t, []string{recs[1].Key}, report.Mismatched)
+	assert.False(t, report.OK())
+}
+
+func mustExport(t *testing.T, strg storage.RecordStorager) []storage.Record {
+	recs, err := strg.ExportRecords(context.Background(), "", 1000)
+	require.NoError(t, err)
+	return recs
+}
@@ -0,0 +1,88 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"shortURL/internal/storage"
+)
+
+// ClickRecorder - структура обработчика, накапливающего переходы по коротким ссылкам
+// и передающего их в хранилище пакетами вне пути перенаправления.
+type ClickRecorder struct {
+	inputCh  chan storage.Click
+	stop     chan struct{}
+	finished chan struct{}
+}
+
+// NewClickRecorder функция создает и возвращает ссылку на обработчик переходов с очередью заданного размера.
+func NewClickRecorder(queue int) *ClickRecorder {
+	return &ClickRecorder{
+		inputCh:  make(chan storage.Click, queue),
+		stop:     make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+}
+
+// Run метод запускает работу обработчика.
+// Накопленные переходы сохраняются при заполнении буфера или по истечении задержки.
+func (c *ClickRecorder) Run(strg storage.Storager, buffer int, delay time.Duration) {
+	go func() {
+		log.Debug().Msg("ClickRecorder started")
+		clicks := make([]storage.Click, 0, buffer)
+		ticker := time.NewTicker(delay)
+		defer ticker.Stop()
+		flush := func() {
+			if len(clicks) == 0 {
+				return
+			}
+			if err := strg.SaveClicks(context.Background(), clicks); err != nil {
+				log.Error().Err(err).Msgf("ClickRecorder SaveClicks err, %d clicks lost", len(clicks))
+			}
+			clicks = make([]storage.Click, 0, buffer)
+		}
+		for {
+			select {
+			case click := <-c.inputCh:
+				clicks = append(clicks, click)
+				if len(clicks) == buffer {
+					flush()
+				}
+			case <-ticker.C:
+				flush()
+			case <-c.stop:
+				for len(c.inputCh) > 0 {
+					clicks = append(clicks, <-c.inputCh)
+				}
+				flush()
+				close(c.finished)
+				log.Debug().Msg("ClickRecorder finished")
+				return
+			}
+		}
+	}()
+}
+
+// Add метод ставит переход в очередь на сохранение без ожидания.
+// При переполненной очереди переход отбрасывается, чтобы не задерживать перенаправление.
+func (c *ClickRecorder) Add(click storage.Click) {
+	select {
+	case <-c.stop:
+		return
+	default:
+	}
+	select {
+	case c.inputCh <- click:
+	default:
+		log.Warn().Msgf("ClickRecorder queue is full, click on %s dropped", click.Key)
+	}
+}
+
+// Stop метод сохраняет оставшиеся переходы и останавливает работу обработчика.
+func (c *ClickRecorder) Stop() {
+	close(c.stop)
+	<-c.finished
+	log.Info().Msg("click recorder stopped")
+}
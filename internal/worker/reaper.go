@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"shortURL/internal/storage"
+)
+
+// Reaper - структура обработчика, периодически помечающего удаленными адреса с истекшим сроком действия
+// и окончательно удаляющего адреса, срок хранения которых после удаления истек.
+type Reaper struct {
+	stop     chan struct{}
+	finished chan struct{}
+}
+
+// NewReaper функция создает и возвращает ссылку на обработчик просроченных адресов.
+func NewReaper() *Reaper {
+	return &Reaper{
+		stop:     make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+}
+
+// Run метод запускает работу обработчика с заданным интервалом проверки.
+// Удаленные адреса хранятся retention, нулевое значение отключает окончательное удаление.
+func (r *Reaper) Run(strg storage.Storager, interval, retention time.Duration) {
+	go func() {
+		log.Debug().Msg("ExpiringReaper started")
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stop:
+				close(r.finished)
+				log.Debug().Msg("ExpiringReaper finished")
+				return
+			case now := <-ticker.C:
+				n, err := strg.ExpireURLs(context.Background(), now)
+				if err != nil {
+					log.Error().Err(err).Msg("ExpiringReaper ExpireURLs err")
+					continue
+				}
+				if n > 0 {
+					log.Debug().Msgf("ExpiringReaper expired %d URLs", n)
+				}
+				if retention <= 0 {
+					continue
+				}
+				n, err = strg.PurgeDeleted(context.Background(), now.Add(-retention))
+				if err != nil {
+					log.Error().Err(err).Msg("ExpiringReaper PurgeDeleted err")
+					continue
+				}
+				if n > 0 {
+					log.Info().Msgf("ExpiringReaper purged %d deleted URLs", n)
+				}
+			}
+		}
+	}()
+}
+
+// Stop метод останавливает работу обработчика.
+func (r *Reaper) Stop() {
+	close(r.stop)
+	<-r.finished
+	log.Info().Msg("reaper stopped")
+}
@@ -34,55 +34,131 @@ func NewWorker() *Worker {
 	return &worker
 }
 
+// Параметры повтора сохранения пакетов на удаление при ошибке хранилища.
+// Очередь повтора ограничена maxRetryBatches пакетами, при переполнении отбрасываются самые старые.
+const (
+	retryMinDelay   = 100 * time.Millisecond
+	retryMaxDelay   = 30 * time.Second
+	stopTimeout     = 10 * time.Second
+	maxRetryBatches = 1024
+)
+
+// deletion - пакет адресов на удаление, ожидающий сохранения в хранилище.
+type deletion struct {
+	keys []string
+	ids  []string
+}
+
 // Run метод запускает работу обработчика
+// Накопленные адреса передаются в хранилище при заполнении буфера или по истечении задержки.
+// Пакеты, которые не удалось сохранить, остаются в очереди и передаются повторно с растущей задержкой.
+// Отброшенные пакеты выводятся в журнал с ключами и пользователями, чтобы удаление можно было повторить.
 func (w *Worker) Run(strg storage.Storager, buffer int, delay time.Duration) {
 
 	go func() {
-		//update
 		log.Debug().Msg("DeletingWorker started")
-		userIDbuf := make([]string, 0, buffer)
-		keyBuf := make([]string, 0, buffer)
+		current := deletion{keys: make([]string, 0, buffer), ids: make([]string, 0, buffer)}
+		queue := make([]deletion, 0)
+		ticker := time.NewTicker(delay)
+		defer ticker.Stop()
+		backoff := retryMinDelay
+		var retryAt time.Time
+		// flush переносит текущий пакет в очередь и сохраняет очередь, если не идет ожидание повтора
+		flush := func(now time.Time) {
+			if len(current.keys) > 0 {
+				queue = append(queue, current)
+				current = deletion{keys: make([]string, 0, buffer), ids: make([]string, 0, buffer)}
+			}
+			if n := len(queue) - maxRetryBatches; n > 0 {
+				logDropped("DeletingWorker retry queue is full", queue[:n])
+				queue = append(queue[:0], queue[n:]...)
+			}
+			if len(queue) == 0 || now.Before(retryAt) {
+				return
+			}
+			var err error
+			queue, err = markDeleted(strg, queue)
+			if err != nil {
+				log.Error().Err(err).Msgf("DeletingWorker MarkDeleted err, %d batches will be retried in %s", len(queue), backoff)
+				retryAt = now.Add(backoff)
+				backoff = nextBackoff(backoff)
+				return
+			}
+			backoff = retryMinDelay
+			retryAt = time.Time{}
+		}
 		for {
-			ctx, timeout := context.WithTimeout(context.Background(), delay)
-			defer timeout()
-			userIDbuf = userIDbuf[:0]
-			keyBuf = keyBuf[:0]
-		loop:
-			for {
-				select {
-				case toDelele, ok := <-w.InputCh:
-					if !ok {
-						if len(userIDbuf) > 0 {
-							strg.MarkDeleted(keyBuf, userIDbuf)
-						}
-						close(w.finished)
-						log.Debug().Msg("DeletingWorker finished")
-						return
-					}
-					for _, key := range toDelele.Keys {
-						userIDbuf = append(userIDbuf, toDelele.ID)
-						keyBuf = append(keyBuf, key)
-						//flush
-						if len(userIDbuf) == buffer {
-							log.Debug().Msg("DeletingWorker flush")
-							strg.MarkDeleted(keyBuf, userIDbuf)
-							userIDbuf = userIDbuf[:0]
-							keyBuf = keyBuf[:0]
-						}
-					}
-				case <-ctx.Done():
-					if len(userIDbuf) > 0 {
-						log.Debug().Msg("DeletingWorker flush from cancel context")
-						strg.MarkDeleted(keyBuf, userIDbuf)
+			select {
+			case toDelete, ok := <-w.InputCh:
+				if !ok {
+					flush(time.Now())
+					w.drain(strg, queue)
+					close(w.finished)
+					log.Debug().Msg("DeletingWorker finished")
+					return
+				}
+				for _, key := range toDelete.Keys {
+					current.ids = append(current.ids, toDelete.ID)
+					current.keys = append(current.keys, key)
+					if len(current.keys) == buffer {
+						log.Debug().Msg("DeletingWorker flush")
+						flush(time.Now())
 					}
-					break loop
 				}
+			case now := <-ticker.C:
+				flush(now)
 			}
 		}
 	}()
 
 }
 
+// drain метод при остановке обработчика повторяет сохранение оставшихся пакетов до истечения stopTimeout.
+func (w *Worker) drain(strg storage.Storager, queue []deletion) {
+	deadline := time.Now().Add(stopTimeout)
+	backoff := retryMinDelay
+	for len(queue) > 0 {
+		var err error
+		queue, err = markDeleted(strg, queue)
+		if err == nil {
+			return
+		}
+		if time.Now().Add(backoff).After(deadline) {
+			log.Error().Err(err).Msgf("DeletingWorker stopped, %d batches lost", len(queue))
+			logDropped("DeletingWorker stopped", queue)
+			return
+		}
+		time.Sleep(backoff)
+		backoff = nextBackoff(backoff)
+	}
+}
+
+// markDeleted функция передает пакеты очереди в хранилище по порядку и возвращает несохраненные пакеты.
+func markDeleted(strg storage.Storager, queue []deletion) ([]deletion, error) {
+	for i, d := range queue {
+		if err := strg.MarkDeleted(context.Background(), d.keys, d.ids); err != nil {
+			return queue[i:], err
+		}
+	}
+	return queue[:0], nil
+}
+
+// logDropped функция выводит в журнал отброшенные пакеты на удаление.
+func logDropped(reason string, batches []deletion) {
+	for _, d := range batches {
+		log.Error().Strs("keys", d.keys).Strs("user_ids", d.ids).Msgf("%s, deletion batch dropped", reason)
+	}
+}
+
+// nextBackoff функция возвращает удвоенную задержку повтора, не превышающую retryMaxDelay.
+func nextBackoff(backoff time.Duration) time.Duration {
+	backoff *= 2
+	if backoff > retryMaxDelay {
+		return retryMaxDelay
+	}
+	return backoff
+}
+
 // Stop метод останавливает работу обработчика
 func (w *Worker) Stop() {
 	w.Closed = true
@@ -0,0 +1,71 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"shortURL/internal/storage"
+)
+
+// flakyStorage возвращает ошибку на первые failures вызовов MarkDeleted и запоминает удаленные адреса.
+type flakyStorage struct {
+	storage.Storager
+	failures int
+	calls    int
+	deleted  []string
+	sync.Mutex
+}
+
+func (s *flakyStorage) MarkDeleted(ctx context.Context, keys []string, ids []string) error {
+	s.Lock()
+	defer s.Unlock()
+	s.calls++
+	if s.calls <= s.failures {
+		return errors.New("database is unavailable")
+	}
+	s.deleted = append(s.deleted, keys...)
+	return nil
+}
+
+func TestWorkerRetry(t *testing.T) {
+	strg := &flakyStorage{failures: 2}
+	w := NewWorker()
+	w.Run(strg, 10, 10*time.Millisecond)
+	assert.NoError(t, w.Add([]string{"a1", "a2"}, "user1"))
+
+	assert.Eventually(t, func() bool {
+		strg.Lock()
+		defer strg.Unlock()
+		return len(strg.deleted) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.NoError(t, w.Add([]string{"a3"}, "user1"))
+	w.Stop()
+
+	assert.Equal(t, []string{"a1", "a2", "a3"}, strg.deleted)
+	assert.Equal(t, 4, strg.calls)
+	assert.ErrorIs(t, w.Add([]string{"a4"}, "user1"), storage.ErrUnavailable)
+}
+
+func TestWorkerRetryLimit(t *testing.T) {
+	strg := &flakyStorage{failures: 1 << 30}
+	w := NewWorker()
+	w.Run(strg, 1, time.Millisecond)
+	keys := make([]string, 0, maxRetryBatches+10)
+	for i := 0; i < maxRetryBatches+10; i++ {
+		keys = append(keys, "k"+strconv.Itoa(i))
+		assert.NoError(t, w.Add([]string{keys[i]}, "user1"))
+	}
+	// после восстановления хранилища сохраняются только последние maxRetryBatches пакетов
+	strg.Lock()
+	strg.failures = 0
+	strg.Unlock()
+	w.Stop()
+
+	assert.Equal(t, keys[10:], strg.deleted)
+}